@@ -7,13 +7,47 @@ import (
 	"crypto/cipher"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"hash"
+	"io"
 
 	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/stream"
 	"go.dedis.ch/kyber/v4/util/random"
+	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
 )
 
+// AEAD identifies the authenticated encryption scheme used to encrypt the
+// message.
+type AEAD byte
+
+const (
+	// AES256GCM is AES-256 in Galois/Counter Mode, the default.
+	AES256GCM AEAD = iota
+	// ChaCha20Poly1305 is ChaCha20-Poly1305 as defined in RFC 8439.
+	ChaCha20Poly1305
+)
+
+// Options are the parameters of the encryption. The zero value gives the
+// format of Encrypt with SHA256.
+type Options struct {
+	// Hash is the hash function of the key derivation, SHA256 if nil.
+	Hash func() hash.Hash
+	// AEAD is the symmetric encryption scheme.
+	AEAD AEAD
+	// AssociatedData is authenticated but not encrypted, and must be given
+	// again for decryption.
+	AssociatedData []byte
+}
+
+const keyLen = 32
+const nonceLen = 12
+
+// streamInfo is the HKDF info of the key of the streaming mode, which keeps
+// its keys separate from the ones of the other mode.
+var streamInfo = []byte("kyber-ecies-stream-v1")
+
 // Encrypt first computes a shared DH key using the given public key, then
 // HKDF-derives a symmetric key (and nonce) from that, and finally uses these
 // values to encrypt the given message via AES-GCM. If the hash input parameter
@@ -21,9 +55,13 @@ import (
 // containing the ephemeral elliptic curve point of the DH key exchange and the
 // ciphertext or an error.
 func Encrypt(group kyber.Group, public kyber.Point, message []byte, hash func() hash.Hash) ([]byte, error) {
-	if hash == nil {
-		hash = sha256.New
-	}
+	return EncryptWithOptions(group, public, message, &Options{Hash: hash})
+}
+
+// EncryptWithOptions is like Encrypt, with the given options. A nil opts is
+// the same as the zero value.
+func EncryptWithOptions(group kyber.Group, public kyber.Point, message []byte, opts *Options) ([]byte, error) {
+	opts = opts.orDefault()
 
 	// Generate an ephemeral elliptic curve scalar and point
 	r := group.Scalar().Pick(random.New())
@@ -34,26 +72,21 @@ func Encrypt(group kyber.Group, public kyber.Point, message []byte, hash func()
 
 	// Derive symmetric key and nonce via HKDF (NOTE: Since we use a new
 	// ephemeral key for every ECIES encryption and thus have a fresh
-	// HKDF-derived key for AES-GCM, the nonce for AES-GCM can be an arbitrary
+	// HKDF-derived key for the AEAD, the nonce can be an arbitrary
 	// (even static) value. We derive it here simply via HKDF as well.)
-	keyNonceLen := 32 + 12
-	buf, err := deriveKey(hash, dh, keyNonceLen)
+	buf, err := deriveKey(opts.Hash, dh, nil, nil, keyLen+nonceLen)
 	if err != nil {
 		return nil, err
 	}
-	key := buf[:32]
-	nonce := buf[32:keyNonceLen]
+	key := buf[:keyLen]
+	nonce := buf[keyLen:]
 
-	// Encrypt message using AES-GCM
-	aes, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, err
-	}
-	aesgcm, err := cipher.NewGCM(aes)
+	// Encrypt message using the AEAD
+	aead, err := newAEAD(opts.AEAD, key)
 	if err != nil {
 		return nil, err
 	}
-	c := aesgcm.Seal(nil, nonce, message, nil)
+	c := aead.Seal(nil, nonce, message, opts.AssociatedData)
 
 	// Serialize ephemeral elliptic curve point and ciphertext
 	var ctx bytes.Buffer
@@ -75,9 +108,13 @@ func Encrypt(group kyber.Group, public kyber.Point, message []byte, hash func()
 // input parameter is nil then SHA256 is used as a default. Decrypt returns the
 // plaintext message or an error.
 func Decrypt(group kyber.Group, private kyber.Scalar, ctx []byte, hash func() hash.Hash) ([]byte, error) {
-	if hash == nil {
-		hash = sha256.New
-	}
+	return DecryptWithOptions(group, private, ctx, &Options{Hash: hash})
+}
+
+// DecryptWithOptions is like Decrypt, with the options given for the
+// encryption.
+func DecryptWithOptions(group kyber.Group, private kyber.Scalar, ctx []byte, opts *Options) ([]byte, error) {
+	opts = opts.orDefault()
 
 	// Reconstruct the ephemeral elliptic curve point
 	R := group.Point()
@@ -91,32 +128,112 @@ func Decrypt(group kyber.Group, private kyber.Scalar, ctx []byte, hash func() ha
 
 	// Compute shared DH key and derive the symmetric key and nonce via HKDF
 	dh := group.Point().Mul(private, R)
-	keyNonceLen := 32 + 12
-	buf, err := deriveKey(hash, dh, keyNonceLen)
+	buf, err := deriveKey(opts.Hash, dh, nil, nil, keyLen+nonceLen)
+	if err != nil {
+		return nil, err
+	}
+	key := buf[:keyLen]
+	nonce := buf[keyLen:]
+
+	// Decrypt message using the AEAD
+	aead, err := newAEAD(opts.AEAD, key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, ctx[l:], opts.AssociatedData)
+}
+
+// NewEncryptWriter returns a writer encrypting the data written to it to the
+// public key and writing the result to w, for payloads too large to be held
+// in memory. The ephemeral point is written first, followed by the data
+// encrypted in chunks with the STREAM construction. The writer must be closed
+// to complete the encryption; this doesn't close w. A nil opts is the same as
+// the zero value.
+func NewEncryptWriter(group kyber.Group, public kyber.Point, w io.Writer, opts *Options) (io.WriteCloser, error) {
+	opts = opts.orDefault()
+
+	r := group.Scalar().Pick(random.New())
+	R := group.Point().Mul(r, nil)
+	dh := group.Point().Mul(r, public)
+	aead, err := streamAEAD(opts, R, dh)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := R.MarshalTo(w); err != nil {
+		return nil, err
+	}
+	return stream.NewWriter(aead, opts.AssociatedData, w)
+}
+
+// NewDecryptReader returns a reader decrypting the data written by a writer
+// of NewEncryptWriter, with the options given for the encryption. The
+// decrypted data is only returned once authenticated, and io.EOF is only
+// returned at the authenticated end of the data.
+func NewDecryptReader(group kyber.Group, private kyber.Scalar, r io.Reader, opts *Options) (io.Reader, error) {
+	opts = opts.orDefault()
+
+	buf := make([]byte, group.PointLen())
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("invalid ecies stream: %w", err)
+	}
+	R := group.Point()
+	if err := R.UnmarshalBinary(buf); err != nil {
+		return nil, err
+	}
+	dh := group.Point().Mul(private, R)
+	aead, err := streamAEAD(opts, R, dh)
 	if err != nil {
 		return nil, err
 	}
-	key := buf[:32]
-	nonce := buf[32:keyNonceLen]
+	return stream.NewReader(aead, opts.AssociatedData, r)
+}
+
+func (o *Options) orDefault() *Options {
+	opts := Options{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.Hash == nil {
+		opts.Hash = sha256.New
+	}
+	return &opts
+}
 
-	// Decrypt message using AES-GCM
-	aes, err := aes.NewCipher(key)
+// streamAEAD returns the AEAD of the streaming mode, whose key is bound to
+// the ephemeral point.
+func streamAEAD(opts *Options, R, dh kyber.Point) (cipher.AEAD, error) {
+	salt, err := R.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	aesgcm, err := cipher.NewGCM(aes)
+	key, err := deriveKey(opts.Hash, dh, salt, streamInfo, keyLen)
 	if err != nil {
 		return nil, err
 	}
-	return aesgcm.Open(nil, nonce, ctx[l:], nil)
+	return newAEAD(opts.AEAD, key)
+}
+
+func newAEAD(kind AEAD, key []byte) (cipher.AEAD, error) {
+	switch kind {
+	case AES256GCM:
+		aes, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(aes)
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	default:
+		return nil, fmt.Errorf("ecies: unknown AEAD %d", kind)
+	}
 }
 
-func deriveKey(hash func() hash.Hash, dh kyber.Point, l int) ([]byte, error) {
+func deriveKey(hash func() hash.Hash, dh kyber.Point, salt, info []byte, l int) ([]byte, error) {
 	dhb, err := dh.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	hkdf := hkdf.New(hash, dhb, nil, nil)
+	hkdf := hkdf.New(hash, dhb, salt, info)
 	key := make([]byte, l)
 	n, err := hkdf.Read(key)
 	if err != nil {
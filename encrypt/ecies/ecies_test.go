@@ -1,11 +1,15 @@
 package ecies
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha512"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/internal/stream"
 	"go.dedis.ch/kyber/v4/util/random"
 )
 
@@ -46,6 +50,81 @@ func TestECIESFailCiphertext(t *testing.T) {
 	require.NotNil(t, err)
 }
 
+func TestECIESOptions(t *testing.T) {
+	message := []byte("Hello ECIES")
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+
+	// the zero options are the default format
+	ciphertext, err := EncryptWithOptions(suite, public, message, nil)
+	require.NoError(t, err)
+	plaintext, err := Decrypt(suite, private, ciphertext, nil)
+	require.NoError(t, err)
+	require.Equal(t, message, plaintext)
+
+	for _, aead := range []AEAD{AES256GCM, ChaCha20Poly1305} {
+		opts := &Options{Hash: sha512.New, AEAD: aead, AssociatedData: []byte("header")}
+		ciphertext, err := EncryptWithOptions(suite, public, message, opts)
+		require.NoError(t, err)
+		plaintext, err := DecryptWithOptions(suite, private, ciphertext, opts)
+		require.NoError(t, err)
+		require.Equal(t, message, plaintext)
+
+		// other associated data, AEAD or hash
+		_, err = DecryptWithOptions(suite, private, ciphertext, &Options{Hash: sha512.New, AEAD: aead})
+		require.Error(t, err)
+		_, err = DecryptWithOptions(suite, private, ciphertext, &Options{Hash: sha512.New,
+			AEAD: 1 - aead, AssociatedData: opts.AssociatedData})
+		require.Error(t, err)
+		_, err = DecryptWithOptions(suite, private, ciphertext, &Options{AEAD: aead,
+			AssociatedData: opts.AssociatedData})
+		require.Error(t, err)
+	}
+
+	_, err = EncryptWithOptions(suite, public, message, &Options{AEAD: 42})
+	require.Error(t, err)
+}
+
+func TestECIESStream(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	private := suite.Scalar().Pick(random.New())
+	public := suite.Point().Mul(private, nil)
+	message := make([]byte, 2*stream.ChunkSize+100)
+	_, _ = rand.Read(message)
+
+	for _, opts := range []*Options{nil, {AEAD: ChaCha20Poly1305, AssociatedData: []byte("header")}} {
+		var buf bytes.Buffer
+		w, err := NewEncryptWriter(suite, public, &buf, opts)
+		require.NoError(t, err)
+		_, err = io.Copy(w, bytes.NewReader(message))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		r, err := NewDecryptReader(suite, private, bytes.NewReader(buf.Bytes()), opts)
+		require.NoError(t, err)
+		plaintext, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, message, plaintext)
+
+		// truncated stream
+		ciphertext := buf.Bytes()
+		r, err = NewDecryptReader(suite, private, bytes.NewReader(ciphertext[:len(ciphertext)-200]), opts)
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.Error(t, err)
+
+		// wrong key
+		r, err = NewDecryptReader(suite, suite.Scalar().Pick(random.New()), bytes.NewReader(ciphertext), opts)
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.Error(t, err)
+	}
+
+	_, err := NewDecryptReader(suite, private, bytes.NewReader([]byte{1, 2}), nil)
+	require.Error(t, err)
+}
+
 func BenchmarkECIES(b *testing.B) {
 	message := make([]byte, 100_000)
 	_, _ = rand.Read(message)
@@ -0,0 +1,69 @@
+package elgamal
+
+import (
+	"errors"
+	"math"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// ErrOutOfRange is returned when a discrete logarithm is not within the range
+// of a DLog table.
+var ErrOutOfRange = errors.New("elgamal: discrete logarithm out of range")
+
+// DLog computes discrete logarithms in the range [0, max] to the base point
+// G of a group with the baby-step giant-step algorithm. The baby steps jG for
+// j < m, where m is about the square root of max, are computed once by
+// NewDLog, so that Solve only takes up to max/m giant steps. A table can be
+// used concurrently.
+type DLog struct {
+	g     kyber.Group
+	max   uint64
+	m     uint64
+	baby  map[string]uint64
+	giant kyber.Point // -mG
+}
+
+// NewDLog returns the table of the discrete logarithms in the range
+// [0, max]. Its precomputation and size are in O(sqrt(max)).
+func NewDLog(g kyber.Group, max uint64) (*DLog, error) {
+	m := uint64(math.Ceil(math.Sqrt(float64(max) + 1)))
+	d := &DLog{g: g, max: max, m: m, baby: make(map[string]uint64, m)}
+	P := g.Point().Null()
+	G := g.Point().Base()
+	for j := range m {
+		key, err := P.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		d.baby[string(key)] = j
+		P.Add(P, G)
+	}
+	// P is mG after the loop
+	d.giant = P.Neg(P)
+	return d, nil
+}
+
+// Max returns the largest discrete logarithm of the table.
+func (d *DLog) Max() uint64 {
+	return d.max
+}
+
+// Solve returns m in the range of the table such that P = mG.
+func (d *DLog) Solve(P kyber.Point) (uint64, error) {
+	Q := P.Clone()
+	for i := uint64(0); i <= d.max/d.m; i++ {
+		key, err := Q.MarshalBinary()
+		if err != nil {
+			return 0, err
+		}
+		if j, ok := d.baby[string(key)]; ok {
+			if v := i*d.m + j; v <= d.max {
+				return v, nil
+			}
+			break
+		}
+		Q.Add(Q, d.giant)
+	}
+	return 0, ErrOutOfRange
+}
@@ -0,0 +1,36 @@
+package elgamal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDLog(t *testing.T) {
+	for _, max := range []uint64{0, 1, 15, 16, 17, 1000} {
+		table, err := NewDLog(suite, max)
+		require.NoError(t, err)
+		require.Equal(t, max, table.Max())
+		for v := range max + 1 {
+			P := suite.Point().Mul(suite.Scalar().SetInt64(int64(v)), nil)
+			m, err := table.Solve(P)
+			require.NoError(t, err)
+			require.Equal(t, v, m)
+		}
+		for _, v := range []int64{int64(max) + 1, int64(max) + 100, -1} {
+			P := suite.Point().Mul(suite.Scalar().SetInt64(v), nil)
+			_, err := table.Solve(P)
+			require.ErrorIs(t, err, ErrOutOfRange)
+		}
+	}
+}
+
+func BenchmarkDLog(b *testing.B) {
+	table, err := NewDLog(suite, 1<<20)
+	require.NoError(b, err)
+	P := suite.Point().Mul(suite.Scalar().SetInt64(1<<20-1), nil)
+	for b.Loop() {
+		_, err := table.Solve(P)
+		require.NoError(b, err)
+	}
+}
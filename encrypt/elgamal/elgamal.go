@@ -0,0 +1,181 @@
+// Package elgamal implements ElGamal encryption on kyber groups. A ciphertext
+// of a point M under the public key X = xG is the pair (K, C) = (rG, M + rX)
+// for a random scalar r.
+//
+// Points are encrypted with Encrypt, and scalars m with EncryptExp, which
+// encrypts the point mG: such ciphertexts are additively homomorphic, so that
+// their sum decrypts to the sum of the messages, but their decryption needs
+// the discrete logarithm of mG, which a DLog table computes for small values.
+//
+// Ciphertexts can be re-randomized, which gives a new ciphertext of the same
+// message that can't be linked to the original one, and re-encrypted to
+// another public key by the owner of the private key. The owner of the
+// private key can also prove that a ciphertext decrypts to a message, with a
+// DLEQ proof from package proof/dleq.
+package elgamal
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/wire"
+	"go.dedis.ch/kyber/v4/proof/dleq"
+)
+
+// Suite describes the functionalities needed by this package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// ErrInvalidDecryption is returned when the proof of a decryption doesn't
+// verify.
+var ErrInvalidDecryption = errors.New("elgamal: invalid decryption proof")
+
+// Ciphertext is an ElGamal ciphertext.
+type Ciphertext struct {
+	K kyber.Point // Ephemeral key rG
+	C kyber.Point // Encrypted message M + rX
+}
+
+// Encrypt encrypts the point M to the public key.
+func Encrypt(suite Suite, public, M kyber.Point) *Ciphertext {
+	return EncryptWith(suite, public, M, suite.Scalar().Pick(suite.RandomStream()))
+}
+
+// EncryptWith encrypts the point M to the public key with the randomness r,
+// which must be secret and never be used again.
+func EncryptWith(g kyber.Group, public, M kyber.Point, r kyber.Scalar) *Ciphertext {
+	C := g.Point().Mul(r, public)
+	return &Ciphertext{
+		K: g.Point().Mul(r, nil),
+		C: C.Add(C, M),
+	}
+}
+
+// EncryptExp encrypts the point mG to the public key, where G is the base
+// point of the group.
+func EncryptExp(suite Suite, public kyber.Point, m kyber.Scalar) *Ciphertext {
+	return Encrypt(suite, public, suite.Point().Mul(m, nil))
+}
+
+// Decrypt returns the point encrypted by the ciphertext.
+func (c *Ciphertext) Decrypt(g kyber.Group, private kyber.Scalar) kyber.Point {
+	S := g.Point().Mul(private, c.K)
+	return S.Sub(c.C, S)
+}
+
+// DecryptExp returns the scalar m encrypted by EncryptExp, which must be
+// within the range of the table.
+func (c *Ciphertext) DecryptExp(g kyber.Group, private kyber.Scalar, table *DLog) (uint64, error) {
+	return table.Solve(c.Decrypt(g, private))
+}
+
+// Add sets c to the sum of a and b, which encrypts the sum of their
+// messages, and returns c.
+func (c *Ciphertext) Add(a, b *Ciphertext) *Ciphertext {
+	K := a.K.Clone().Add(a.K, b.K)
+	C := a.C.Clone().Add(a.C, b.C)
+	c.K, c.C = K, C
+	return c
+}
+
+// Sub sets c to the difference of a and b, which encrypts the difference of
+// their messages, and returns c.
+func (c *Ciphertext) Sub(a, b *Ciphertext) *Ciphertext {
+	K := a.K.Clone().Sub(a.K, b.K)
+	C := a.C.Clone().Sub(a.C, b.C)
+	c.K, c.C = K, C
+	return c
+}
+
+// Mul sets c to the product of a by the scalar s, which encrypts the product
+// of its message by s, and returns c.
+func (c *Ciphertext) Mul(s kyber.Scalar, a *Ciphertext) *Ciphertext {
+	K := a.K.Clone().Mul(s, a.K)
+	C := a.C.Clone().Mul(s, a.C)
+	c.K, c.C = K, C
+	return c
+}
+
+// Equal returns whether the two ciphertexts are equal.
+func (c *Ciphertext) Equal(c2 *Ciphertext) bool {
+	return c.K.Equal(c2.K) && c.C.Equal(c2.C)
+}
+
+// Clone returns a copy of the ciphertext.
+func (c *Ciphertext) Clone() *Ciphertext {
+	return &Ciphertext{K: c.K.Clone(), C: c.C.Clone()}
+}
+
+// Rerandomize returns a new ciphertext of the message of c under the same
+// public key, which can't be linked to c without the private key.
+func Rerandomize(suite Suite, public kyber.Point, c *Ciphertext) *Ciphertext {
+	return RerandomizeWith(suite, public, c, suite.Scalar().Pick(suite.RandomStream()))
+}
+
+// RerandomizeWith returns c added to an encryption of the identity with the
+// randomness r, which must be secret and never be used again.
+func RerandomizeWith(g kyber.Group, public kyber.Point, c *Ciphertext, r kyber.Scalar) *Ciphertext {
+	zero := EncryptWith(g, public, g.Point().Null(), r)
+	return zero.Add(c, zero)
+}
+
+// ReEncrypt returns a ciphertext of the message of c under the public key
+// target, given the private key c is encrypted to. The message isn't
+// computed along the way.
+func ReEncrypt(suite Suite, private kyber.Scalar, target kyber.Point, c *Ciphertext) *Ciphertext {
+	r := suite.Scalar().Pick(suite.RandomStream())
+	C := suite.Point().Mul(private, c.K)
+	C.Sub(c.C, C)
+	C.Add(C, suite.Point().Mul(r, target))
+	return &Ciphertext{K: suite.Point().Mul(r, nil), C: C}
+}
+
+// ProveDecryption decrypts the ciphertext and returns the message along with
+// a proof that it is the decryption of c with the private key of the public
+// key xG, i.e. that log_G(xG) == log_K(C - M).
+func ProveDecryption(suite Suite, private kyber.Scalar, c *Ciphertext) (kyber.Point, *dleq.Proof, error) {
+	proof, _, S, err := dleq.NewDLEQProof(suite, suite.Point().Base(), c.K, private)
+	if err != nil {
+		return nil, nil, err
+	}
+	return S.Sub(c.C, S), proof, nil
+}
+
+// VerifyDecryption checks the proof that M is the decryption of c with the
+// private key of the public key.
+func VerifyDecryption(suite Suite, public kyber.Point, c *Ciphertext, M kyber.Point, proof *dleq.Proof) error {
+	if proof == nil || proof.C == nil || proof.R == nil || proof.VG == nil || proof.VH == nil {
+		return fmt.Errorf("%w: incomplete", ErrInvalidDecryption)
+	}
+	S := suite.Point().Sub(c.C, M)
+	if err := proof.Verify(suite, suite.Point().Base(), c.K, public, S); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidDecryption, err)
+	}
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the ciphertext.
+func (c *Ciphertext) Marshal() ([]byte, error) {
+	e := wire.NewEncoder(wire.TagElGamalCiphertext)
+	e.Point(c.K)
+	e.Point(c.C)
+	return e.Finish()
+}
+
+// Unmarshal decodes a ciphertext encoded by Marshal with points of the
+// group g.
+func (c *Ciphertext) Unmarshal(data []byte, g kyber.Group) error {
+	d := wire.NewDecoder(data, wire.TagElGamalCiphertext)
+	K := d.Point(g)
+	C := d.Point(g)
+	if err := d.Finish(); err != nil {
+		return err
+	}
+	*c = Ciphertext{K: K, C: C}
+	return nil
+}
@@ -0,0 +1,107 @@
+package elgamal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/internal/wire"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+func TestEncrypt(t *testing.T) {
+	x := suite.Scalar().Pick(suite.RandomStream())
+	X := suite.Point().Mul(x, nil)
+	M := suite.Point().Embed([]byte("Hello World"), suite.RandomStream())
+
+	c := Encrypt(suite, X, M)
+	require.True(t, M.Equal(c.Decrypt(suite, x)))
+
+	// re-randomized ciphertexts can't be linked but decrypt the same
+	c2 := Rerandomize(suite, X, c)
+	require.False(t, c2.K.Equal(c.K))
+	require.False(t, c2.C.Equal(c.C))
+	require.True(t, M.Equal(c2.Decrypt(suite, x)))
+
+	// re-encrypted to another key
+	y := suite.Scalar().Pick(suite.RandomStream())
+	Y := suite.Point().Mul(y, nil)
+	c3 := ReEncrypt(suite, x, Y, c)
+	require.True(t, M.Equal(c3.Decrypt(suite, y)))
+	require.False(t, M.Equal(c3.Decrypt(suite, x)))
+
+	data, err := c.Decrypt(suite, x).Data()
+	require.NoError(t, err)
+	require.Equal(t, []byte("Hello World"), data)
+}
+
+func TestHomomorphic(t *testing.T) {
+	x := suite.Scalar().Pick(suite.RandomStream())
+	X := suite.Point().Mul(x, nil)
+	table, err := NewDLog(suite, 1000)
+	require.NoError(t, err)
+
+	a := EncryptExp(suite, X, suite.Scalar().SetInt64(20))
+	b := EncryptExp(suite, X, suite.Scalar().SetInt64(22))
+	sum := new(Ciphertext).Add(a, b)
+	m, err := sum.DecryptExp(suite, x, table)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), m)
+
+	diff := new(Ciphertext).Sub(b, a)
+	m, err = diff.DecryptExp(suite, x, table)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), m)
+
+	prod := new(Ciphertext).Mul(suite.Scalar().SetInt64(10), sum)
+	m, err = prod.DecryptExp(suite, x, table)
+	require.NoError(t, err)
+	require.Equal(t, uint64(420), m)
+
+	// in place, the operands are left unchanged
+	saved := a.Clone()
+	a.Add(a, a)
+	require.False(t, a.Equal(saved))
+	m, err = a.DecryptExp(suite, x, table)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), m)
+
+	_, err = new(Ciphertext).Mul(suite.Scalar().SetInt64(3), prod).DecryptExp(suite, x, table)
+	require.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestDecryptionProof(t *testing.T) {
+	x := suite.Scalar().Pick(suite.RandomStream())
+	X := suite.Point().Mul(x, nil)
+	M := suite.Point().Pick(suite.RandomStream())
+	c := Encrypt(suite, X, M)
+
+	D, proof, err := ProveDecryption(suite, x, c)
+	require.NoError(t, err)
+	require.True(t, M.Equal(D))
+	require.NoError(t, VerifyDecryption(suite, X, c, D, proof))
+
+	// wrong message, key or ciphertext
+	other := suite.Point().Pick(suite.RandomStream())
+	require.ErrorIs(t, VerifyDecryption(suite, X, c, other, proof), ErrInvalidDecryption)
+	require.ErrorIs(t, VerifyDecryption(suite, other, c, D, proof), ErrInvalidDecryption)
+	require.ErrorIs(t, VerifyDecryption(suite, X, Rerandomize(suite, X, c), D, proof), ErrInvalidDecryption)
+	proof.R = nil
+	require.ErrorIs(t, VerifyDecryption(suite, X, c, D, proof), ErrInvalidDecryption)
+}
+
+func TestMarshal(t *testing.T) {
+	X := suite.Point().Pick(suite.RandomStream())
+	c := Encrypt(suite, X, suite.Point().Pick(suite.RandomStream()))
+	buf, err := c.Marshal()
+	require.NoError(t, err)
+	decoded := new(Ciphertext)
+	require.NoError(t, decoded.Unmarshal(buf, suite))
+	require.True(t, c.Equal(decoded))
+
+	for i := range buf {
+		require.Error(t, decoded.Unmarshal(buf[:i], suite))
+	}
+	require.ErrorIs(t, decoded.Unmarshal(append(buf, 0), suite), wire.ErrTrailing)
+}
@@ -0,0 +1,432 @@
+// Package hpke implements Hybrid Public Key Encryption as specified in
+// RFC 9180, with the Diffie-Hellman based key encapsulation (DHKEM) computed
+// on kyber groups.
+//
+// Two KEMs are provided: DHKEMX25519, which uses edwards25519 keys but whose
+// encoding and shared secrets are the ones of X25519, and DHKEMP256 on the
+// NIST P-256 curve. Both are interoperable with other implementations of the
+// RFC. A Suite combines a KEM with a KDF and an AEAD, and sets up the sender
+// and receiver contexts in the four modes of the RFC: base, psk, auth and
+// auth_psk. A context encrypts a sequence of messages and exports secrets
+// bound to the encryption.
+package hpke
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Mode is the mode of a context, which tells how the sender is
+// authenticated.
+type Mode byte
+
+const (
+	// ModeBase doesn't authenticate the sender.
+	ModeBase Mode = 0x00
+	// ModePSK authenticates the sender with a pre-shared key.
+	ModePSK Mode = 0x01
+	// ModeAuth authenticates the sender with its KEM private key.
+	ModeAuth Mode = 0x02
+	// ModeAuthPSK authenticates the sender with both.
+	ModeAuthPSK Mode = 0x03
+)
+
+// KDF identifies a key derivation function.
+type KDF uint16
+
+const (
+	// HKDFSHA256 is HKDF with SHA-256.
+	HKDFSHA256 KDF = 0x0001
+	// HKDFSHA384 is HKDF with SHA-384.
+	HKDFSHA384 KDF = 0x0002
+	// HKDFSHA512 is HKDF with SHA-512.
+	HKDFSHA512 KDF = 0x0003
+)
+
+// AEAD identifies an authenticated encryption scheme.
+type AEAD uint16
+
+const (
+	// AES128GCM is AES-128 in Galois/Counter Mode.
+	AES128GCM AEAD = 0x0001
+	// AES256GCM is AES-256 in Galois/Counter Mode.
+	AES256GCM AEAD = 0x0002
+	// ChaCha20Poly1305 is ChaCha20-Poly1305 as defined in RFC 8439.
+	ChaCha20Poly1305 AEAD = 0x0003
+	// ExportOnly is used when the context only exports secrets.
+	ExportOnly AEAD = 0xffff
+)
+
+// ErrOpen is returned when a ciphertext doesn't decrypt.
+var ErrOpen = errors.New("hpke: authentication failed")
+
+// ErrExportOnly is returned when encrypting or decrypting with a context
+// using ExportOnly.
+var ErrExportOnly = errors.New("hpke: export only context")
+
+var versionLabel = []byte("HPKE-v1")
+
+func (k KDF) hash() (func() hash.Hash, error) {
+	switch k {
+	case HKDFSHA256:
+		return sha256.New, nil
+	case HKDFSHA384:
+		return sha512.New384, nil
+	case HKDFSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("hpke: unknown KDF %#04x", uint16(k))
+	}
+}
+
+// keySize returns the key size Nk of the AEAD.
+func (a AEAD) keySize() (int, error) {
+	switch a {
+	case AES128GCM:
+		return 16, nil
+	case AES256GCM, ChaCha20Poly1305:
+		return 32, nil
+	case ExportOnly:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("hpke: unknown AEAD %#04x", uint16(a))
+	}
+}
+
+func (a AEAD) new(key []byte) (cipher.AEAD, error) {
+	switch a {
+	case AES128GCM, AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	default:
+		return nil, fmt.Errorf("hpke: unknown AEAD %#04x", uint16(a))
+	}
+}
+
+// labeled is the labeled HKDF of the RFC for a suite identifier.
+type labeled struct {
+	hash    func() hash.Hash
+	suiteID []byte
+}
+
+func (l *labeled) extract(salt []byte, label string, ikm []byte) []byte {
+	in := make([]byte, 0, len(versionLabel)+len(l.suiteID)+len(label)+len(ikm))
+	in = append(in, versionLabel...)
+	in = append(in, l.suiteID...)
+	in = append(in, label...)
+	in = append(in, ikm...)
+	return hkdf.Extract(l.hash, in, salt)
+}
+
+func (l *labeled) expand(prk []byte, label string, info []byte, length int) ([]byte, error) {
+	if length > math.MaxUint16 {
+		return nil, errors.New("hpke: expanded length too large")
+	}
+	in := binary.BigEndian.AppendUint16(nil, uint16(length))
+	in = append(in, versionLabel...)
+	in = append(in, l.suiteID...)
+	in = append(in, label...)
+	in = append(in, info...)
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(l.hash, prk, in), out); err != nil {
+		return nil, fmt.Errorf("hpke: expanding %s: %w", label, err)
+	}
+	return out, nil
+}
+
+// Suite is a combination of a KEM, a KDF and an AEAD.
+type Suite struct {
+	kem     *KEM
+	kdf     KDF
+	aead    AEAD
+	labeled labeled
+	nk      int
+}
+
+// NewSuite returns the suite of the given algorithms.
+func NewSuite(kem *KEM, kdf KDF, aead AEAD) (*Suite, error) {
+	h, err := kdf.hash()
+	if err != nil {
+		return nil, err
+	}
+	nk, err := aead.keySize()
+	if err != nil {
+		return nil, err
+	}
+	id := []byte("HPKE")
+	id = binary.BigEndian.AppendUint16(id, kem.id)
+	id = binary.BigEndian.AppendUint16(id, uint16(kdf))
+	id = binary.BigEndian.AppendUint16(id, uint16(aead))
+	return &Suite{
+		kem:     kem,
+		kdf:     kdf,
+		aead:    aead,
+		labeled: labeled{hash: h, suiteID: id},
+		nk:      nk,
+	}, nil
+}
+
+// KEM returns the KEM of the suite.
+func (s *Suite) KEM() *KEM {
+	return s.kem
+}
+
+// SetupBaseS returns the encapsulated key to send to the receiver along with
+// the context encrypting to the public key pkR. The info binds the context to
+// the application.
+func (s *Suite) SetupBaseS(pkR *PublicKey, info []byte) ([]byte, *Sender, error) {
+	return s.setupS(ModeBase, pkR, info, nil, nil, nil, nil)
+}
+
+// SetupBaseR returns the context decrypting the messages of a sender set up
+// with SetupBaseS, from its encapsulated key enc.
+func (s *Suite) SetupBaseR(enc []byte, skR *PrivateKey, info []byte) (*Receiver, error) {
+	return s.setupR(ModeBase, enc, skR, info, nil, nil, nil)
+}
+
+// SetupPSKS is like SetupBaseS, with the sender authenticated by the
+// pre-shared key psk of identifier pskID.
+func (s *Suite) SetupPSKS(pkR *PublicKey, info, psk, pskID []byte) ([]byte, *Sender, error) {
+	return s.setupS(ModePSK, pkR, info, psk, pskID, nil, nil)
+}
+
+// SetupPSKR returns the context decrypting the messages of a sender set up
+// with SetupPSKS.
+func (s *Suite) SetupPSKR(enc []byte, skR *PrivateKey, info, psk, pskID []byte) (*Receiver, error) {
+	return s.setupR(ModePSK, enc, skR, info, psk, pskID, nil)
+}
+
+// SetupAuthS is like SetupBaseS, with the sender authenticated by its private
+// key skS.
+func (s *Suite) SetupAuthS(pkR *PublicKey, info []byte, skS *PrivateKey) ([]byte, *Sender, error) {
+	return s.setupS(ModeAuth, pkR, info, nil, nil, skS, nil)
+}
+
+// SetupAuthR returns the context decrypting the messages of a sender set up
+// with SetupAuthS, whose public key is pkS.
+func (s *Suite) SetupAuthR(enc []byte, skR *PrivateKey, info []byte, pkS *PublicKey) (*Receiver, error) {
+	return s.setupR(ModeAuth, enc, skR, info, nil, nil, pkS)
+}
+
+// SetupAuthPSKS is like SetupBaseS, with the sender authenticated by both the
+// pre-shared key psk of identifier pskID and its private key skS.
+func (s *Suite) SetupAuthPSKS(pkR *PublicKey, info, psk, pskID []byte, skS *PrivateKey) ([]byte, *Sender, error) {
+	return s.setupS(ModeAuthPSK, pkR, info, psk, pskID, skS, nil)
+}
+
+// SetupAuthPSKR returns the context decrypting the messages of a sender set
+// up with SetupAuthPSKS, whose public key is pkS.
+func (s *Suite) SetupAuthPSKR(enc []byte, skR *PrivateKey, info, psk, pskID []byte,
+	pkS *PublicKey) (*Receiver, error) {
+	return s.setupR(ModeAuthPSK, enc, skR, info, psk, pskID, pkS)
+}
+
+// Seal encrypts a single message to pkR in the base mode, and returns the
+// encapsulated key and the ciphertext.
+func (s *Suite) Seal(pkR *PublicKey, info, aad, plaintext []byte) ([]byte, []byte, error) {
+	enc, ctx, err := s.SetupBaseS(pkR, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	ct, err := ctx.Seal(plaintext, aad)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, ct, nil
+}
+
+// Open decrypts a message encrypted by Seal.
+func (s *Suite) Open(enc []byte, skR *PrivateKey, info, aad, ciphertext []byte) ([]byte, error) {
+	ctx, err := s.SetupBaseR(enc, skR, info)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.Open(ciphertext, aad)
+}
+
+// setupS encapsulates a key to pkR with the ephemeral key skE, or a random one
+// if nil, and returns the sender context.
+func (s *Suite) setupS(mode Mode, pkR *PublicKey, info, psk, pskID []byte,
+	skS, skE *PrivateKey) ([]byte, *Sender, error) {
+	if err := checkMode(mode, psk, pskID); err != nil {
+		return nil, nil, err
+	}
+	if skE == nil {
+		var err error
+		skE, err = s.kem.GenerateKeyPair()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	shared, enc, err := s.kem.encap(pkR, skS, skE)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, err := s.keySchedule(mode, shared, info, psk, pskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, &Sender{*ctx}, nil
+}
+
+func (s *Suite) setupR(mode Mode, enc []byte, skR *PrivateKey, info, psk, pskID []byte,
+	pkS *PublicKey) (*Receiver, error) {
+	if err := checkMode(mode, psk, pskID); err != nil {
+		return nil, err
+	}
+	shared, err := s.kem.decap(enc, skR, pkS)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := s.keySchedule(mode, shared, info, psk, pskID)
+	if err != nil {
+		return nil, err
+	}
+	return &Receiver{*ctx}, nil
+}
+
+// checkMode checks that a pre-shared key is given exactly in the modes using
+// one.
+func checkMode(mode Mode, psk, pskID []byte) error {
+	gotPSK := len(psk) > 0
+	if gotPSK != (len(pskID) > 0) {
+		return errors.New("hpke: inconsistent PSK inputs")
+	}
+	switch mode {
+	case ModeBase, ModeAuth:
+		if gotPSK {
+			return errors.New("hpke: PSK input provided when not needed")
+		}
+	case ModePSK, ModeAuthPSK:
+		if !gotPSK {
+			return errors.New("hpke: missing required PSK input")
+		}
+	default:
+		return fmt.Errorf("hpke: unknown mode %d", mode)
+	}
+	return nil
+}
+
+func (s *Suite) keySchedule(mode Mode, shared, info, psk, pskID []byte) (*context, error) {
+	l := &s.labeled
+	pskIDHash := l.extract(nil, "psk_id_hash", pskID)
+	infoHash := l.extract(nil, "info_hash", info)
+	ksc := append([]byte{byte(mode)}, pskIDHash...)
+	ksc = append(ksc, infoHash...)
+
+	secret := l.extract(shared, "secret", psk)
+	exporter, err := l.expand(secret, "exp", ksc, l.hash().Size())
+	if err != nil {
+		return nil, err
+	}
+	ctx := &context{suite: s, exporterSecret: exporter}
+	if s.aead == ExportOnly {
+		return ctx, nil
+	}
+
+	key, err := l.expand(secret, "key", ksc, s.nk)
+	if err != nil {
+		return nil, err
+	}
+	ctx.baseNonce, err = l.expand(secret, "base_nonce", ksc, nonceSize)
+	if err != nil {
+		return nil, err
+	}
+	ctx.aead, err = s.aead.new(key)
+	if err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// nonceSize is the nonce size Nn of all the AEADs.
+const nonceSize = 12
+
+// context is the encryption context shared by the sender and the receiver.
+type context struct {
+	suite          *Suite
+	aead           cipher.AEAD
+	baseNonce      []byte
+	seq            uint64
+	exporterSecret []byte
+}
+
+// nonce returns the nonce of the current message, the base nonce xored with
+// the sequence number.
+func (c *context) nonce() ([]byte, error) {
+	if c.aead == nil {
+		return nil, ErrExportOnly
+	}
+	if c.seq == math.MaxUint64 {
+		return nil, errors.New("hpke: message limit reached")
+	}
+	nonce := make([]byte, nonceSize)
+	binary.BigEndian.PutUint64(nonce[nonceSize-8:], c.seq)
+	for i := range nonce {
+		nonce[i] ^= c.baseNonce[i]
+	}
+	return nonce, nil
+}
+
+// Export returns a secret of length bytes derived from the context and
+// exporterContext.
+func (c *context) Export(exporterContext []byte, length int) ([]byte, error) {
+	l := &c.suite.labeled
+	if length > 255*l.hash().Size() {
+		return nil, errors.New("hpke: exported length too large")
+	}
+	return l.expand(c.exporterSecret, "sec", exporterContext, length)
+}
+
+// Sender is the context of the sender, which encrypts messages.
+type Sender struct {
+	context
+}
+
+// Seal encrypts the next message with the associated data aad. The messages
+// must be decrypted in the same order.
+func (c *Sender) Seal(plaintext, aad []byte) ([]byte, error) {
+	nonce, err := c.nonce()
+	if err != nil {
+		return nil, err
+	}
+	ct := c.aead.Seal(nil, nonce, plaintext, aad)
+	c.seq++
+	return ct, nil
+}
+
+// Receiver is the context of the receiver, which decrypts messages.
+type Receiver struct {
+	context
+}
+
+// Open decrypts the next message with the associated data aad. The sequence
+// number only advances when the decryption succeeds.
+func (c *Receiver) Open(ciphertext, aad []byte) ([]byte, error) {
+	nonce, err := c.nonce()
+	if err != nil {
+		return nil, err
+	}
+	pt, err := c.aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrOpen
+	}
+	c.seq++
+	return pt, nil
+}
@@ -0,0 +1,299 @@
+package hpke
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+type hexBytes []byte
+
+func (h *hexBytes) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	buf, err := hex.DecodeString(s)
+	*h = buf
+	return err
+}
+
+// Test vectors from RFC 9180, Appendix A, for the KEMs of the package. Only
+// some of the encryptions of each vector are kept.
+type vector struct {
+	Mode           Mode     `json:"mode"`
+	KEM            uint16   `json:"kem_id"`
+	KDF            KDF      `json:"kdf_id"`
+	AEAD           AEAD     `json:"aead_id"`
+	Info           hexBytes `json:"info"`
+	IkmR           hexBytes `json:"ikmR"`
+	IkmS           hexBytes `json:"ikmS"`
+	IkmE           hexBytes `json:"ikmE"`
+	SkRm           hexBytes `json:"skRm"`
+	SkSm           hexBytes `json:"skSm"`
+	SkEm           hexBytes `json:"skEm"`
+	PSK            hexBytes `json:"psk"`
+	PSKID          hexBytes `json:"psk_id"`
+	PkRm           hexBytes `json:"pkRm"`
+	PkSm           hexBytes `json:"pkSm"`
+	PkEm           hexBytes `json:"pkEm"`
+	Enc            hexBytes `json:"enc"`
+	SharedSecret   hexBytes `json:"shared_secret"`
+	Key            hexBytes `json:"key"`
+	BaseNonce      hexBytes `json:"base_nonce"`
+	ExporterSecret hexBytes `json:"exporter_secret"`
+	Encryptions    []struct {
+		AAD   hexBytes `json:"aad"`
+		CT    hexBytes `json:"ct"`
+		Nonce hexBytes `json:"nonce"`
+		PT    hexBytes `json:"pt"`
+	} `json:"encryptions"`
+	Exports []struct {
+		Context hexBytes `json:"exporter_context"`
+		L       int      `json:"L"`
+		Value   hexBytes `json:"exported_value"`
+	} `json:"exports"`
+}
+
+func TestVectors(t *testing.T) {
+	buf, err := os.ReadFile("testdata/rfc9180.json")
+	require.NoError(t, err)
+	var vectors []vector
+	require.NoError(t, json.Unmarshal(buf, &vectors))
+
+	for _, v := range vectors {
+		var kem *KEM
+		for _, k := range kems {
+			if k.ID() == v.KEM {
+				kem = k
+			}
+		}
+		if kem == nil {
+			continue
+		}
+		name := fmt.Sprintf("%#04x/%#04x/%#04x/%d", v.KEM, v.KDF, v.AEAD, v.Mode)
+		t.Run(name, func(t *testing.T) {
+			testVector(t, kem, &v)
+		})
+	}
+}
+
+func testVector(t *testing.T, kem *KEM, v *vector) {
+	suite, err := NewSuite(kem, v.KDF, v.AEAD)
+	require.NoError(t, err)
+
+	derive := func(ikm, skm, pkm []byte) *PrivateKey {
+		sk, err := kem.DeriveKeyPair(ikm)
+		require.NoError(t, err)
+		b, err := sk.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, skm, b)
+		b, err = sk.Public().MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, pkm, b)
+		return sk
+	}
+	skR := derive(v.IkmR, v.SkRm, v.PkRm)
+	skE := derive(v.IkmE, v.SkEm, v.PkEm)
+	var skS *PrivateKey
+	var pkS *PublicKey
+	if v.Mode == ModeAuth || v.Mode == ModeAuthPSK {
+		skS = derive(v.IkmS, v.SkSm, v.PkSm)
+		pkS, err = kem.UnmarshalPublicKey(v.PkSm)
+		require.NoError(t, err)
+	}
+	pkR, err := kem.UnmarshalPublicKey(v.PkRm)
+	require.NoError(t, err)
+
+	enc, sender, err := suite.setupS(v.Mode, pkR, v.Info, v.PSK, v.PSKID, skS, skE)
+	require.NoError(t, err)
+	require.Equal(t, []byte(v.Enc), enc)
+	receiver, err := suite.setupR(v.Mode, enc, skR, v.Info, v.PSK, v.PSKID, pkS)
+	require.NoError(t, err)
+
+	require.Equal(t, []byte(v.ExporterSecret), sender.exporterSecret)
+	require.Equal(t, []byte(v.ExporterSecret), receiver.exporterSecret)
+	if v.AEAD != ExportOnly {
+		require.Equal(t, []byte(v.BaseNonce), sender.baseNonce)
+		require.Equal(t, []byte(v.BaseNonce), receiver.baseNonce)
+	}
+
+	for _, e := range v.Encryptions {
+		for {
+			nonce, err := sender.nonce()
+			require.NoError(t, err)
+			if string(nonce) == string(e.Nonce) {
+				break
+			}
+			// skip the messages left out of the vectors
+			sender.seq++
+			receiver.seq++
+		}
+		ct, err := sender.Seal(e.PT, e.AAD)
+		require.NoError(t, err)
+		require.Equal(t, []byte(e.CT), ct)
+		pt, err := receiver.Open(ct, e.AAD)
+		require.NoError(t, err)
+		require.Equal(t, []byte(e.PT), pt)
+	}
+
+	for _, e := range v.Exports {
+		for _, ctx := range []*context{&sender.context, &receiver.context} {
+			value, err := ctx.Export(e.Context, e.L)
+			require.NoError(t, err)
+			require.Equal(t, []byte(e.Value), value)
+		}
+	}
+}
+
+func TestModes(t *testing.T) {
+	for _, kem := range kems {
+		suite, err := NewSuite(kem, HKDFSHA256, ChaCha20Poly1305)
+		require.NoError(t, err)
+		skR, err := kem.GenerateKeyPair()
+		require.NoError(t, err)
+		skS, err := kem.GenerateKeyPair()
+		require.NoError(t, err)
+		pkR, pkS := skR.Public(), skS.Public()
+		info := []byte("info")
+		psk, pskID := []byte("a pre-shared key of 32 bytes....."), []byte("psk id")
+
+		type setup struct {
+			sender   func() ([]byte, *Sender, error)
+			receiver func(enc []byte) (*Receiver, error)
+		}
+		for name, s := range map[string]setup{
+			"base": {
+				func() ([]byte, *Sender, error) { return suite.SetupBaseS(pkR, info) },
+				func(enc []byte) (*Receiver, error) { return suite.SetupBaseR(enc, skR, info) },
+			},
+			"psk": {
+				func() ([]byte, *Sender, error) { return suite.SetupPSKS(pkR, info, psk, pskID) },
+				func(enc []byte) (*Receiver, error) { return suite.SetupPSKR(enc, skR, info, psk, pskID) },
+			},
+			"auth": {
+				func() ([]byte, *Sender, error) { return suite.SetupAuthS(pkR, info, skS) },
+				func(enc []byte) (*Receiver, error) { return suite.SetupAuthR(enc, skR, info, pkS) },
+			},
+			"authpsk": {
+				func() ([]byte, *Sender, error) { return suite.SetupAuthPSKS(pkR, info, psk, pskID, skS) },
+				func(enc []byte) (*Receiver, error) {
+					return suite.SetupAuthPSKR(enc, skR, info, psk, pskID, pkS)
+				},
+			},
+		} {
+			t.Run(fmt.Sprintf("%#04x/%s", kem.ID(), name), func(t *testing.T) {
+				enc, sender, err := s.sender()
+				require.NoError(t, err)
+				receiver, err := s.receiver(enc)
+				require.NoError(t, err)
+				for i := range 3 {
+					msg := fmt.Appendf(nil, "message %d", i)
+					ct, err := sender.Seal(msg, info)
+					require.NoError(t, err)
+					_, err = receiver.Open(ct, nil)
+					require.ErrorIs(t, err, ErrOpen)
+					pt, err := receiver.Open(ct, info)
+					require.NoError(t, err)
+					require.Equal(t, msg, pt)
+				}
+				a, err := sender.Export([]byte("ctx"), 64)
+				require.NoError(t, err)
+				b, err := receiver.Export([]byte("ctx"), 64)
+				require.NoError(t, err)
+				require.Equal(t, a, b)
+			})
+		}
+
+		// the sender must be the expected one
+		other, err := kem.GenerateKeyPair()
+		require.NoError(t, err)
+		enc, sender, err := suite.SetupAuthS(pkR, info, skS)
+		require.NoError(t, err)
+		ct, err := sender.Seal([]byte("hello"), nil)
+		require.NoError(t, err)
+		receiver, err := suite.SetupAuthR(enc, skR, info, other.Public())
+		require.NoError(t, err)
+		_, err = receiver.Open(ct, nil)
+		require.ErrorIs(t, err, ErrOpen)
+
+		// PSK inputs must match the mode
+		_, _, err = suite.SetupPSKS(pkR, info, nil, nil)
+		require.Error(t, err)
+		_, _, err = suite.SetupPSKS(pkR, info, psk, nil)
+		require.Error(t, err)
+		_, _, err = suite.setupS(ModeBase, pkR, info, psk, pskID, nil, nil)
+		require.Error(t, err)
+	}
+}
+
+func TestSingleShot(t *testing.T) {
+	for _, kem := range kems {
+		suite, err := NewSuite(kem, HKDFSHA512, AES256GCM)
+		require.NoError(t, err)
+		sk, err := kem.GenerateKeyPair()
+		require.NoError(t, err)
+		msg := []byte("Hello World")
+		enc, ct, err := suite.Seal(sk.Public(), nil, []byte("aad"), msg)
+		require.NoError(t, err)
+		pt, err := suite.Open(enc, sk, nil, []byte("aad"), ct)
+		require.NoError(t, err)
+		require.Equal(t, msg, pt)
+
+		ct[0] ^= 1
+		_, err = suite.Open(enc, sk, nil, []byte("aad"), ct)
+		require.ErrorIs(t, err, ErrOpen)
+		_, err = suite.Open(enc[1:], sk, nil, []byte("aad"), ct)
+		require.Error(t, err)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	for _, kem := range kems {
+		g := kem.Group()
+		sk, err := kem.GenerateKeyPair()
+		require.NoError(t, err)
+		require.True(t, g.Point().Mul(sk.Scalar(), nil).Equal(sk.Public().Point()))
+		b, err := sk.MarshalBinary()
+		require.NoError(t, err)
+		decoded, err := kem.UnmarshalPrivateKey(b)
+		require.NoError(t, err)
+		require.True(t, decoded.Scalar().Equal(sk.Scalar()))
+		b, err = sk.Public().MarshalBinary()
+		require.NoError(t, err)
+		pk, err := kem.UnmarshalPublicKey(b)
+		require.NoError(t, err)
+		// X25519 only encodes a point up to its sign
+		P := pk.Point().Clone()
+		require.True(t, P.Equal(sk.Public().Point()) || P.Neg(P).Equal(sk.Public().Point()))
+
+		// keys made from the group
+		x := g.Scalar().Pick(random.New())
+		sk, err = kem.NewPrivateKey(x)
+		require.NoError(t, err)
+		pk, err = kem.NewPublicKey(g.Point().Mul(x, nil))
+		require.NoError(t, err)
+		suite, err := NewSuite(kem, HKDFSHA256, AES128GCM)
+		require.NoError(t, err)
+		enc, ct, err := suite.Seal(pk, nil, nil, []byte("hello"))
+		require.NoError(t, err)
+		pt, err := suite.Open(enc, sk, nil, nil, ct)
+		require.NoError(t, err)
+		require.Equal(t, []byte("hello"), pt)
+
+		// invalid keys
+		_, err = kem.NewPublicKey(g.Point().Null())
+		require.Error(t, err)
+		_, err = kem.NewPrivateKey(g.Scalar().Zero())
+		require.Error(t, err)
+		_, err = kem.UnmarshalPublicKey(b[1:])
+		require.Error(t, err)
+		_, err = suite.Open(make([]byte, len(enc)), sk, nil, nil, ct)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,248 @@
+package hpke
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+// dhGroup is the group of a DHKEM along with the encodings of the RFC.
+type dhGroup interface {
+	group() kyber.Group
+	// privateKeySize and publicKeySize are Nsk and Npk.
+	privateKeySize() int
+	publicKeySize() int
+	// deriveKey returns the encoded private key derived from ikm.
+	deriveKey(l *labeled, ikm []byte) ([]byte, error)
+	// decodeScalar decodes a private key into the scalar of its public key.
+	decodeScalar(b []byte) (kyber.Scalar, error)
+	encodeScalar(s kyber.Scalar) ([]byte, error)
+	encodePoint(P kyber.Point) ([]byte, error)
+	decodePoint(b []byte) (kyber.Point, error)
+	// dh returns the encoded shared secret of s and P, which must not be
+	// zero.
+	dh(s kyber.Scalar, P kyber.Point) ([]byte, error)
+}
+
+// KEM is a Diffie-Hellman based key encapsulation mechanism, with HKDF-SHA256
+// as its KDF.
+type KEM struct {
+	id      uint16
+	dh      dhGroup
+	labeled labeled
+}
+
+func newKEM(id uint16, dh dhGroup) *KEM {
+	return &KEM{
+		id: id,
+		dh: dh,
+		labeled: labeled{
+			hash:    sha256.New,
+			suiteID: binary.BigEndian.AppendUint16([]byte("KEM"), id),
+		},
+	}
+}
+
+// ID returns the identifier of the KEM in the RFC.
+func (k *KEM) ID() uint16 {
+	return k.id
+}
+
+// Group returns the group of the keys of the KEM.
+func (k *KEM) Group() kyber.Group {
+	return k.dh.group()
+}
+
+// PublicKey is a public key of a KEM.
+type PublicKey struct {
+	kem   *KEM
+	point kyber.Point
+	enc   []byte
+}
+
+// Point returns the point of the public key. As X25519 public keys don't
+// encode the sign of the Edwards point, it is up to its sign for decoded
+// DHKEMX25519 keys, which doesn't change the shared secrets.
+func (pk *PublicKey) Point() kyber.Point {
+	return pk.point
+}
+
+// MarshalBinary returns the encoding of the public key in the RFC.
+func (pk *PublicKey) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, pk.enc...), nil
+}
+
+// PrivateKey is a private key of a KEM.
+type PrivateKey struct {
+	kem    *KEM
+	scalar kyber.Scalar
+	enc    []byte
+	public *PublicKey
+}
+
+// Scalar returns the scalar of the private key, whose product with the base
+// point is the public key.
+func (sk *PrivateKey) Scalar() kyber.Scalar {
+	return sk.scalar
+}
+
+// Public returns the public key of the private key.
+func (sk *PrivateKey) Public() *PublicKey {
+	return sk.public
+}
+
+// MarshalBinary returns the encoding of the private key in the RFC. Keys
+// made with NewPrivateKey may not have one.
+func (sk *PrivateKey) MarshalBinary() ([]byte, error) {
+	if sk.enc == nil {
+		return sk.kem.dh.encodeScalar(sk.scalar)
+	}
+	return append([]byte{}, sk.enc...), nil
+}
+
+// NewPublicKey returns the public key of the point P of the group.
+func (k *KEM) NewPublicKey(P kyber.Point) (*PublicKey, error) {
+	if P.Equal(k.dh.group().Point().Null()) {
+		return nil, errors.New("hpke: public key is the identity")
+	}
+	enc, err := k.dh.encodePoint(P)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{kem: k, point: P.Clone(), enc: enc}, nil
+}
+
+// NewPrivateKey returns the private key of the scalar s of the group.
+func (k *KEM) NewPrivateKey(s kyber.Scalar) (*PrivateKey, error) {
+	return k.newPrivateKey(s.Clone(), nil)
+}
+
+func (k *KEM) newPrivateKey(s kyber.Scalar, enc []byte) (*PrivateKey, error) {
+	if s.Equal(k.dh.group().Scalar().Zero()) {
+		return nil, errors.New("hpke: private key is zero")
+	}
+	pk, err := k.NewPublicKey(k.dh.group().Point().Mul(s, nil))
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{kem: k, scalar: s, enc: enc, public: pk}, nil
+}
+
+// UnmarshalPublicKey decodes a public key encoded as in the RFC.
+func (k *KEM) UnmarshalPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != k.dh.publicKeySize() {
+		return nil, errors.New("hpke: invalid public key length")
+	}
+	P, err := k.dh.decodePoint(b)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{kem: k, point: P, enc: append([]byte{}, b...)}, nil
+}
+
+// UnmarshalPrivateKey decodes a private key encoded as in the RFC.
+func (k *KEM) UnmarshalPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != k.dh.privateKeySize() {
+		return nil, errors.New("hpke: invalid private key length")
+	}
+	s, err := k.dh.decodeScalar(b)
+	if err != nil {
+		return nil, err
+	}
+	return k.newPrivateKey(s, append([]byte{}, b...))
+}
+
+// DeriveKeyPair deterministically derives a private key from the input
+// keying material ikm, which must have at least as much entropy as the
+// private key.
+func (k *KEM) DeriveKeyPair(ikm []byte) (*PrivateKey, error) {
+	b, err := k.dh.deriveKey(&k.labeled, ikm)
+	if err != nil {
+		return nil, err
+	}
+	return k.UnmarshalPrivateKey(b)
+}
+
+// GenerateKeyPair returns a random private key.
+func (k *KEM) GenerateKeyPair() (*PrivateKey, error) {
+	ikm := make([]byte, k.dh.privateKeySize())
+	random.Bytes(ikm, random.New())
+	return k.DeriveKeyPair(ikm)
+}
+
+// encap returns the shared secret and its encapsulation to pkR with the
+// ephemeral key skE, authenticated by skS if not nil.
+func (k *KEM) encap(pkR *PublicKey, skS, skE *PrivateKey) ([]byte, []byte, error) {
+	if err := k.check(pkR.kem, skE.kem); err != nil {
+		return nil, nil, err
+	}
+	dh, err := k.dh.dh(skE.scalar, pkR.point)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc := skE.public.enc
+	kemContext := append(append([]byte{}, enc...), pkR.enc...)
+	if skS != nil {
+		if err := k.check(skS.kem); err != nil {
+			return nil, nil, err
+		}
+		dhS, err := k.dh.dh(skS.scalar, pkR.point)
+		if err != nil {
+			return nil, nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, skS.public.enc...)
+	}
+	shared, err := k.extractAndExpand(dh, kemContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shared, append([]byte{}, enc...), nil
+}
+
+// decap returns the shared secret encapsulated in enc for skR, authenticated
+// by pkS if not nil.
+func (k *KEM) decap(enc []byte, skR *PrivateKey, pkS *PublicKey) ([]byte, error) {
+	if err := k.check(skR.kem); err != nil {
+		return nil, err
+	}
+	pkE, err := k.UnmarshalPublicKey(enc)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := k.dh.dh(skR.scalar, pkE.point)
+	if err != nil {
+		return nil, err
+	}
+	kemContext := append(append([]byte{}, enc...), skR.public.enc...)
+	if pkS != nil {
+		if err := k.check(pkS.kem); err != nil {
+			return nil, err
+		}
+		dhS, err := k.dh.dh(skR.scalar, pkS.point)
+		if err != nil {
+			return nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, pkS.enc...)
+	}
+	return k.extractAndExpand(dh, kemContext)
+}
+
+func (k *KEM) extractAndExpand(dh, kemContext []byte) ([]byte, error) {
+	prk := k.labeled.extract(nil, "eae_prk", dh)
+	return k.labeled.expand(prk, "shared_secret", kemContext, k.labeled.hash().Size())
+}
+
+// check checks that the keys are keys of the KEM.
+func (k *KEM) check(keys ...*KEM) error {
+	for _, key := range keys {
+		if key.id != k.id {
+			return errors.New("hpke: key of another KEM")
+		}
+	}
+	return nil
+}
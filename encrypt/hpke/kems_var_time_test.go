@@ -0,0 +1,5 @@
+//go:build !constantTime
+
+package hpke
+
+var kems = []*KEM{DHKEMX25519(), DHKEMP256()}
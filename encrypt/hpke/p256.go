@@ -0,0 +1,92 @@
+//go:build !constantTime
+
+package hpke
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/p256"
+)
+
+// nistP256 uses the uncompressed encoding of points and the big-endian
+// encoding of scalars, and the x-coordinate of the product as shared secret.
+type nistP256 struct {
+	g kyber.Group
+}
+
+var p256KEM = newKEM(0x0010, &nistP256{g: p256.NewBlakeSHA256P256()})
+
+// DHKEMP256 returns the DHKEM(P-256, HKDF-SHA256) KEM. As the P-256 group,
+// it isn't available in constant time builds.
+func DHKEMP256() *KEM {
+	return p256KEM
+}
+
+func (n *nistP256) group() kyber.Group  { return n.g }
+func (n *nistP256) privateKeySize() int { return 32 }
+func (n *nistP256) publicKeySize() int  { return 65 }
+
+func (n *nistP256) deriveKey(l *labeled, ikm []byte) ([]byte, error) {
+	prk := l.extract(nil, "dkp_prk", ikm)
+	for counter := 0; counter < 256; counter++ {
+		b, err := l.expand(prk, "candidate", []byte{byte(counter)}, 32)
+		if err != nil {
+			return nil, err
+		}
+		if n.checkScalar(b) == nil {
+			return b, nil
+		}
+	}
+	return nil, errors.New("hpke: failed to derive a key pair")
+}
+
+// checkScalar checks that b encodes a non-zero scalar lower than the order.
+func (n *nistP256) checkScalar(b []byte) error {
+	k := new(big.Int).SetBytes(b)
+	if k.Sign() == 0 || k.Cmp(elliptic.P256().Params().N) >= 0 {
+		return errors.New("hpke: invalid P-256 private key")
+	}
+	return nil
+}
+
+func (n *nistP256) decodeScalar(b []byte) (kyber.Scalar, error) {
+	if err := n.checkScalar(b); err != nil {
+		return nil, err
+	}
+	return n.g.Scalar().SetBytes(b), nil
+}
+
+func (n *nistP256) encodeScalar(s kyber.Scalar) ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (n *nistP256) encodePoint(P kyber.Point) ([]byte, error) {
+	return P.MarshalBinary()
+}
+
+func (n *nistP256) decodePoint(b []byte) (kyber.Point, error) {
+	P := n.g.Point()
+	if err := P.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	valid, ok := P.(interface{ Valid() bool })
+	if !ok || !valid.Valid() || P.Equal(n.g.Point().Null()) {
+		return nil, errors.New("hpke: invalid P-256 public key")
+	}
+	return P, nil
+}
+
+func (n *nistP256) dh(s kyber.Scalar, P kyber.Point) ([]byte, error) {
+	Q := n.g.Point().Mul(s, P)
+	if Q.Equal(n.g.Point().Null()) {
+		return nil, errors.New("hpke: invalid shared secret")
+	}
+	b, err := Q.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return b[1:33], nil
+}
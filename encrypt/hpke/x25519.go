@@ -0,0 +1,114 @@
+package hpke
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+)
+
+// x25519 computes X25519 on the birationally equivalent edwards25519 curve:
+// the u-coordinate of a Montgomery point is (1+y)/(1-y), where y is the
+// coordinate of the Edwards point. Private keys are X25519 private keys, whose
+// clamped value reduced modulo the group order is the scalar of the
+// public key.
+type x25519 struct {
+	g kyber.Group
+}
+
+// fieldPrime is the prime 2^255-19 of the curve field.
+var fieldPrime = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+var x25519KEM = newKEM(0x0020, &x25519{g: edwards25519.NewBlakeSHA256Ed25519()})
+
+// DHKEMX25519 returns the DHKEM(X25519, HKDF-SHA256) KEM, whose keys are
+// edwards25519 scalars and points.
+func DHKEMX25519() *KEM {
+	return x25519KEM
+}
+
+func (x *x25519) group() kyber.Group  { return x.g }
+func (x *x25519) privateKeySize() int { return 32 }
+func (x *x25519) publicKeySize() int  { return 32 }
+
+func (x *x25519) deriveKey(l *labeled, ikm []byte) ([]byte, error) {
+	prk := l.extract(nil, "dkp_prk", ikm)
+	return l.expand(prk, "sk", nil, 32)
+}
+
+func (x *x25519) decodeScalar(b []byte) (kyber.Scalar, error) {
+	k := slices.Clone(b)
+	k[0] &= 248
+	k[31] &= 127
+	k[31] |= 64
+	return x.g.Scalar().SetBytes(k), nil
+}
+
+func (x *x25519) encodeScalar(kyber.Scalar) ([]byte, error) {
+	return nil, errors.New("hpke: no X25519 encoding for the private key")
+}
+
+// encodePoint returns the u-coordinate of P, which is zero for the identity.
+func (x *x25519) encodePoint(P kyber.Point) ([]byte, error) {
+	b, err := P.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b[31] &= 0x7f
+	y := new(big.Int).SetBytes(reverse(b))
+	den := new(big.Int).Sub(big.NewInt(1), y)
+	den.Mod(den, fieldPrime)
+	u := new(big.Int)
+	if den.Sign() != 0 {
+		u.Add(y, big.NewInt(1))
+		u.Mul(u, den.ModInverse(den, fieldPrime))
+		u.Mod(u, fieldPrime)
+	}
+	return reverse(u.FillBytes(make([]byte, 32))), nil
+}
+
+// decodePoint returns one of the two Edwards points of u-coordinate b. Points
+// on the twist of the curve are rejected.
+func (x *x25519) decodePoint(b []byte) (kyber.Point, error) {
+	k := reverse(b)
+	k[0] &= 0x7f
+	u := new(big.Int).SetBytes(k)
+	den := new(big.Int).Add(u, big.NewInt(1))
+	den.Mod(den, fieldPrime)
+	if den.Sign() == 0 {
+		return nil, errors.New("hpke: invalid X25519 public key")
+	}
+	y := new(big.Int).Sub(u, big.NewInt(1))
+	y.Mul(y, den.ModInverse(den, fieldPrime))
+	y.Mod(y, fieldPrime)
+	P := x.g.Point()
+	if err := P.UnmarshalBinary(reverse(y.FillBytes(make([]byte, 32)))); err != nil {
+		return nil, errors.New("hpke: invalid X25519 public key")
+	}
+	return P, nil
+}
+
+// dh multiplies the cofactor out of P, as the clamped scalar of X25519 is a
+// multiple of it, so that points out of the prime order subgroup are handled
+// as in X25519.
+func (x *x25519) dh(s kyber.Scalar, P kyber.Point) ([]byte, error) {
+	Q := x.g.Point().Add(P, P)
+	Q.Add(Q, Q)
+	Q.Add(Q, Q)
+	eighth := x.g.Scalar().Inv(x.g.Scalar().SetInt64(8))
+	Q.Mul(x.g.Scalar().Mul(s, eighth), Q)
+	if Q.Equal(x.g.Point().Null()) {
+		return nil, errors.New("hpke: invalid shared secret")
+	}
+	return x.encodePoint(Q)
+}
+
+// reverse returns b in reverse order, to go between the little-endian
+// encodings of the curve and big.Int.
+func reverse(b []byte) []byte {
+	r := slices.Clone(b)
+	slices.Reverse(r)
+	return r
+}
@@ -0,0 +1,171 @@
+package ibe
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/hybrid"
+	"go.dedis.ch/kyber/v4/internal/stream"
+	"go.dedis.ch/kyber/v4/internal/wire"
+	"go.dedis.ch/kyber/v4/pairing"
+)
+
+// The hybrid mode encrypts payloads of any length: a random key is
+// encapsulated with the CCA identity-based encryption, and the payload is
+// encrypted with AES-256-GCM under a key derived from it, in chunks with the
+// STREAM construction so that it can be processed on the fly. The encrypted
+// payload is made of a header holding the encoded IBE ciphertext, followed by
+// the chunks, which authenticate the header.
+
+const hybridKeySize = 32
+
+// maxHybridHeader bounds the size of the IBE ciphertext read from a header,
+// which holds a point and two strings of the size of the key.
+const maxHybridHeader = 4096
+
+var hybridInfo = []byte("kyber-ibe-hybrid-v1")
+
+// kem is the key encapsulation of a variant of the CCA scheme.
+type kem struct {
+	uGroup  kyber.Group
+	encrypt func(s pairing.Suite, master kyber.Point, ID, msg []byte) (*Ciphertext, error)
+	decrypt func(s pairing.Suite, private kyber.Point, c *Ciphertext) ([]byte, error)
+}
+
+func kemOnG1(s pairing.Suite) *kem {
+	return &kem{uGroup: s.G1(), encrypt: EncryptCCAonG1, decrypt: DecryptCCAonG1}
+}
+
+func kemOnG2(s pairing.Suite) *kem {
+	return &kem{uGroup: s.G2(), encrypt: EncryptCCAonG2, decrypt: DecryptCCAonG2}
+}
+
+func (k *kem) writer(s pairing.Suite, master kyber.Point, ID []byte, w io.Writer) (io.WriteCloser, error) {
+	key := make([]byte, hybridKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("err reading rand key: %w", err)
+	}
+	c, err := k.encrypt(s, master, ID, key)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := c.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := hybrid.NewAEAD(key, nil, hybridInfo)
+	if err != nil {
+		return nil, err
+	}
+	header, err := wire.WriteBytes(w, wire.TagIBEHybrid, buf)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewWriter(aead, header, w)
+}
+
+func (k *kem) reader(s pairing.Suite, private kyber.Point, r io.Reader) (io.Reader, error) {
+	header, buf, err := wire.ReadBytes(r, wire.TagIBEHybrid, maxHybridHeader)
+	if err != nil {
+		return nil, fmt.Errorf("ibe: reading header: %w", err)
+	}
+	c := new(Ciphertext)
+	if err := c.Unmarshal(buf, k.uGroup); err != nil {
+		return nil, err
+	}
+	key, err := k.decrypt(s, private, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != hybridKeySize {
+		return nil, errors.New("ibe: invalid encapsulated key")
+	}
+	aead, err := hybrid.NewAEAD(key, nil, hybridInfo)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewReader(aead, header, r)
+}
+
+func (k *kem) seal(s pairing.Suite, master kyber.Point, ID, msg []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := k.writer(s, master, ID, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (k *kem) open(s pairing.Suite, private kyber.Point, ciphertext []byte) ([]byte, error) {
+	r, err := k.reader(s, private, bytes.NewReader(ciphertext))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// NewEncryptWriterOnG1 returns a writer encrypting the data written to it to
+// the identity ID and writing the result to w, with the master key on G1 as in
+// EncryptCCAonG1. The writer must be closed to complete the encryption; this
+// doesn't close w.
+func NewEncryptWriterOnG1(s pairing.Suite, master kyber.Point, ID []byte, w io.Writer) (io.WriteCloser, error) {
+	return kemOnG1(s).writer(s, master, ID, w)
+}
+
+// NewDecryptReaderOnG1 returns a reader decrypting the data written by a
+// writer of NewEncryptWriterOnG1, given the private key of the identity on G2.
+// The decrypted data is only returned once authenticated, and io.EOF is only
+// returned at the authenticated end of the data.
+func NewDecryptReaderOnG1(s pairing.Suite, private kyber.Point, r io.Reader) (io.Reader, error) {
+	return kemOnG1(s).reader(s, private, r)
+}
+
+// EncryptHybridOnG1 encrypts msg of any length to the identity ID, with the
+// master key on G1. It is the one-shot version of NewEncryptWriterOnG1.
+func EncryptHybridOnG1(s pairing.Suite, master kyber.Point, ID, msg []byte) ([]byte, error) {
+	return kemOnG1(s).seal(s, master, ID, msg)
+}
+
+// DecryptHybridOnG1 decrypts a ciphertext given by EncryptHybridOnG1 with the
+// private key of the identity on G2.
+func DecryptHybridOnG1(s pairing.Suite, private kyber.Point, ciphertext []byte) ([]byte, error) {
+	return kemOnG1(s).open(s, private, ciphertext)
+}
+
+// NewEncryptWriterOnG2 returns a writer encrypting the data written to it to
+// the identity ID and writing the result to w, with the master key on G2 as in
+// EncryptCCAonG2. The writer must be closed to complete the encryption; this
+// doesn't close w.
+func NewEncryptWriterOnG2(s pairing.Suite, master kyber.Point, ID []byte, w io.Writer) (io.WriteCloser, error) {
+	return kemOnG2(s).writer(s, master, ID, w)
+}
+
+// NewDecryptReaderOnG2 returns a reader decrypting the data written by a
+// writer of NewEncryptWriterOnG2, given the private key of the identity on G1.
+// The decrypted data is only returned once authenticated, and io.EOF is only
+// returned at the authenticated end of the data.
+func NewDecryptReaderOnG2(s pairing.Suite, private kyber.Point, r io.Reader) (io.Reader, error) {
+	return kemOnG2(s).reader(s, private, r)
+}
+
+// EncryptHybridOnG2 encrypts msg of any length to the identity ID, with the
+// master key on G2. It is the one-shot version of NewEncryptWriterOnG2.
+func EncryptHybridOnG2(s pairing.Suite, master kyber.Point, ID, msg []byte) ([]byte, error) {
+	return kemOnG2(s).seal(s, master, ID, msg)
+}
+
+// DecryptHybridOnG2 decrypts a ciphertext given by EncryptHybridOnG2 with the
+// private key of the identity on G1.
+func DecryptHybridOnG2(s pairing.Suite, private kyber.Point, ciphertext []byte) ([]byte, error) {
+	return kemOnG2(s).open(s, private, ciphertext)
+}
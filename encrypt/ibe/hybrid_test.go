@@ -0,0 +1,146 @@
+package ibe
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/stream"
+	"go.dedis.ch/kyber/v4/pairing"
+	circl "go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestHybrid(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		setting uint
+		encrypt func(pairing.Suite, kyber.Point, []byte, []byte) ([]byte, error)
+		decrypt func(pairing.Suite, kyber.Point, []byte) ([]byte, error)
+		writer  func(pairing.Suite, kyber.Point, []byte, io.Writer) (io.WriteCloser, error)
+		reader  func(pairing.Suite, kyber.Point, io.Reader) (io.Reader, error)
+	}{
+		{"OnG1", 1, EncryptHybridOnG1, DecryptHybridOnG1, NewEncryptWriterOnG1, NewDecryptReaderOnG1},
+		{"OnG2", 2, EncryptHybridOnG2, DecryptHybridOnG2, NewEncryptWriterOnG2, NewDecryptReaderOnG2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			suite, Ppub, ID, sQid, _, _ := newSetting(tc.setting)
+
+			for _, size := range []int{0, 10, 1000, 2*stream.ChunkSize + 1} {
+				msg := make([]byte, size)
+				_, _ = rand.Read(msg)
+				c, err := tc.encrypt(suite, Ppub, ID, msg)
+				require.NoError(t, err)
+				plain, err := tc.decrypt(suite, sQid, c)
+				require.NoError(t, err)
+				require.Equal(t, msg, append([]byte{}, plain...))
+			}
+
+			// streamed
+			msg := make([]byte, 3*stream.ChunkSize/2)
+			_, _ = rand.Read(msg)
+			var buf bytes.Buffer
+			w, err := tc.writer(suite, Ppub, ID, &buf)
+			require.NoError(t, err)
+			_, err = io.Copy(w, bytes.NewReader(msg))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			r, err := tc.reader(suite, sQid, bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+			plain, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, msg, plain)
+
+			// tampered header or payload
+			c := buf.Bytes()
+			c[10] ^= 1
+			_, err = tc.decrypt(suite, sQid, c)
+			require.Error(t, err)
+			c[10] ^= 1
+			c[len(c)-1] ^= 1
+			_, err = tc.decrypt(suite, sQid, c)
+			require.Error(t, err)
+			c[len(c)-1] ^= 1
+			_, err = tc.decrypt(suite, sQid, c[:len(c)-20])
+			require.Error(t, err)
+			_, err = tc.decrypt(suite, sQid, c[:5])
+			require.Error(t, err)
+
+			// wrong key
+			_, err = tc.decrypt(suite, sQid.Clone().Add(sQid, sQid), c)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestCiphertextMarshal(t *testing.T) {
+	for _, setting := range []uint{1, 2} {
+		suite, Ppub, ID, sQid, encrypt, decrypt := newSetting(setting)
+		uGroup := suite.G1()
+		if setting == 2 {
+			uGroup = suite.G2()
+		}
+		msg := []byte("Hello World\n")
+		c, err := encrypt(suite, Ppub, ID, msg)
+		require.NoError(t, err)
+		buf, err := c.Marshal()
+		require.NoError(t, err)
+
+		decoded := new(Ciphertext)
+		require.NoError(t, decoded.Unmarshal(buf, uGroup))
+		plain, err := decrypt(suite, sQid, decoded)
+		require.NoError(t, err)
+		require.Equal(t, msg, plain)
+
+		// truncated, trailing bytes, wrong group
+		for i := range buf {
+			require.Error(t, decoded.Unmarshal(buf[:i], uGroup))
+		}
+		require.Error(t, decoded.Unmarshal(append(buf, 0), uGroup))
+		require.Error(t, new(CiphertextCPA).Unmarshal(buf, uGroup))
+	}
+
+	suite := circl.NewSuiteBLS12381()
+	P := suite.G1().Point().Base()
+	s := suite.G1().Scalar().Pick(random.New())
+	Ppub := suite.G1().Point().Mul(s, P)
+	ID := []byte("passtherand")
+	Qid := suite.G2().Point().(kyber.HashablePoint).Hash(ID)
+	sQid := Qid.Mul(s, Qid)
+	msg := []byte("a message longer than the hash size of the suite, for CPA")
+	c, err := EncryptCPAonG1(suite, P, Ppub, ID, msg)
+	require.NoError(t, err)
+	buf, err := c.Marshal()
+	require.NoError(t, err)
+	decoded := new(CiphertextCPA)
+	require.NoError(t, decoded.Unmarshal(buf, suite.G1()))
+	plain, err := DecryptCPAonG1(suite, sQid, decoded)
+	require.NoError(t, err)
+	require.Equal(t, msg, plain)
+	require.Error(t, decoded.Unmarshal(buf[:len(buf)-1], suite.G1()))
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	suite, Ppub, ID, _, encrypt, _ := newSetting(1)
+	c, err := encrypt(suite, Ppub, ID, []byte("seed"))
+	require.NoError(f, err)
+	buf, err := c.Marshal()
+	require.NoError(f, err)
+	f.Add(buf)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		c := new(Ciphertext)
+		if c.Unmarshal(data, suite.G1()) != nil {
+			return
+		}
+		buf, err := c.Marshal()
+		require.NoError(t, err)
+		decoded := new(Ciphertext)
+		require.NoError(t, decoded.Unmarshal(buf, suite.G1()))
+		again, err := decoded.Marshal()
+		require.NoError(t, err)
+		require.Equal(t, buf, again)
+	})
+}
@@ -0,0 +1,217 @@
+package ibe
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// The identity private key s*Q_id can be extracted by a group of n
+// participants holding the shares s_i of the master secret s, without anyone
+// learning s: each participant gives its partial key s_i*Q_id, which can be
+// checked against the public sharing polynomial of the master key, and t
+// valid partial keys are combined with Lagrange interpolation. The identity
+// private key is exactly the BLS signature of the identity under the master
+// key, so that a threshold BLS beacon releasing signatures of round numbers
+// (see kyber/sign/tbls) provides timelock encryption: a message encrypted to
+// the identity of a future round can only be decrypted once the beacon has
+// signed that round.
+
+// ErrInvalidPartialKey is returned when a partial identity key doesn't verify
+// against the public polynomial.
+var ErrInvalidPartialKey = errors.New("invalid partial identity key")
+
+// extraction describes the groups of the master keys and of the identities.
+type extraction struct {
+	s        pairing.Suite
+	keyGroup kyber.Group
+	idGroup  kyber.Group
+	onG1     bool
+}
+
+// onG1 is the setting of EncryptCCAonG1, with master keys on G1 and identity
+// keys on G2.
+func onG1(s pairing.Suite) *extraction {
+	return &extraction{s: s, keyGroup: s.G1(), idGroup: s.G2(), onG1: true}
+}
+
+// onG2 is the setting of EncryptCCAonG2, with master keys on G2 and identity
+// keys on G1.
+func onG2(s pairing.Suite) *extraction {
+	return &extraction{s: s, keyGroup: s.G2(), idGroup: s.G1()}
+}
+
+func (e *extraction) hashID(ID []byte) (kyber.Point, error) {
+	hashable, ok := e.idGroup.Point().(kyber.HashablePoint)
+	if !ok {
+		return nil, errors.New("point needs to implement `kyber.HashablePoint`")
+	}
+	return hashable.Hash(ID), nil
+}
+
+func (e *extraction) partial(private *share.PriShare, ID []byte) (*share.PubShare, error) {
+	Qid, err := e.hashID(ID)
+	if err != nil {
+		return nil, err
+	}
+	return &share.PubShare{I: private.I, V: e.idGroup.Point().Mul(private.V, Qid)}, nil
+}
+
+// check checks that key is the identity key of ID for the master key public,
+// i.e. that e(public, Q_id) == e(P, key) with P the base point of the master
+// keys group.
+func (e *extraction) check(public, key kyber.Point, ID []byte) error {
+	Qid, err := e.hashID(ID)
+	if err != nil {
+		return err
+	}
+	base := e.keyGroup.Point().Base()
+	var ok bool
+	if e.onG1 {
+		ok = e.s.ValidatePairing(public, Qid, base, key)
+	} else {
+		ok = e.s.ValidatePairing(Qid, public, key, base)
+	}
+	if !ok {
+		return ErrInvalidPartialKey
+	}
+	return nil
+}
+
+func (e *extraction) verify(public *share.PubPoly, ID []byte, partial *share.PubShare) error {
+	if partial == nil || partial.V == nil {
+		return ErrInvalidPartialKey
+	}
+	if err := e.check(public.Eval(partial.I).V, partial.V, ID); err != nil {
+		return fmt.Errorf("%w %d", ErrInvalidPartialKey, partial.I)
+	}
+	return nil
+}
+
+func (e *extraction) combine(public *share.PubPoly, ID []byte, partials []*share.PubShare, t, n uint32) (kyber.Point, error) {
+	var shares []*share.PubShare
+	seen := make(map[uint32]bool, t)
+	for _, p := range partials {
+		if p == nil || seen[p.I] {
+			continue
+		}
+		if err := e.verify(public, ID, p); err != nil {
+			continue
+		}
+		seen[p.I] = true
+		shares = append(shares, p)
+		if uint32(len(shares)) >= t {
+			break
+		}
+	}
+	if uint32(len(shares)) < t {
+		return nil, errors.New("not enough valid partial identity keys")
+	}
+	return share.RecoverCommit(e.idGroup, shares, t, n)
+}
+
+// ExtractPartialOnG1 returns the partial identity key of ID on G2 for the
+// share of a master secret whose public key is on G1, for use with
+// DecryptCCAonG1.
+func ExtractPartialOnG1(s pairing.Suite, private *share.PriShare, ID []byte) (*share.PubShare, error) {
+	return onG1(s).partial(private, ID)
+}
+
+// VerifyPartialOnG1 checks a partial identity key given by ExtractPartialOnG1
+// against the public sharing polynomial of the master key on G1.
+func VerifyPartialOnG1(s pairing.Suite, public *share.PubPoly, ID []byte, partial *share.PubShare) error {
+	return onG1(s).verify(public, ID, partial)
+}
+
+// CombinePartialsOnG1 verifies the partial identity keys given by
+// ExtractPartialOnG1 and recovers the identity private key of ID from t valid
+// ones. Invalid partial keys are ignored.
+func CombinePartialsOnG1(s pairing.Suite, public *share.PubPoly, ID []byte, partials []*share.PubShare,
+	t, n uint32) (kyber.Point, error) {
+	return onG1(s).combine(public, ID, partials, t, n)
+}
+
+// ExtractPartialOnG2 returns the partial identity key of ID on G1 for the
+// share of a master secret whose public key is on G2, for use with
+// DecryptCCAonG2.
+func ExtractPartialOnG2(s pairing.Suite, private *share.PriShare, ID []byte) (*share.PubShare, error) {
+	return onG2(s).partial(private, ID)
+}
+
+// VerifyPartialOnG2 checks a partial identity key given by ExtractPartialOnG2
+// against the public sharing polynomial of the master key on G2.
+func VerifyPartialOnG2(s pairing.Suite, public *share.PubPoly, ID []byte, partial *share.PubShare) error {
+	return onG2(s).verify(public, ID, partial)
+}
+
+// CombinePartialsOnG2 verifies the partial identity keys given by
+// ExtractPartialOnG2 and recovers the identity private key of ID from t valid
+// ones. Invalid partial keys are ignored.
+func CombinePartialsOnG2(s pairing.Suite, public *share.PubPoly, ID []byte, partials []*share.PubShare,
+	t, n uint32) (kyber.Point, error) {
+	return onG2(s).combine(public, ID, partials, t, n)
+}
+
+// RoundID returns the identity of a round of a randomness beacon for timelock
+// encryption: the SHA-256 hash of the round number in big-endian, which is the
+// message signed by the beacon for that round.
+func RoundID(round uint64) []byte {
+	h := sha256.Sum256(binary.BigEndian.AppendUint64(nil, round))
+	return h[:]
+}
+
+// EncryptTimelockOnG1 encrypts msg so that it can be decrypted with the
+// signature of the given round by a beacon whose public key is master on G1,
+// and whose signatures are thus on G2.
+func EncryptTimelockOnG1(s pairing.Suite, master kyber.Point, round uint64, msg []byte) (*Ciphertext, error) {
+	return EncryptCCAonG1(s, master, RoundID(round), msg)
+}
+
+// DecryptTimelockOnG1 decrypts a ciphertext given by EncryptTimelockOnG1 with
+// the signature of the round by the beacon. The signature is checked against
+// the public key of the beacon before being used.
+func DecryptTimelockOnG1(s pairing.Suite, master kyber.Point, round uint64, signature []byte,
+	c *Ciphertext) ([]byte, error) {
+	key, err := timelockKey(onG1(s), master, round, signature)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptCCAonG1(s, key, c)
+}
+
+// EncryptTimelockOnG2 encrypts msg so that it can be decrypted with the
+// signature of the given round by a beacon whose public key is master on G2,
+// and whose signatures are thus on G1.
+func EncryptTimelockOnG2(s pairing.Suite, master kyber.Point, round uint64, msg []byte) (*Ciphertext, error) {
+	return EncryptCCAonG2(s, master, RoundID(round), msg)
+}
+
+// DecryptTimelockOnG2 decrypts a ciphertext given by EncryptTimelockOnG2 with
+// the signature of the round by the beacon. The signature is checked against
+// the public key of the beacon before being used.
+func DecryptTimelockOnG2(s pairing.Suite, master kyber.Point, round uint64, signature []byte,
+	c *Ciphertext) ([]byte, error) {
+	key, err := timelockKey(onG2(s), master, round, signature)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptCCAonG2(s, key, c)
+}
+
+// timelockKey decodes the signature of the round and checks it is the
+// identity key of the round.
+func timelockKey(e *extraction, master kyber.Point, round uint64, signature []byte) (kyber.Point, error) {
+	key := e.idGroup.Point()
+	if err := key.UnmarshalBinary(signature); err != nil {
+		return nil, fmt.Errorf("invalid round signature: %w", err)
+	}
+	if err := e.check(master, key, RoundID(round)); err != nil {
+		return nil, errors.New("invalid round signature")
+	}
+	return key, nil
+}
@@ -0,0 +1,119 @@
+package ibe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing"
+	circl "go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign"
+	"go.dedis.ch/kyber/v4/sign/tbls"
+)
+
+func TestDistributedExtraction(t *testing.T) {
+	suite := circl.NewSuiteBLS12381()
+	n, th := uint32(5), uint32(3)
+	ID := []byte("alice@example.com")
+	msg := []byte("Hello World\n")
+
+	for _, tc := range []struct {
+		name     string
+		keyGroup kyber.Group
+		extract  func(pairing.Suite, *share.PriShare, []byte) (*share.PubShare, error)
+		verify   func(pairing.Suite, *share.PubPoly, []byte, *share.PubShare) error
+		combine  func(pairing.Suite, *share.PubPoly, []byte, []*share.PubShare, uint32, uint32) (kyber.Point, error)
+		encrypt  func(pairing.Suite, kyber.Point, []byte, []byte) (*Ciphertext, error)
+		decrypt  func(pairing.Suite, kyber.Point, *Ciphertext) ([]byte, error)
+	}{
+		{"OnG1", suite.G1(), ExtractPartialOnG1, VerifyPartialOnG1, CombinePartialsOnG1,
+			EncryptCCAonG1, DecryptCCAonG1},
+		{"OnG2", suite.G2(), ExtractPartialOnG2, VerifyPartialOnG2, CombinePartialsOnG2,
+			EncryptCCAonG2, DecryptCCAonG2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			poly := share.NewPriPoly(tc.keyGroup, th, nil, suite.RandomStream())
+			pub := poly.Commit(tc.keyGroup.Point().Base())
+
+			partials := make([]*share.PubShare, n)
+			for i, s := range poly.Shares(n) {
+				p, err := tc.extract(suite, s, ID)
+				require.NoError(t, err)
+				require.NoError(t, tc.verify(suite, pub, ID, p))
+				partials[i] = p
+			}
+
+			// a partial key of another identity or of another share is rejected
+			other, err := tc.extract(suite, poly.Shares(n)[0], []byte("bob@example.com"))
+			require.NoError(t, err)
+			require.ErrorIs(t, tc.verify(suite, pub, ID, other), ErrInvalidPartialKey)
+			partials[1].I = 2
+			require.ErrorIs(t, tc.verify(suite, pub, ID, partials[1]), ErrInvalidPartialKey)
+			require.ErrorIs(t, tc.verify(suite, pub, ID, nil), ErrInvalidPartialKey)
+
+			// and skipped when combining
+			key, err := tc.combine(suite, pub, ID, []*share.PubShare{other, partials[1], partials[3],
+				partials[0], partials[4]}, th, n)
+			require.NoError(t, err)
+			_, err = tc.combine(suite, pub, ID, []*share.PubShare{other, partials[1], partials[3],
+				partials[3], partials[4]}, th, n)
+			require.Error(t, err)
+
+			c, err := tc.encrypt(suite, pub.Commit(), ID, msg)
+			require.NoError(t, err)
+			decrypted, err := tc.decrypt(suite, key, c)
+			require.NoError(t, err)
+			require.Equal(t, msg, decrypted)
+		})
+	}
+}
+
+func TestTimelock(t *testing.T) {
+	suite := circl.NewSuiteBLS12381()
+	n, th := uint32(5), uint32(3)
+	round := uint64(1337)
+	msg := []byte("open me later")
+
+	for _, tc := range []struct {
+		name     string
+		keyGroup kyber.Group
+		scheme   sign.ThresholdScheme
+		encrypt  func(pairing.Suite, kyber.Point, uint64, []byte) (*Ciphertext, error)
+		decrypt  func(pairing.Suite, kyber.Point, uint64, []byte, *Ciphertext) ([]byte, error)
+	}{
+		{"OnG1", suite.G1(), tbls.NewThresholdSchemeOnG2(suite), EncryptTimelockOnG1, DecryptTimelockOnG1},
+		{"OnG2", suite.G2(), tbls.NewThresholdSchemeOnG1(suite), EncryptTimelockOnG2, DecryptTimelockOnG2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			poly := share.NewPriPoly(tc.keyGroup, th, nil, suite.RandomStream())
+			pub := poly.Commit(tc.keyGroup.Point().Base())
+			master := pub.Commit()
+
+			// encrypt to a future round
+			c, err := tc.encrypt(suite, master, round, msg)
+			require.NoError(t, err)
+
+			// the beacon signs the round
+			scheme := tc.scheme
+			var sigs [][]byte
+			for _, s := range poly.Shares(n)[:th] {
+				sig, err := scheme.Sign(s, RoundID(round))
+				require.NoError(t, err)
+				sigs = append(sigs, sig)
+			}
+			sig, err := scheme.Recover(pub, RoundID(round), sigs, th, n)
+			require.NoError(t, err)
+
+			decrypted, err := tc.decrypt(suite, master, round, sig, c)
+			require.NoError(t, err)
+			require.Equal(t, msg, decrypted)
+
+			// the signature of another round doesn't decrypt
+			_, err = tc.decrypt(suite, master, round+1, sig, c)
+			require.Error(t, err)
+			_, err = tc.decrypt(suite, master, round, sig[1:], c)
+			require.Error(t, err)
+		})
+	}
+}
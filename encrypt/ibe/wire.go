@@ -0,0 +1,55 @@
+package ibe
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/wire"
+)
+
+// Marshal returns the versioned binary encoding of the ciphertext.
+func (c *Ciphertext) Marshal() ([]byte, error) {
+	e := wire.NewEncoder(wire.TagIBECiphertext)
+	e.Point(c.U)
+	e.Bytes(c.V)
+	e.Bytes(c.W)
+	return e.Finish()
+}
+
+// Unmarshal decodes a ciphertext encoded by Marshal. The group g is the group
+// of the point U, i.e. G1 for EncryptCCAonG1 and G2 for EncryptCCAonG2.
+func (c *Ciphertext) Unmarshal(data []byte, g kyber.Group) error {
+	d := wire.NewDecoder(data, wire.TagIBECiphertext)
+	U := d.Point(g)
+	V := d.Bytes()
+	W := d.Bytes()
+	if len(V) != len(W) {
+		d.Fail(errors.New("ibe: sigma and message of different lengths"))
+	}
+	if err := d.Finish(); err != nil {
+		return err
+	}
+	*c = Ciphertext{U: U, V: V, W: W}
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the ciphertext.
+func (c *CiphertextCPA) Marshal() ([]byte, error) {
+	e := wire.NewEncoder(wire.TagIBECiphertextCPA)
+	e.Point(c.RP)
+	e.Bytes(c.C)
+	return e.Finish()
+}
+
+// Unmarshal decodes a ciphertext encoded by Marshal. The group g is the group
+// of the point RP, i.e. G1 for EncryptCPAonG1.
+func (c *CiphertextCPA) Unmarshal(data []byte, g kyber.Group) error {
+	d := wire.NewDecoder(data, wire.TagIBECiphertextCPA)
+	RP := d.Point(g)
+	C := d.Bytes()
+	if err := d.Finish(); err != nil {
+		return err
+	}
+	*c = CiphertextCPA{RP: RP, C: C}
+	return nil
+}
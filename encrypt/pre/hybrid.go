@@ -0,0 +1,160 @@
+package pre
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/hybrid"
+	"go.dedis.ch/kyber/v4/internal/stream"
+	"go.dedis.ch/kyber/v4/internal/wire"
+	"go.dedis.ch/kyber/v4/pairing"
+)
+
+// The hybrid mode encrypts payloads of any length: a random point of GT is
+// encrypted as a second-level ciphertext, and the payload is encrypted with
+// AES-256-GCM under a key derived from it, in chunks with the STREAM
+// construction. The encrypted payload is made of a header holding the encoded
+// ciphertext of the point, followed by the chunks. The proxy only transforms
+// the header, which is why the chunks don't authenticate it: the key would
+// change anyway if it was modified.
+
+// maxHybridHeader bounds the size of the ciphertext read from a header, which
+// holds a few elements of GT in the first-level case.
+const maxHybridHeader = 4096
+
+var hybridInfo = []byte("kyber-pre-hybrid-v1")
+
+// NewEncryptWriter returns a writer encrypting the data written to it to the
+// public key and writing the result to w. The writer must be closed to
+// complete the encryption; this doesn't close w.
+func NewEncryptWriter(s pairing.Suite, pk *PublicKey, w io.Writer) (io.WriteCloser, error) {
+	M := s.GT().Point().Mul(s.GT().Scalar().Pick(s.RandomStream()), base(s))
+	c := Encrypt(s, pk, M)
+	buf, err := c.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := wire.WriteBytes(w, wire.TagPREHybrid, buf); err != nil {
+		return nil, err
+	}
+	aead, err := hybridAEAD(M)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewWriter(aead, nil, w)
+}
+
+// NewDecryptReader returns a reader decrypting the data written by a writer
+// of NewEncryptWriter, or re-encrypted by ReEncryptHybrid, given the private
+// key it is encrypted or re-encrypted to. The decrypted data is only returned
+// once authenticated, and io.EOF is only returned at the authenticated end of
+// the data.
+func NewDecryptReader(s pairing.Suite, private kyber.Scalar, r io.Reader) (io.Reader, error) {
+	header, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	var M kyber.Point
+	second := new(SecondLevelCiphertext)
+	err = second.Unmarshal(header, s)
+	switch {
+	case err == nil:
+		M = second.Decrypt(s, private)
+	case errors.Is(err, wire.ErrTag):
+		first := new(FirstLevelCiphertext)
+		if err := first.Unmarshal(header, s); err != nil {
+			return nil, err
+		}
+		M = first.Decrypt(s, private)
+	default:
+		return nil, err
+	}
+	aead, err := hybridAEAD(M)
+	if err != nil {
+		return nil, err
+	}
+	return stream.NewReader(aead, nil, r)
+}
+
+// EncryptHybrid encrypts msg of any length to the public key. It is the
+// one-shot version of NewEncryptWriter.
+func EncryptHybrid(s pairing.Suite, pk *PublicKey, msg []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(s, pk, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecryptHybrid decrypts a ciphertext given by EncryptHybrid or
+// ReEncryptHybrid.
+func DecryptHybrid(s pairing.Suite, private kyber.Scalar, ciphertext []byte) ([]byte, error) {
+	r, err := NewDecryptReader(s, private, bytes.NewReader(ciphertext))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// ReEncryptStream re-encrypts the hybrid ciphertext read from r with the
+// re-encryption key rk, and writes the result to w. Only the header is
+// transformed, the encrypted payload is copied as is.
+func ReEncryptStream(s pairing.Suite, rk kyber.Point, w io.Writer, r io.Reader) error {
+	header, err := readHeader(r)
+	if err != nil {
+		return err
+	}
+	c := new(SecondLevelCiphertext)
+	if err := c.Unmarshal(header, s); err != nil {
+		return err
+	}
+	buf, err := ReEncrypt(s, rk, c).Marshal()
+	if err != nil {
+		return err
+	}
+	if _, err := wire.WriteBytes(w, wire.TagPREHybrid, buf); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// ReEncryptHybrid re-encrypts a ciphertext given by EncryptHybrid with the
+// re-encryption key rk. It is the one-shot version of ReEncryptStream.
+func ReEncryptHybrid(s pairing.Suite, rk kyber.Point, ciphertext []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ReEncryptStream(s, rk, &buf, bytes.NewReader(ciphertext)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readHeader reads the header and returns the encoded ciphertext it holds.
+func readHeader(r io.Reader) ([]byte, error) {
+	_, buf, err := wire.ReadBytes(r, wire.TagPREHybrid, maxHybridHeader)
+	if err != nil {
+		return nil, fmt.Errorf("pre: reading header: %w", err)
+	}
+	return buf, nil
+}
+
+// hybridAEAD returns the AES-256-GCM instance keyed by the encapsulated
+// point.
+func hybridAEAD(M kyber.Point) (cipher.AEAD, error) {
+	ikm, err := M.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return hybrid.NewAEAD(ikm, nil, hybridInfo)
+}
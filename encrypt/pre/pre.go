@@ -0,0 +1,176 @@
+// Package pre implements the unidirectional proxy re-encryption scheme of
+// Ateniese, Fu, Green and Hohenberger, "Improved Proxy Re-Encryption Schemes
+// with Applications to Secure Distributed Storage" (the third scheme), on
+// the asymmetric pairings of a pairing.Suite.
+//
+// A user A with private key a holds the public key (aG1, aG2). A message M of
+// GT is encrypted to A as the second-level ciphertext (r*aG1, M + rZ), where
+// Z = e(G1, G2). Given the public key of a user B, A computes the
+// re-encryption key (b/a)G2, with which a proxy transforms the second-level
+// ciphertexts of A into first-level ciphertexts (e(r*aG1, (b/a)G2), M + rZ)
+// = (rbZ, M + rZ) that B decrypts, without learning anything about M. The
+// re-encryption is unidirectional, and first-level ciphertexts can't be
+// re-encrypted again.
+//
+// Byte strings of any length are encrypted with EncryptHybrid, where a random
+// key is encapsulated with the scheme and only the encapsulation is
+// transformed by the proxy.
+package pre
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/wire"
+	"go.dedis.ch/kyber/v4/pairing"
+)
+
+// ErrInvalidPublicKey is returned when the points of a public key don't have
+// the same discrete logarithm.
+var ErrInvalidPublicKey = errors.New("pre: invalid public key")
+
+// PublicKey is the public key of the private key a.
+type PublicKey struct {
+	G1 kyber.Point // aG1
+	G2 kyber.Point // aG2
+}
+
+// NewKeyPair returns a random private key and its public key.
+func NewKeyPair(s pairing.Suite) (kyber.Scalar, *PublicKey) {
+	a := s.G1().Scalar().Pick(s.RandomStream())
+	return a, Public(s, a)
+}
+
+// Public returns the public key of the private key a.
+func Public(s pairing.Suite, a kyber.Scalar) *PublicKey {
+	return &PublicKey{
+		G1: s.G1().Point().Mul(a, nil),
+		G2: s.G2().Point().Mul(a, nil),
+	}
+}
+
+// Verify checks that the points of the public key have the same discrete
+// logarithm, i.e. that e(aG1, G2) == e(G1, aG2).
+func (pk *PublicKey) Verify(s pairing.Suite) error {
+	if pk.G1 == nil || pk.G2 == nil ||
+		!s.ValidatePairing(pk.G1, s.G2().Point().Base(), s.G1().Point().Base(), pk.G2) {
+		return ErrInvalidPublicKey
+	}
+	return nil
+}
+
+// ReKey returns the re-encryption key from the private key a to the public
+// key of B, which transforms the second-level ciphertexts encrypted to a into
+// first-level ciphertexts for B.
+func ReKey(s pairing.Suite, a kyber.Scalar, to *PublicKey) (kyber.Point, error) {
+	if err := to.Verify(s); err != nil {
+		return nil, err
+	}
+	inv := s.G2().Scalar().Inv(a)
+	return s.G2().Point().Mul(inv, to.G2), nil
+}
+
+// SecondLevelCiphertext is a ciphertext which can be re-encrypted.
+type SecondLevelCiphertext struct {
+	C1 kyber.Point // r*aG1 on G1
+	C2 kyber.Point // M + rZ on GT
+}
+
+// FirstLevelCiphertext is a ciphertext which can't be re-encrypted, either
+// encrypted with EncryptFirstLevel or re-encrypted from a second-level one.
+type FirstLevelCiphertext struct {
+	C1 kyber.Point // rbZ on GT
+	C2 kyber.Point // M + rZ on GT
+}
+
+// base returns Z = e(G1, G2).
+func base(s pairing.Suite) kyber.Point {
+	return s.Pair(s.G1().Point().Base(), s.G2().Point().Base())
+}
+
+// Encrypt encrypts the point M of GT to the public key, as a second-level
+// ciphertext.
+func Encrypt(s pairing.Suite, pk *PublicKey, M kyber.Point) *SecondLevelCiphertext {
+	r := s.G1().Scalar().Pick(s.RandomStream())
+	rZ := s.GT().Point().Mul(r, base(s))
+	return &SecondLevelCiphertext{
+		C1: s.G1().Point().Mul(r, pk.G1),
+		C2: rZ.Add(rZ, M),
+	}
+}
+
+// EncryptFirstLevel encrypts the point M of GT to the public key, as a
+// first-level ciphertext which can't be re-encrypted.
+func EncryptFirstLevel(s pairing.Suite, pk *PublicKey, M kyber.Point) *FirstLevelCiphertext {
+	r := s.G1().Scalar().Pick(s.RandomStream())
+	rZ := s.GT().Point().Mul(r, base(s))
+	return &FirstLevelCiphertext{
+		C1: s.GT().Point().Mul(r, s.Pair(pk.G1, s.G2().Point().Base())),
+		C2: rZ.Add(rZ, M),
+	}
+}
+
+// ReEncrypt transforms the second-level ciphertext with the re-encryption key
+// rk into a first-level ciphertext of the same message for the delegatee.
+func ReEncrypt(s pairing.Suite, rk kyber.Point, c *SecondLevelCiphertext) *FirstLevelCiphertext {
+	return &FirstLevelCiphertext{
+		C1: s.Pair(c.C1, rk),
+		C2: c.C2.Clone(),
+	}
+}
+
+// Decrypt returns the message of the ciphertext, given the private key it is
+// encrypted to.
+func (c *SecondLevelCiphertext) Decrypt(s pairing.Suite, a kyber.Scalar) kyber.Point {
+	// e(r*aG1, G2) = raZ
+	rZ := s.Pair(c.C1, s.G2().Point().Base())
+	rZ.Mul(s.GT().Scalar().Inv(a), rZ)
+	return rZ.Sub(c.C2, rZ)
+}
+
+// Decrypt returns the message of the ciphertext, given the private key it is
+// encrypted or re-encrypted to.
+func (c *FirstLevelCiphertext) Decrypt(s pairing.Suite, b kyber.Scalar) kyber.Point {
+	rZ := s.GT().Point().Mul(s.GT().Scalar().Inv(b), c.C1)
+	return rZ.Sub(c.C2, rZ)
+}
+
+// Marshal returns the versioned binary encoding of the ciphertext.
+func (c *SecondLevelCiphertext) Marshal() ([]byte, error) {
+	e := wire.NewEncoder(wire.TagPRESecondLevel)
+	e.Point(c.C1)
+	e.Point(c.C2)
+	return e.Finish()
+}
+
+// Unmarshal decodes a ciphertext encoded by Marshal.
+func (c *SecondLevelCiphertext) Unmarshal(data []byte, s pairing.Suite) error {
+	d := wire.NewDecoder(data, wire.TagPRESecondLevel)
+	C1 := d.Point(s.G1())
+	C2 := d.Point(s.GT())
+	if err := d.Finish(); err != nil {
+		return err
+	}
+	*c = SecondLevelCiphertext{C1: C1, C2: C2}
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the ciphertext.
+func (c *FirstLevelCiphertext) Marshal() ([]byte, error) {
+	e := wire.NewEncoder(wire.TagPREFirstLevel)
+	e.Point(c.C1)
+	e.Point(c.C2)
+	return e.Finish()
+}
+
+// Unmarshal decodes a ciphertext encoded by Marshal.
+func (c *FirstLevelCiphertext) Unmarshal(data []byte, s pairing.Suite) error {
+	d := wire.NewDecoder(data, wire.TagPREFirstLevel)
+	C1 := d.Point(s.GT())
+	C2 := d.Point(s.GT())
+	if err := d.Finish(); err != nil {
+		return err
+	}
+	*c = FirstLevelCiphertext{C1: C1, C2: C2}
+	return nil
+}
@@ -0,0 +1,126 @@
+package pre
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/internal/stream"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+)
+
+func TestPRE(t *testing.T) {
+	testPRE(t, circl.NewSuiteBLS12381())
+}
+
+func TestHybrid(t *testing.T) {
+	testHybrid(t, circl.NewSuiteBLS12381())
+}
+
+func testPRE(t *testing.T, s pairing.Suite) {
+	a, pkA := NewKeyPair(s)
+	b, pkB := NewKeyPair(s)
+	require.NoError(t, pkA.Verify(s))
+	M := s.GT().Point().Mul(s.GT().Scalar().Pick(s.RandomStream()), base(s))
+
+	c := Encrypt(s, pkA, M)
+	require.True(t, M.Equal(c.Decrypt(s, a)))
+	require.False(t, M.Equal(c.Decrypt(s, b)))
+
+	rk, err := ReKey(s, a, pkB)
+	require.NoError(t, err)
+	re := ReEncrypt(s, rk, c)
+	require.True(t, M.Equal(re.Decrypt(s, b)))
+	require.False(t, M.Equal(re.Decrypt(s, a)))
+
+	first := EncryptFirstLevel(s, pkB, M)
+	require.True(t, M.Equal(first.Decrypt(s, b)))
+
+	// the re-encryption key requires a valid public key
+	_, err = ReKey(s, a, &PublicKey{G1: pkB.G1, G2: pkA.G2})
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	// encoding
+	buf, err := c.Marshal()
+	require.NoError(t, err)
+	decoded := new(SecondLevelCiphertext)
+	require.NoError(t, decoded.Unmarshal(buf, s))
+	require.True(t, M.Equal(decoded.Decrypt(s, a)))
+	require.Error(t, decoded.Unmarshal(buf[:len(buf)-1], s))
+	require.Error(t, new(FirstLevelCiphertext).Unmarshal(buf, s))
+
+	buf, err = re.Marshal()
+	require.NoError(t, err)
+	decodedFirst := new(FirstLevelCiphertext)
+	require.NoError(t, decodedFirst.Unmarshal(buf, s))
+	require.True(t, M.Equal(decodedFirst.Decrypt(s, b)))
+	require.Error(t, decoded.Unmarshal(buf, s))
+}
+
+func testHybrid(t *testing.T, s pairing.Suite) {
+	a, pkA := NewKeyPair(s)
+	b, pkB := NewKeyPair(s)
+	rk, err := ReKey(s, a, pkB)
+	require.NoError(t, err)
+
+	for _, size := range []int{0, 100, 2*stream.ChunkSize + 1} {
+		msg := make([]byte, size)
+		_, _ = rand.Read(msg)
+		c, err := EncryptHybrid(s, pkA, msg)
+		require.NoError(t, err)
+		plain, err := DecryptHybrid(s, a, c)
+		require.NoError(t, err)
+		require.Equal(t, msg, append([]byte{}, plain...))
+
+		re, err := ReEncryptHybrid(s, rk, c)
+		require.NoError(t, err)
+		plain, err = DecryptHybrid(s, b, re)
+		require.NoError(t, err)
+		require.Equal(t, msg, append([]byte{}, plain...))
+
+		// only the delegatee decrypts, and first-level ciphertexts can't be
+		// re-encrypted
+		_, err = DecryptHybrid(s, b, c)
+		require.Error(t, err)
+		_, err = DecryptHybrid(s, a, re)
+		require.Error(t, err)
+		_, err = ReEncryptHybrid(s, rk, re)
+		require.Error(t, err)
+	}
+
+	// streamed
+	msg := make([]byte, 3*stream.ChunkSize/2)
+	_, _ = rand.Read(msg)
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(s, pkA, &buf)
+	require.NoError(t, err)
+	_, err = io.Copy(w, bytes.NewReader(msg))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	var re bytes.Buffer
+	require.NoError(t, ReEncryptStream(s, rk, &re, &buf))
+	r, err := NewDecryptReader(s, b, &re)
+	require.NoError(t, err)
+	plain, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, msg, plain)
+
+	// tampered header or payload
+	c, err := EncryptHybrid(s, pkA, msg)
+	require.NoError(t, err)
+	c[10] ^= 1
+	_, err = DecryptHybrid(s, a, c)
+	require.Error(t, err)
+	c[10] ^= 1
+	c[len(c)-1] ^= 1
+	_, err = DecryptHybrid(s, a, c)
+	require.Error(t, err)
+	c[len(c)-1] ^= 1
+	_, err = DecryptHybrid(s, a, c[:len(c)-20])
+	require.Error(t, err)
+	_, err = DecryptHybrid(s, a, c[:5])
+	require.Error(t, err)
+}
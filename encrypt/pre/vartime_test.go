@@ -0,0 +1,14 @@
+//go:build !constantTime
+
+package pre
+
+import (
+	"testing"
+
+	"go.dedis.ch/kyber/v4/pairing/bn254"
+)
+
+func TestPREBN254(t *testing.T) {
+	testPRE(t, bn254.NewSuite())
+	testHybrid(t, bn254.NewSuite())
+}
@@ -0,0 +1,192 @@
+// Package threshold implements threshold ElGamal decryption. A message is
+// encrypted to the collective public key X = xG of a group of n participants
+// holding the shares x_i of the private key x, typically the output of a
+// distributed key generation (see kyber/share/dkg). To decrypt a ciphertext,
+// at least t participants each compute a partial decryption x_i*K of the
+// ephemeral key K of the ciphertext, together with a DLEQ proof that it has
+// been computed with the key share whose public counterpart X_i is given by
+// the public sharing polynomial. Anyone can then check the partial
+// decryptions and combine t valid ones with Lagrange interpolation into xK,
+// which decrypts the ciphertext.
+//
+// Points are encrypted with plain ElGamal by Encrypt, and byte strings of any
+// length with a hybrid scheme by EncryptHybrid, where the key of an AEAD is
+// derived from the ElGamal shared secret.
+//
+// Note that participants publishing partial decryptions of any ciphertext
+// act as a decryption oracle, so they should only decrypt the ciphertexts
+// that their application has decided to open.
+package threshold
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/hybrid"
+	"go.dedis.ch/kyber/v4/proof/dleq"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// Suite describes the functionalities needed by this package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// hybridInfo is the HKDF info of the key derivation of the hybrid scheme.
+var hybridInfo = []byte("kyber-threshold-hybrid-v1")
+
+var ErrInvalidPartial = errors.New("invalid partial decryption")
+var ErrTooFewPartials = errors.New("not enough valid partial decryptions")
+
+// Ciphertext is an ElGamal encryption of a point.
+type Ciphertext struct {
+	K kyber.Point // Ephemeral key rG
+	C kyber.Point // Encrypted message M + rX
+}
+
+// HybridCiphertext is a hybrid encryption of a byte string.
+type HybridCiphertext struct {
+	K    kyber.Point // Ephemeral key rG
+	Data []byte      // Message encrypted with a key derived from rX
+}
+
+// Partial is the partial decryption of a ciphertext by a participant.
+type Partial struct {
+	I     uint32      // Index of the key share of the participant
+	V     kyber.Point // Partial decryption x_i*K
+	Proof dleq.Proof  // Proof that log_G(X_i) == log_K(V)
+}
+
+// Encrypt encrypts the point msg to the public key.
+func Encrypt(suite Suite, public, msg kyber.Point) *Ciphertext {
+	r := suite.Scalar().Pick(suite.RandomStream())
+	return &Ciphertext{
+		K: suite.Point().Mul(r, nil),
+		C: suite.Point().Add(msg, suite.Point().Mul(r, public)),
+	}
+}
+
+// Open returns the message of the ciphertext, given the shared secret xK
+// returned by Combine.
+func (c *Ciphertext) Open(suite Suite, secret kyber.Point) kyber.Point {
+	return suite.Point().Sub(c.C, secret)
+}
+
+// EncryptHybrid encrypts msg to the public key with AES-256-GCM, under a key
+// derived from the ElGamal shared secret. The associated data ad, which may be
+// nil, is authenticated but not encrypted and must be given again to Open.
+func EncryptHybrid(suite Suite, public kyber.Point, msg, ad []byte) (*HybridCiphertext, error) {
+	r := suite.Scalar().Pick(suite.RandomStream())
+	K := suite.Point().Mul(r, nil)
+	aead, err := hybridAEAD(K, suite.Point().Mul(r, public))
+	if err != nil {
+		return nil, err
+	}
+	return &HybridCiphertext{K: K, Data: hybrid.Seal(aead, msg, ad)}, nil
+}
+
+// Open decrypts the ciphertext, given the shared secret xK returned by
+// Combine and the associated data given to EncryptHybrid.
+func (c *HybridCiphertext) Open(secret kyber.Point, ad []byte) ([]byte, error) {
+	aead, err := hybridAEAD(c.K, secret)
+	if err != nil {
+		return nil, err
+	}
+	return hybrid.Open(aead, c.Data, ad)
+}
+
+// PartialDecrypt computes the partial decryption of the ephemeral key K of a
+// ciphertext with the given key share.
+func PartialDecrypt(suite Suite, private *share.PriShare, K kyber.Point) (*Partial, error) {
+	proof, _, V, err := dleq.NewDLEQProof(suite, suite.Point().Base(), K, private.V)
+	if err != nil {
+		return nil, err
+	}
+	return &Partial{I: private.I, V: V, Proof: *proof}, nil
+}
+
+// VerifyPartial checks the partial decryption of the ephemeral key K against
+// the public key share of the participant, given by the public sharing
+// polynomial.
+func VerifyPartial(suite Suite, public *share.PubPoly, K kyber.Point, p *Partial) error {
+	if p == nil || p.V == nil || p.Proof.C == nil || p.Proof.R == nil ||
+		p.Proof.VG == nil || p.Proof.VH == nil {
+		return fmt.Errorf("%w: incomplete", ErrInvalidPartial)
+	}
+	X := public.Eval(p.I).V
+	if err := p.Proof.Verify(suite, suite.Point().Base(), K, X, p.V); err != nil {
+		return fmt.Errorf("%w %d: %w", ErrInvalidPartial, p.I, err)
+	}
+	return nil
+}
+
+// Combine verifies the partial decryptions of the ephemeral key K and
+// recovers the shared secret xK from t valid ones with Lagrange
+// interpolation. Invalid partial decryptions are ignored.
+func Combine(suite Suite, public *share.PubPoly, K kyber.Point, partials []*Partial, t, n uint32) (kyber.Point, error) {
+	var shares []*share.PubShare
+	seen := make(map[uint32]bool, t)
+	for _, p := range partials {
+		if p == nil || seen[p.I] {
+			continue
+		}
+		if err := VerifyPartial(suite, public, K, p); err != nil {
+			continue
+		}
+		seen[p.I] = true
+		shares = append(shares, &share.PubShare{I: p.I, V: p.V})
+		if uint32(len(shares)) >= t {
+			break
+		}
+	}
+	if uint32(len(shares)) < t {
+		return nil, ErrTooFewPartials
+	}
+	return share.RecoverCommit(suite, shares, t, n)
+}
+
+// CombineStrict is like Combine, but all the partial decryptions must be
+// valid: it returns ErrInvalidPartial as soon as one of them is nil, invalid,
+// out of range or given twice, instead of ignoring it. It is meant for
+// protocols where every participant is accountable for what it publishes.
+func CombineStrict(suite Suite, public *share.PubPoly, K kyber.Point, partials []*Partial, t, n uint32) (
+	kyber.Point, error) {
+	shares := make([]*share.PubShare, 0, len(partials))
+	seen := make(map[uint32]bool, len(partials))
+	for _, p := range partials {
+		if p == nil {
+			return nil, fmt.Errorf("%w: nil", ErrInvalidPartial)
+		}
+		if p.I >= n || seen[p.I] {
+			return nil, fmt.Errorf("%w %d: unexpected index", ErrInvalidPartial, p.I)
+		}
+		if err := VerifyPartial(suite, public, K, p); err != nil {
+			return nil, err
+		}
+		seen[p.I] = true
+		shares = append(shares, &share.PubShare{I: p.I, V: p.V})
+	}
+	if uint32(len(shares)) < t {
+		return nil, ErrTooFewPartials
+	}
+	return share.RecoverCommit(suite, shares, t, n)
+}
+
+// hybridAEAD returns the AES-256-GCM instance keyed by the shared secret of
+// the ephemeral key K.
+func hybridAEAD(K, secret kyber.Point) (cipher.AEAD, error) {
+	ikm, err := secret.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	salt, err := K.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return hybrid.NewAEAD(ikm, salt, hybridInfo)
+}
@@ -0,0 +1,124 @@
+package threshold
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+const n, t = 7, 4
+
+func genKey() ([]*share.PriShare, *share.PubPoly) {
+	poly := share.NewPriPoly(suite, t, nil, suite.RandomStream())
+	return poly.Shares(n), poly.Commit(nil)
+}
+
+func partials(test *testing.T, shares []*share.PriShare, K kyber.Point) []*Partial {
+	ps := make([]*Partial, len(shares))
+	for i, s := range shares {
+		p, err := PartialDecrypt(suite, s, K)
+		require.NoError(test, err)
+		ps[i] = p
+	}
+	return ps
+}
+
+func TestThresholdElGamal(test *testing.T) {
+	shares, pub := genKey()
+	msg := suite.Point().Pick(suite.RandomStream())
+	c := Encrypt(suite, pub.Commit(), msg)
+
+	ps := partials(test, shares, c.K)
+	for _, p := range ps {
+		require.NoError(test, VerifyPartial(suite, pub, c.K, p))
+	}
+
+	// any t of the partial decryptions
+	secret, err := Combine(suite, pub, c.K, ps[n-t:], t, n)
+	require.NoError(test, err)
+	require.True(test, msg.Equal(c.Open(suite, secret)))
+
+	// but not less
+	_, err = Combine(suite, pub, c.K, ps[:t-1], t, n)
+	require.ErrorIs(test, err, ErrTooFewPartials)
+}
+
+func TestThresholdHybrid(test *testing.T) {
+	shares, pub := genKey()
+	msg := []byte("the eagle has landed")
+	ad := []byte("round 42")
+	c, err := EncryptHybrid(suite, pub.Commit(), msg, ad)
+	require.NoError(test, err)
+
+	ps := partials(test, shares, c.K)
+	secret, err := Combine(suite, pub, c.K, ps, t, n)
+	require.NoError(test, err)
+	plain, err := c.Open(secret, ad)
+	require.NoError(test, err)
+	require.Equal(test, msg, plain)
+
+	_, err = c.Open(secret, []byte("round 43"))
+	require.Error(test, err)
+	c.Data[0] ^= 1
+	_, err = c.Open(secret, ad)
+	require.Error(test, err)
+}
+
+func TestThresholdInvalidPartials(test *testing.T) {
+	shares, pub := genKey()
+	msg := suite.Point().Pick(suite.RandomStream())
+	c := Encrypt(suite, pub.Commit(), msg)
+	ps := partials(test, shares, c.K)
+
+	// a wrong value, a partial for another index, a partial of another
+	// ciphertext and a duplicate are all rejected
+	ps[0].V = suite.Point().Pick(suite.RandomStream())
+	require.ErrorIs(test, VerifyPartial(suite, pub, c.K, ps[0]), ErrInvalidPartial)
+	ps[1].I = 6
+	require.ErrorIs(test, VerifyPartial(suite, pub, c.K, ps[1]), ErrInvalidPartial)
+	other := Encrypt(suite, pub.Commit(), msg)
+	ps[2], _ = PartialDecrypt(suite, shares[2], other.K)
+	require.ErrorIs(test, VerifyPartial(suite, pub, c.K, ps[2]), ErrInvalidPartial)
+	require.ErrorIs(test, VerifyPartial(suite, pub, c.K, &Partial{}), ErrInvalidPartial)
+
+	_, err := Combine(suite, pub, c.K, []*Partial{ps[0], ps[1], ps[2], ps[3], ps[3], nil}, t, n)
+	require.ErrorIs(test, err, ErrTooFewPartials)
+
+	// the invalid partials are skipped
+	secret, err := Combine(suite, pub, c.K, ps, t, n)
+	require.NoError(test, err)
+	require.True(test, msg.Equal(c.Open(suite, secret)))
+}
+
+func TestThresholdCombineStrict(test *testing.T) {
+	shares, pub := genKey()
+	msg := suite.Point().Pick(suite.RandomStream())
+	c := Encrypt(suite, pub.Commit(), msg)
+	ps := partials(test, shares, c.K)
+
+	// all the partial decryptions are used
+	secret, err := CombineStrict(suite, pub, c.K, ps, t, n)
+	require.NoError(test, err)
+	require.True(test, msg.Equal(c.Open(suite, secret)))
+	_, err = CombineStrict(suite, pub, c.K, ps[:t-1], t, n)
+	require.ErrorIs(test, err, ErrTooFewPartials)
+
+	// and a single invalid one is enough to fail
+	_, err = CombineStrict(suite, pub, c.K, append(ps[:t:t], nil), t, n)
+	require.ErrorIs(test, err, ErrInvalidPartial)
+	_, err = CombineStrict(suite, pub, c.K, append(ps[:t:t], ps[0]), t, n)
+	require.ErrorIs(test, err, ErrInvalidPartial)
+	bad := *ps[n-1]
+	bad.V = suite.Point().Pick(suite.RandomStream())
+	_, err = CombineStrict(suite, pub, c.K, append(ps[:t:t], &bad), t, n)
+	require.ErrorIs(test, err, ErrInvalidPartial)
+	bad = *ps[n-1]
+	bad.I = n
+	_, err = CombineStrict(suite, pub, c.K, append(ps[:t:t], &bad), t, n)
+	require.ErrorIs(test, err, ErrInvalidPartial)
+}
@@ -0,0 +1,48 @@
+// Package hybrid derives the AEAD of the hybrid encryption schemes, which
+// encrypt a payload with AES-256-GCM under a key derived with HKDF-SHA256
+// from a secret: a group element, a shared scalar or an encapsulated key.
+//
+// The derived key is fresh for every payload, which is why a payload of a
+// single message is encrypted with a fixed nonce by Seal, while the payloads
+// of any length are encrypted in chunks by package internal/stream.
+package hybrid
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// keySize is the size of an AES-256 key.
+const keySize = 32
+
+// NewAEAD returns the AES-256-GCM instance keyed by HKDF-SHA256 of the secret
+// ikm, with the given salt, which may be nil, and info, which separates the
+// schemes. The AEAD must only encrypt a single payload.
+func NewAEAD(ikm, salt, info []byte) (cipher.AEAD, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, info), key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal encrypts msg and authenticates ad with the AEAD returned by NewAEAD.
+// The key is fresh so the nonce can be fixed.
+func Seal(aead cipher.AEAD, msg, ad []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(nil, nonce, msg, ad)
+}
+
+// Open decrypts a ciphertext given by Seal.
+func Open(aead cipher.AEAD, ciphertext, ad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Open(nil, nonce, ciphertext, ad)
+}
@@ -0,0 +1,32 @@
+package hybrid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealOpen(t *testing.T) {
+	info := []byte("kyber-hybrid-test")
+	aead, err := NewAEAD([]byte("secret"), nil, info)
+	require.NoError(t, err)
+	c := Seal(aead, []byte("message"), []byte("ad"))
+
+	msg, err := Open(aead, c, []byte("ad"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("message"), msg)
+	_, err = Open(aead, c, nil)
+	require.Error(t, err)
+
+	// the key depends on the secret, the salt and the info
+	for _, args := range [][3][]byte{
+		{[]byte("other secret"), nil, info},
+		{[]byte("secret"), []byte("salt"), info},
+		{[]byte("secret"), nil, []byte("kyber-hybrid-other")},
+	} {
+		other, err := NewAEAD(args[0], args[1], args[2])
+		require.NoError(t, err)
+		_, err = Open(other, c, []byte("ad"))
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,115 @@
+// Package msm computes multi-scalar multiplications, i.e. sums of points
+// multiplied by scalars, with the bucket method of Pippenger. It is much
+// faster than multiplying the points one by one when there are many of them,
+// but it runs in variable time and must only be used on public inputs,
+// typically to verify proofs.
+package msm
+
+import (
+	"math/bits"
+	"slices"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// naiveThreshold is the number of points under which the products are
+// computed one by one.
+const naiveThreshold = 16
+
+// MultiMul returns the sum of the points multiplied by the scalars of the
+// same index. It panics if the slices have different lengths.
+func MultiMul(g kyber.Group, scalars []kyber.Scalar, points []kyber.Point) kyber.Point {
+	if len(scalars) != len(points) {
+		panic("msm: scalars and points of different lengths")
+	}
+	sum := g.Point().Null()
+	if len(points) < naiveThreshold {
+		tmp := g.Point()
+		for i, P := range points {
+			sum.Add(sum, tmp.Mul(scalars[i], P))
+		}
+		return sum
+	}
+
+	digits := make([][]byte, len(scalars))
+	maxLen := 0
+	for i, s := range scalars {
+		digits[i] = littleEndian(s)
+		maxLen = max(maxLen, len(digits[i]))
+	}
+	c := max(2, bits.Len(uint(len(points)))-2)
+	windows := (8*maxLen + c - 1) / c
+	buckets := make([]kyber.Point, 1<<c-1)
+	running := g.Point()
+	window := g.Point()
+	for w := windows - 1; w >= 0; w-- {
+		for range c {
+			sum.Add(sum, sum)
+		}
+		clear(buckets)
+		for i, P := range points {
+			d := digit(digits[i], w*c, c)
+			if d == 0 {
+				continue
+			}
+			if buckets[d-1] == nil {
+				buckets[d-1] = P.Clone()
+			} else {
+				buckets[d-1].Add(buckets[d-1], P)
+			}
+		}
+		// the sum of k*bucket[k-1] is the sum of the running sums from the
+		// top bucket down
+		running.Null()
+		window.Null()
+		for k := len(buckets) - 1; k >= 0; k-- {
+			if buckets[k] != nil {
+				running.Add(running, buckets[k])
+			}
+			window.Add(window, running)
+		}
+		sum.Add(sum, window)
+	}
+	return sum
+}
+
+// PrimeOrder tells whether the group reports, with an IsPrimeOrder or a
+// PrimeOrder method, that it has a prime order. Checks combining several equations with random
+// weights, typically computed with MultiMul, are only sound in such groups:
+// in a group with a cofactor, a component of small order added to a point
+// cancels out whenever its weight is a multiple of its order, which happens
+// with a probability of at least 1/8 on edwards25519.
+func PrimeOrder(g kyber.Group) bool {
+	switch po := g.(type) {
+	case interface{ IsPrimeOrder() bool }:
+		return po.IsPrimeOrder()
+	case interface{ PrimeOrder() bool }:
+		return po.PrimeOrder()
+	}
+	return false
+}
+
+// littleEndian returns the little-endian encoding of the scalar.
+func littleEndian(s kyber.Scalar) []byte {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		panic("msm: scalar encoding: " + err.Error())
+	}
+	if s.ByteOrder() == kyber.BigEndian {
+		slices.Reverse(b)
+	}
+	return b
+}
+
+// digit returns the c bits of b from the bit offset.
+func digit(b []byte, offset, c int) int {
+	d := 0
+	for i := c - 1; i >= 0; i-- {
+		bit := offset + i
+		d <<= 1
+		if bit/8 < len(b) {
+			d |= int(b[bit/8]>>(bit%8)) & 1
+		}
+	}
+	return d
+}
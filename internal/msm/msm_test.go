@@ -0,0 +1,34 @@
+package msm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+)
+
+func TestMultiMul(t *testing.T) {
+	circlSuite := circl.NewSuiteBLS12381()
+	for _, g := range []kyber.Group{edwards25519.NewBlakeSHA256Ed25519(), circlSuite.G1()} {
+		rand := edwards25519.NewBlakeSHA256Ed25519().RandomStream()
+		for _, n := range []int{0, 1, 15, 16, 17, 100, 300} {
+			scalars := make([]kyber.Scalar, n)
+			points := make([]kyber.Point, n)
+			expected := g.Point().Null()
+			for i := range n {
+				scalars[i] = g.Scalar().Pick(rand)
+				if i%7 == 0 {
+					scalars[i].SetInt64(int64(i))
+				}
+				points[i] = g.Point().Pick(rand)
+				expected.Add(expected, g.Point().Mul(scalars[i], points[i]))
+			}
+			require.True(t, expected.Equal(MultiMul(g, scalars, points)), "%s %d", g, n)
+		}
+	}
+	require.Panics(t, func() {
+		MultiMul(edwards25519.NewBlakeSHA256Ed25519(), nil, []kyber.Point{nil})
+	})
+}
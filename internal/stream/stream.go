@@ -0,0 +1,218 @@
+// Package stream implements the STREAM construction of "Online
+// Authenticated-Encryption and its Nonce-Reuse Misuse-Resistance" by Hoang,
+// Reyhanitabar, Rogaway and Vizár, which encrypts a payload of any length
+// with an AEAD in chunks, so that it can be processed without holding it
+// entirely in memory while still detecting truncated, reordered or modified
+// chunks.
+//
+// The payload is split in chunks of ChunkSize bytes, the last one being
+// possibly shorter or empty. The nonce of a chunk is its index as an 11 bytes
+// big-endian counter followed by a byte set to 1 for the last chunk and to 0
+// otherwise. The AEAD key must never be used for anything else.
+package stream
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"errors"
+	"io"
+)
+
+// ChunkSize is the size of the plaintext of all the chunks but the last.
+const ChunkSize = 64 * 1024
+
+// NonceSize is the nonce size the AEAD must use.
+const NonceSize = 12
+
+// ErrTruncated is returned when the encrypted payload ends before its last
+// chunk.
+var ErrTruncated = errors.New("stream: truncated payload")
+
+// ErrAuth is returned when a chunk doesn't decrypt.
+var ErrAuth = errors.New("stream: authentication failed")
+
+type nonce [NonceSize]byte
+
+// next increments the counter of the nonce.
+func (n *nonce) next() error {
+	for i := NonceSize - 2; i >= 0; i-- {
+		n[i]++
+		if n[i] != 0 {
+			return nil
+		}
+	}
+	return errors.New("stream: too many chunks")
+}
+
+func (n *nonce) setLast() {
+	n[NonceSize-1] = 1
+}
+
+// Writer encrypts the data written to it. Close must be called to write the
+// last chunk.
+type Writer struct {
+	aead   cipher.AEAD
+	ad     []byte
+	w      io.Writer
+	nonce  nonce
+	buf    []byte
+	err    error
+	closed bool
+}
+
+// NewWriter returns a Writer encrypting to w with the AEAD, whose nonce size
+// must be NonceSize. The associated data ad, which may be nil, is
+// authenticated with every chunk.
+func NewWriter(aead cipher.AEAD, ad []byte, w io.Writer) (*Writer, error) {
+	if aead.NonceSize() != NonceSize {
+		return nil, errors.New("stream: invalid AEAD nonce size")
+	}
+	return &Writer{
+		aead: aead,
+		ad:   ad,
+		w:    w,
+		buf:  make([]byte, 0, ChunkSize+aead.Overhead()),
+	}, nil
+}
+
+// Write encrypts p. A chunk is only written once it is full and more data
+// follows, since the last chunk is only known on Close.
+func (s *Writer) Write(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	if s.closed {
+		return 0, errors.New("stream: write after close")
+	}
+	n := 0
+	for len(p) > 0 {
+		if len(s.buf) == ChunkSize {
+			if err := s.flush(false); err != nil {
+				return n, err
+			}
+		}
+		k := min(ChunkSize-len(s.buf), len(p))
+		s.buf = append(s.buf, p[:k]...)
+		p = p[k:]
+		n += k
+	}
+	return n, nil
+}
+
+// Close writes the last chunk. It doesn't close the underlying writer.
+func (s *Writer) Close() error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.flush(true)
+}
+
+func (s *Writer) flush(last bool) error {
+	if last {
+		s.nonce.setLast()
+	}
+	out := s.aead.Seal(s.buf[:0], s.nonce[:], s.buf, s.ad)
+	if _, err := s.w.Write(out); err != nil {
+		s.err = err
+		return err
+	}
+	s.buf = s.buf[:0]
+	if !last {
+		if err := s.nonce.next(); err != nil {
+			s.err = err
+			return err
+		}
+	}
+	return nil
+}
+
+// Reader decrypts the data read from an underlying reader.
+type Reader struct {
+	aead  cipher.AEAD
+	ad    []byte
+	r     *bufio.Reader
+	nonce nonce
+	chunk []byte
+	plain []byte
+	err   error
+}
+
+// NewReader returns a Reader decrypting the data written by a Writer with the
+// same AEAD and associated data. Decrypted data is only returned once its
+// chunk has been authenticated, and the end of the payload is only reported
+// after the last chunk has been authenticated.
+func NewReader(aead cipher.AEAD, ad []byte, r io.Reader) (*Reader, error) {
+	if aead.NonceSize() != NonceSize {
+		return nil, errors.New("stream: invalid AEAD nonce size")
+	}
+	return &Reader{
+		aead:  aead,
+		ad:    ad,
+		r:     bufio.NewReader(r),
+		chunk: make([]byte, ChunkSize+aead.Overhead()),
+	}, nil
+}
+
+// Read decrypts data into p.
+func (s *Reader) Read(p []byte) (int, error) {
+	for len(s.plain) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		if err := s.next(); err != nil {
+			s.err = err
+		}
+	}
+	n := copy(p, s.plain)
+	s.plain = s.plain[n:]
+	return n, nil
+}
+
+// next decrypts the next chunk into s.plain and returns io.EOF after the last
+// chunk.
+func (s *Reader) next() error {
+	n, err := io.ReadFull(s.r, s.chunk)
+	switch {
+	case errors.Is(err, io.EOF):
+		return ErrTruncated
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		// a short chunk is the last one
+		return s.open(s.chunk[:n], true)
+	case err != nil:
+		return err
+	}
+	// a full chunk is the last one if nothing follows
+	if _, err := s.r.Peek(1); errors.Is(err, io.EOF) {
+		return s.open(s.chunk, true)
+	} else if err != nil {
+		return err
+	}
+	return s.open(s.chunk, false)
+}
+
+func (s *Reader) open(chunk []byte, last bool) error {
+	if len(chunk) < s.aead.Overhead() {
+		return ErrTruncated
+	}
+	if last {
+		s.nonce.setLast()
+	}
+	plain, err := s.aead.Open(chunk[:0], s.nonce[:], chunk, s.ad)
+	if err != nil {
+		return ErrAuth
+	}
+	s.plain = plain
+	if last {
+		if len(plain) == 0 {
+			return io.EOF
+		}
+		// io.EOF is returned once the plaintext has been read
+		s.err = io.EOF
+		return nil
+	}
+	return s.nonce.next()
+}
@@ -0,0 +1,103 @@
+package stream
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newAEAD(t *testing.T) cipher.AEAD {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	return aead
+}
+
+func encrypt(t *testing.T, aead cipher.AEAD, ad, msg []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(aead, ad, &buf)
+	require.NoError(t, err)
+	// write in uneven pieces
+	for len(msg) > 0 {
+		k := min(len(msg), 1000)
+		n, err := w.Write(msg[:k])
+		require.NoError(t, err)
+		require.Equal(t, k, n)
+		msg = msg[k:]
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+	_, err = w.Write([]byte{1})
+	require.Error(t, err)
+	return buf.Bytes()
+}
+
+func decrypt(aead cipher.AEAD, ad, ct []byte) ([]byte, error) {
+	r, err := NewReader(aead, ad, bytes.NewReader(ct))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestStream(t *testing.T) {
+	aead := newAEAD(t)
+	ad := []byte("header")
+	for _, size := range []int{0, 1, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3*ChunkSize + 17} {
+		msg := make([]byte, size)
+		_, _ = rand.Read(msg)
+		ct := encrypt(t, aead, ad, msg)
+		chunks := max(1, (size+ChunkSize-1)/ChunkSize)
+		require.Len(t, ct, size+chunks*aead.Overhead())
+
+		plain, err := decrypt(aead, ad, ct)
+		require.NoError(t, err, "size %d", size)
+		require.Equal(t, msg, append([]byte{}, plain...))
+
+		// wrong associated data
+		_, err = decrypt(aead, []byte("other"), ct)
+		require.ErrorIs(t, err, ErrAuth)
+	}
+}
+
+func TestStreamTampered(t *testing.T) {
+	aead := newAEAD(t)
+	msg := make([]byte, 2*ChunkSize+100)
+	ct := encrypt(t, aead, nil, msg)
+	full := ChunkSize + aead.Overhead()
+
+	// truncation at a chunk boundary, of the last chunk or of everything
+	_, err := decrypt(aead, nil, ct[:full])
+	require.ErrorIs(t, err, ErrAuth)
+	_, err = decrypt(aead, nil, ct[:2*full])
+	require.ErrorIs(t, err, ErrAuth)
+	_, err = decrypt(aead, nil, ct[:len(ct)-1])
+	require.ErrorIs(t, err, ErrAuth)
+	_, err = decrypt(aead, nil, nil)
+	require.ErrorIs(t, err, ErrTruncated)
+	_, err = decrypt(aead, nil, ct[:2*full+3])
+	require.ErrorIs(t, err, ErrTruncated)
+
+	// reordered chunks
+	swapped := append(append(append([]byte{}, ct[full:2*full]...), ct[:full]...), ct[2*full:]...)
+	_, err = decrypt(aead, nil, swapped)
+	require.ErrorIs(t, err, ErrAuth)
+
+	// extended payload
+	_, err = decrypt(aead, nil, append(append([]byte{}, ct...), 0))
+	require.ErrorIs(t, err, ErrAuth)
+
+	// modified byte
+	ct[full+5] ^= 1
+	_, err = decrypt(aead, nil, ct)
+	require.ErrorIs(t, err, ErrAuth)
+}
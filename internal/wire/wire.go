@@ -0,0 +1,394 @@
+// Package wire implements the versioned binary encoding used by the messages
+// of the secret sharing and distributed key generation protocols, by the
+// ciphertexts of the encryption schemes and by the zero-knowledge proofs.
+//
+// Every message starts with a two bytes header made of the version of the
+// encoding and of a tag identifying the type of the message. Integers are
+// encoded in big-endian, byte strings and lists are prefixed by their length
+// as a uint32, and points and scalars use their fixed size binary encoding.
+// Decoding checks every length against the remaining input before allocating
+// anything, and fails on trailing bytes.
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// Version is the current version of the encoding.
+const Version byte = 1
+
+// Tags identifying the type of the encoded messages.
+const (
+	TagDKGDealBundle byte = iota + 1
+	TagDKGResponseBundle
+	TagDKGJustificationBundle
+	TagRabinDeal
+	TagRabinResponse
+	TagRabinJustification
+	TagRabinSecretCommits
+	TagRabinComplaintCommits
+	TagRabinReconstructCommits
+	TagVSSEncryptedDeal
+	TagVSSResponse
+	TagVSSJustification
+	TagRabinVSSEncryptedDeal
+	TagRabinVSSResponse
+	TagRabinVSSJustification
+	TagVSSHidingDeal
+	TagVSSHidingJustification
+	TagIBECiphertext
+	TagIBECiphertextCPA
+	TagIBEHybrid
+	TagElGamalCiphertext
+	TagPRESecondLevel
+	TagPREFirstLevel
+	TagPREHybrid
+	TagBulletproofsRangeProof
+	TagProofNIZK
+	TagShareByteShare
+)
+
+const headerSize = 2
+
+var (
+	// ErrVersion is returned when decoding a message encoded with an
+	// unsupported version.
+	ErrVersion = errors.New("wire: unsupported encoding version")
+	// ErrTag is returned when decoding a message of another type.
+	ErrTag = errors.New("wire: unexpected message type")
+	// ErrShort is returned when the input is too short.
+	ErrShort = errors.New("wire: input too short")
+	// ErrTrailing is returned when the input has bytes left after decoding.
+	ErrTrailing = errors.New("wire: trailing bytes")
+	// ErrTooLong is returned by ReadBytes when the byte string is longer
+	// than allowed.
+	ErrTooLong = errors.New("wire: byte string too long")
+)
+
+// Encoder writes the fields of a message. The first error encountered is
+// kept and returned by Finish, so callers don't need to check every write.
+type Encoder struct {
+	buf []byte
+	err error
+}
+
+// NewEncoder returns an Encoder for a message of the given type.
+func NewEncoder(tag byte) *Encoder {
+	return &Encoder{buf: []byte{Version, tag}}
+}
+
+// Uint32 writes v in big-endian.
+func (e *Encoder) Uint32(v uint32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, v)
+}
+
+// Byte writes a single byte.
+func (e *Encoder) Byte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+// Bool writes b as a single byte.
+func (e *Encoder) Bool(b bool) {
+	if b {
+		e.Byte(1)
+	} else {
+		e.Byte(0)
+	}
+}
+
+// Bytes writes b prefixed by its length.
+func (e *Encoder) Bytes(b []byte) {
+	e.Uint32(uint32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+// Point writes the binary encoding of p.
+func (e *Encoder) Point(p kyber.Point) {
+	if p == nil {
+		e.fail(errors.New("wire: nil point"))
+		return
+	}
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		e.fail(err)
+		return
+	}
+	e.buf = append(e.buf, buf...)
+}
+
+// Points writes ps prefixed by its length.
+func (e *Encoder) Points(ps []kyber.Point) {
+	e.Uint32(uint32(len(ps)))
+	for _, p := range ps {
+		e.Point(p)
+	}
+}
+
+// Scalar writes the binary encoding of s.
+func (e *Encoder) Scalar(s kyber.Scalar) {
+	if s == nil {
+		e.fail(errors.New("wire: nil scalar"))
+		return
+	}
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		e.fail(err)
+		return
+	}
+	e.buf = append(e.buf, buf...)
+}
+
+// Scalars writes ss prefixed by its length.
+func (e *Encoder) Scalars(ss []kyber.Scalar) {
+	e.Uint32(uint32(len(ss)))
+	for _, s := range ss {
+		e.Scalar(s)
+	}
+}
+
+// Finish returns the encoded message, or the first error encountered.
+func (e *Encoder) Finish() ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.buf, nil
+}
+
+func (e *Encoder) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// Decoder reads the fields of a message. The first error encountered is kept
+// and returned by Finish; once an error happened, all reads return zero
+// values.
+type Decoder struct {
+	data []byte
+	err  error
+}
+
+// NewDecoder returns a Decoder for a message of the given type. It checks the
+// header of the message.
+func NewDecoder(data []byte, tag byte) *Decoder {
+	d := &Decoder{}
+	switch {
+	case len(data) < headerSize:
+		d.err = ErrShort
+	case data[0] != Version:
+		d.err = fmt.Errorf("%w: %d", ErrVersion, data[0])
+	case data[1] != tag:
+		d.err = fmt.Errorf("%w: %d instead of %d", ErrTag, data[1], tag)
+	default:
+		d.data = data[headerSize:]
+	}
+	return d
+}
+
+func (d *Decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.data) {
+		d.err = ErrShort
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+// Uint32 reads a big-endian uint32.
+func (d *Decoder) Uint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+// Byte reads a single byte.
+func (d *Decoder) Byte() byte {
+	b := d.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// Bool reads a boolean, which must be encoded as 0 or 1.
+func (d *Decoder) Bool() bool {
+	switch d.Byte() {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		d.Fail(errors.New("wire: invalid boolean"))
+		return false
+	}
+}
+
+// Bytes reads a length prefixed byte string. The returned slice is a copy, or
+// nil for an empty string.
+func (d *Decoder) Bytes() []byte {
+	n := d.Uint32()
+	if d.err != nil || uint64(n) > uint64(len(d.data)) {
+		d.Fail(ErrShort)
+		return nil
+	}
+	if n == 0 {
+		return nil
+	}
+	return append([]byte{}, d.next(int(n))...)
+}
+
+// Count reads the length of a list whose elements take at least minSize
+// bytes each, and checks it against the remaining input.
+func (d *Decoder) Count(minSize int) int {
+	n := d.Uint32()
+	if d.err != nil {
+		return 0
+	}
+	if minSize < 1 {
+		minSize = 1
+	}
+	if uint64(n)*uint64(minSize) > uint64(len(d.data)) {
+		d.Fail(ErrShort)
+		return 0
+	}
+	return int(n)
+}
+
+// Point reads a point of the given group.
+func (d *Decoder) Point(g kyber.Group) kyber.Point {
+	p := g.Point()
+	b := d.next(p.MarshalSize())
+	if b == nil {
+		return nil
+	}
+	if err := p.UnmarshalBinary(b); err != nil {
+		d.Fail(fmt.Errorf("wire: invalid point: %w", err))
+		return nil
+	}
+	return p
+}
+
+// Points reads a length prefixed list of points of the given group.
+func (d *Decoder) Points(g kyber.Group) []kyber.Point {
+	n := d.Count(g.PointLen())
+	if d.err != nil {
+		return nil
+	}
+	ps := make([]kyber.Point, n)
+	for i := range ps {
+		ps[i] = d.Point(g)
+	}
+	if d.err != nil {
+		return nil
+	}
+	return ps
+}
+
+// Scalar reads a scalar of the given group.
+func (d *Decoder) Scalar(g kyber.Group) kyber.Scalar {
+	s := g.Scalar()
+	b := d.next(s.MarshalSize())
+	if b == nil {
+		return nil
+	}
+	if err := s.UnmarshalBinary(b); err != nil {
+		d.Fail(fmt.Errorf("wire: invalid scalar: %w", err))
+		return nil
+	}
+	return s
+}
+
+// Scalars reads a length prefixed list of scalars of the given group.
+func (d *Decoder) Scalars(g kyber.Group) []kyber.Scalar {
+	n := d.Count(g.ScalarLen())
+	if d.err != nil {
+		return nil
+	}
+	ss := make([]kyber.Scalar, n)
+	for i := range ss {
+		ss[i] = d.Scalar(g)
+	}
+	if d.err != nil {
+		return nil
+	}
+	return ss
+}
+
+// Fail records a validation error. Only the first error is kept.
+func (d *Decoder) Fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// Err returns the first error encountered so far.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Finish returns the first error encountered, or ErrTrailing if some input
+// has not been read.
+func (d *Decoder) Finish() error {
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.data) != 0 {
+		return ErrTrailing
+	}
+	return nil
+}
+
+// WriteBytes writes to w the message of the given type holding only the byte
+// string b, and returns it. It frames a header followed by other data in a
+// stream, as read by ReadBytes.
+func WriteBytes(w io.Writer, tag byte, b []byte) ([]byte, error) {
+	e := NewEncoder(tag)
+	e.Bytes(b)
+	msg, err := e.Finish()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// ReadBytes reads from r a message written by WriteBytes, without reading
+// past its end, and returns the message and the byte string it holds. Byte
+// strings longer than maxLen are rejected with ErrTooLong before being read.
+func ReadBytes(r io.Reader, tag byte, maxLen int) (msg, b []byte, err error) {
+	prefix := make([]byte, headerSize+4)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, nil, fmt.Errorf("wire: reading message: %w", err)
+	}
+	if d := NewDecoder(prefix, tag); d.Err() != nil {
+		return nil, nil, d.Err()
+	}
+	n := binary.BigEndian.Uint32(prefix[headerSize:])
+	if uint64(n) > uint64(maxLen) {
+		return nil, nil, fmt.Errorf("%w: %d bytes", ErrTooLong, n)
+	}
+	msg = make([]byte, len(prefix)+int(n))
+	copy(msg, prefix)
+	if _, err := io.ReadFull(r, msg[len(prefix):]); err != nil {
+		return nil, nil, fmt.Errorf("wire: reading message: %w", err)
+	}
+	d := NewDecoder(msg, tag)
+	b = d.Bytes()
+	if err := d.Finish(); err != nil {
+		return nil, nil, err
+	}
+	return msg, b, nil
+}
@@ -0,0 +1,137 @@
+package wire
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func TestRoundTrip(t *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	points := []kyber.Point{g.Point().Pick(random.New()), g.Point().Base()}
+	scalars := []kyber.Scalar{g.Scalar().Pick(random.New()), g.Scalar().One()}
+	sc := g.Scalar().Pick(random.New())
+
+	e := NewEncoder(TagVSSResponse)
+	e.Uint32(7)
+	e.Bool(true)
+	e.Byte(3)
+	e.Bytes([]byte("hello"))
+	e.Bytes(nil)
+	e.Points(points)
+	e.Scalars(scalars)
+	e.Scalar(sc)
+	buf, err := e.Finish()
+	require.NoError(t, err)
+	require.Equal(t, []byte{Version, TagVSSResponse}, buf[:2])
+
+	d := NewDecoder(buf, TagVSSResponse)
+	require.Equal(t, uint32(7), d.Uint32())
+	require.True(t, d.Bool())
+	require.Equal(t, byte(3), d.Byte())
+	require.Equal(t, []byte("hello"), d.Bytes())
+	require.Nil(t, d.Bytes())
+	gotPoints := d.Points(g)
+	require.Len(t, gotPoints, len(points))
+	for i := range points {
+		require.True(t, points[i].Equal(gotPoints[i]))
+	}
+	gotScalars := d.Scalars(g)
+	require.Len(t, gotScalars, len(scalars))
+	for i := range scalars {
+		require.True(t, scalars[i].Equal(gotScalars[i]))
+	}
+	require.True(t, sc.Equal(d.Scalar(g)))
+	require.NoError(t, d.Finish())
+
+	// trailing bytes
+	d = NewDecoder(append(buf, 0), TagVSSResponse)
+	d.Uint32()
+	d.Bool()
+	d.Byte()
+	d.Bytes()
+	d.Bytes()
+	d.Points(g)
+	d.Scalars(g)
+	d.Scalar(g)
+	require.ErrorIs(t, d.Finish(), ErrTrailing)
+
+	// every truncation fails
+	for i := range buf {
+		d := NewDecoder(buf[:i], TagVSSResponse)
+		d.Uint32()
+		d.Bool()
+		d.Byte()
+		d.Bytes()
+		d.Bytes()
+		d.Points(g)
+		d.Scalars(g)
+		d.Scalar(g)
+		require.Error(t, d.Finish())
+	}
+}
+
+func TestHeader(t *testing.T) {
+	require.ErrorIs(t, NewDecoder(nil, TagVSSResponse).Finish(), ErrShort)
+	require.ErrorIs(t, NewDecoder([]byte{Version + 1, TagVSSResponse}, TagVSSResponse).Finish(), ErrVersion)
+	require.ErrorIs(t, NewDecoder([]byte{Version, TagVSSResponse}, TagVSSJustification).Finish(), ErrTag)
+	require.NoError(t, NewDecoder([]byte{Version, TagVSSResponse}, TagVSSResponse).Finish())
+}
+
+func TestLengthChecks(t *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	// huge lengths are rejected before allocating anything
+	d := NewDecoder([]byte{Version, 1, 0xff, 0xff, 0xff, 0xff}, 1)
+	require.Nil(t, d.Bytes())
+	require.ErrorIs(t, d.Finish(), ErrShort)
+
+	d = NewDecoder([]byte{Version, 1, 0x00, 0x10, 0x00, 0x00, 1, 2, 3}, 1)
+	require.Nil(t, d.Points(g))
+	require.ErrorIs(t, d.Finish(), ErrShort)
+
+	// invalid boolean
+	d = NewDecoder([]byte{Version, 1, 2}, 1)
+	d.Bool()
+	require.Error(t, d.Finish())
+
+	// nil values can't be encoded
+	e := NewEncoder(1)
+	e.Point(nil)
+	_, err := e.Finish()
+	require.Error(t, err)
+	e = NewEncoder(1)
+	e.Scalar(nil)
+	_, err = e.Finish()
+	require.Error(t, err)
+}
+
+func TestReadBytes(t *testing.T) {
+	var buf bytes.Buffer
+	msg, err := WriteBytes(&buf, TagIBEHybrid, []byte("header"))
+	require.NoError(t, err)
+	buf.WriteString("payload")
+
+	r := bytes.NewReader(buf.Bytes())
+	got, b, err := ReadBytes(r, TagIBEHybrid, 16)
+	require.NoError(t, err)
+	require.Equal(t, msg, got)
+	require.Equal(t, []byte("header"), b)
+	rest, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), rest)
+
+	_, _, err = ReadBytes(bytes.NewReader(buf.Bytes()), TagPREHybrid, 16)
+	require.ErrorIs(t, err, ErrTag)
+	_, _, err = ReadBytes(bytes.NewReader(buf.Bytes()), TagIBEHybrid, 5)
+	require.ErrorIs(t, err, ErrTooLong)
+	_, _, err = ReadBytes(bytes.NewReader(msg[:len(msg)-1]), TagIBEHybrid, 16)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	_, _, err = ReadBytes(bytes.NewReader(nil), TagIBEHybrid, 16)
+	require.ErrorIs(t, err, io.EOF)
+}
@@ -113,6 +113,12 @@ func (s *Suite) SetDomainG2(dst []byte) {
 	s.g2.dst = newDST
 }
 
+// PrimeOrder returns true: all the groups of the suite have a prime order,
+// and so does the suite when it is used as one of them.
+func (s *Suite) PrimeOrder() bool {
+	return true
+}
+
 // G1 returns the group G1 of the BN254 pairing.
 func (s *Suite) G1() kyber.Group {
 	return s.g1
@@ -79,6 +79,12 @@ func NewSuiteRand(rand cipher.Stream) *Suite {
 	return s
 }
 
+// PrimeOrder returns true: all the groups of the suite have a prime order,
+// and so does the suite when it is used as one of them.
+func (s *Suite) PrimeOrder() bool {
+	return true
+}
+
 // G1 returns the group G1 of the BN256 pairing.
 func (s *Suite) G1() kyber.Group {
 	return s.g1
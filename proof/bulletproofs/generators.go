@@ -0,0 +1,49 @@
+package bulletproofs
+
+import (
+	"encoding/binary"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// Generators are the independent points of the proofs: the bases B and
+// Blinding of the Pedersen commitments, and the vectors G and H of the inner
+// product arguments. They are derived from the XOF of the suite, so that
+// nobody knows the discrete logarithm of any of them with respect to the
+// others.
+type Generators struct {
+	B        kyber.Point   // base of the committed values
+	Blinding kyber.Point   // base of the blinding factors
+	G        []kyber.Point // vector bases of the left vectors
+	H        []kyber.Point // vector bases of the right vectors
+}
+
+// NewGenerators returns the generators of proofs of up to capacity bits, i.e.
+// the number of bits of the range times the number of aggregated values. B is
+// the base point of the group.
+func NewGenerators(suite Suite, capacity int) *Generators {
+	gens := &Generators{
+		B:        suite.Point().Base(),
+		Blinding: derive(suite, "Blinding", 0),
+		G:        make([]kyber.Point, capacity),
+		H:        make([]kyber.Point, capacity),
+	}
+	for i := range capacity {
+		gens.G[i] = derive(suite, "G", uint32(i))
+		gens.H[i] = derive(suite, "H", uint32(i))
+	}
+	return gens
+}
+
+func derive(suite Suite, label string, i uint32) kyber.Point {
+	seed := []byte("kyber-bulletproofs-generator-" + label)
+	seed = binary.BigEndian.AppendUint32(seed, i)
+	return suite.Point().Pick(suite.XOF(seed))
+}
+
+// Commit returns the Pedersen commitment vB + gamma*Blinding to the value v
+// with the blinding factor gamma.
+func (gens *Generators) Commit(v, gamma kyber.Scalar) kyber.Point {
+	V := gens.B.Clone().Mul(v, gens.B)
+	return V.Add(V, gens.Blinding.Clone().Mul(gamma, gens.Blinding))
+}
@@ -0,0 +1,206 @@
+package bulletproofs
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/msm"
+)
+
+const innerProductLabel = "kyber-bulletproofs-inner-product-v1"
+
+// InnerProductProof is a proof of knowledge of vectors a and b of length n
+// such that P = <a, G> + <b, H> + <a, b>Q, with 2*log2(n) points and two
+// scalars.
+type InnerProductProof struct {
+	L, R []kyber.Point
+	A, B kyber.Scalar
+}
+
+// NewInnerProductProof returns the proof of the vectors a and b for the bases
+// G and H and the point Q, whose length must be a power of two.
+func NewInnerProductProof(suite Suite, Q kyber.Point, G, H []kyber.Point,
+	a, b []kyber.Scalar) (*InnerProductProof, error) {
+	n := len(a)
+	if !isPowerOfTwo(n) || len(b) != n || len(G) != n || len(H) != n {
+		return nil, errors.New("bulletproofs: invalid inner product lengths")
+	}
+	P := suite.Point().Mul(innerProduct(suite, a, b), Q)
+	P.Add(P, sumMul(suite, a, G))
+	P.Add(P, sumMul(suite, b, H))
+	t := innerProductTranscript(suite, Q, P, n)
+	proof := proveInnerProduct(suite, t, Q, G, H, a, b)
+	if t.err != nil {
+		return nil, t.err
+	}
+	return proof, nil
+}
+
+// Verify checks the proof for the commitment P, in a group of prime order.
+func (p *InnerProductProof) Verify(suite Suite, Q, P kyber.Point, G, H []kyber.Point) error {
+	if !msm.PrimeOrder(suite) {
+		return ErrNotPrimeOrder
+	}
+	n := len(G)
+	if !isPowerOfTwo(n) || len(H) != n {
+		return errors.New("bulletproofs: invalid inner product lengths")
+	}
+	t := innerProductTranscript(suite, Q, P, n)
+	u, err := p.challenges(suite, t, n)
+	if err != nil {
+		return err
+	}
+	s := foldScalars(suite, u)
+
+	// P + sum(u_j^2 L_j + u_j^-2 R_j) - a<s, G> - b<1/s, H> - ab*Q == 0
+	scalars := []kyber.Scalar{suite.Scalar().One(), suite.Scalar().Neg(suite.Scalar().Mul(p.A, p.B))}
+	points := []kyber.Point{P, Q}
+	for j := range u {
+		u2 := suite.Scalar().Mul(u[j], u[j])
+		scalars = append(scalars, u2, suite.Scalar().Inv(u2))
+		points = append(points, p.L[j], p.R[j])
+	}
+	for i := range n {
+		scalars = append(scalars,
+			suite.Scalar().Neg(suite.Scalar().Mul(p.A, s[i])),
+			suite.Scalar().Neg(suite.Scalar().Div(p.B, s[i])))
+		points = append(points, G[i], H[i])
+	}
+	if !msm.MultiMul(suite, scalars, points).Equal(suite.Point().Null()) {
+		return fmt.Errorf("bulletproofs: %w", ErrInvalidProof)
+	}
+	return nil
+}
+
+func innerProductTranscript(suite Suite, Q, P kyber.Point, n int) *transcript {
+	t := newTranscript(suite, innerProductLabel)
+	t.appendUint32("n", uint32(n))
+	t.append("Q", Q)
+	t.append("P", P)
+	return t
+}
+
+// proveInnerProduct halves the vectors at every round, with the challenges
+// of the transcript.
+func proveInnerProduct(suite Suite, t *transcript, Q kyber.Point, G, H []kyber.Point,
+	a, b []kyber.Scalar) *InnerProductProof {
+	proof := &InnerProductProof{}
+	for n := len(a); n > 1; n /= 2 {
+		h := n / 2
+		aLo, aHi, bLo, bHi := a[:h], a[h:], b[:h], b[h:]
+		GLo, GHi, HLo, HHi := G[:h], G[h:], H[:h], H[h:]
+
+		L := suite.Point().Mul(innerProduct(suite, aLo, bHi), Q)
+		L.Add(L, sumMul(suite, aLo, GHi))
+		L.Add(L, sumMul(suite, bHi, HLo))
+		R := suite.Point().Mul(innerProduct(suite, aHi, bLo), Q)
+		R.Add(R, sumMul(suite, aHi, GLo))
+		R.Add(R, sumMul(suite, bLo, HHi))
+		proof.L = append(proof.L, L)
+		proof.R = append(proof.R, R)
+		t.append("L", L)
+		t.append("R", R)
+		u := t.challenge("u")
+		uInv := suite.Scalar().Inv(u)
+
+		a2, b2 := make([]kyber.Scalar, h), make([]kyber.Scalar, h)
+		G2, H2 := make([]kyber.Point, h), make([]kyber.Point, h)
+		for i := range h {
+			a2[i] = suite.Scalar().Mul(aLo[i], u)
+			a2[i].Add(a2[i], suite.Scalar().Mul(aHi[i], uInv))
+			b2[i] = suite.Scalar().Mul(bLo[i], uInv)
+			b2[i].Add(b2[i], suite.Scalar().Mul(bHi[i], u))
+			G2[i] = suite.Point().Mul(uInv, GLo[i])
+			G2[i].Add(G2[i], suite.Point().Mul(u, GHi[i]))
+			H2[i] = suite.Point().Mul(u, HLo[i])
+			H2[i].Add(H2[i], suite.Point().Mul(uInv, HHi[i]))
+		}
+		a, b, G, H = a2, b2, G2, H2
+	}
+	proof.A, proof.B = a[0], b[0]
+	return proof
+}
+
+// challenges checks the shape of the proof for vectors of length n and
+// returns the challenges of its rounds.
+func (p *InnerProductProof) challenges(suite Suite, t *transcript, n int) ([]kyber.Scalar, error) {
+	rounds := log2(n)
+	if len(p.L) != rounds || len(p.R) != rounds || p.A == nil || p.B == nil {
+		return nil, fmt.Errorf("bulletproofs: %w: malformed inner product proof", ErrInvalidProof)
+	}
+	u := make([]kyber.Scalar, rounds)
+	for j := range rounds {
+		if p.L[j] == nil || p.R[j] == nil {
+			return nil, fmt.Errorf("bulletproofs: %w: malformed inner product proof", ErrInvalidProof)
+		}
+		t.append("L", p.L[j])
+		t.append("R", p.R[j])
+		u[j] = t.challenge("u")
+		if u[j].Equal(suite.Scalar().Zero()) {
+			return nil, fmt.Errorf("bulletproofs: %w: zero challenge", ErrInvalidProof)
+		}
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	return u, nil
+}
+
+// foldScalars returns the scalars s such that the bases G folded by the
+// rounds of challenges u are <s, G>: s_i is the product of the u_j, or of
+// their inverse when the bit of i of round j is zero, round 0 being the most
+// significant bit. The bases H are folded into <1/s, H>.
+func foldScalars(suite Suite, u []kyber.Scalar) []kyber.Scalar {
+	rounds := len(u)
+	uInv := make([]kyber.Scalar, rounds)
+	for j := range u {
+		uInv[j] = suite.Scalar().Inv(u[j])
+	}
+	s := make([]kyber.Scalar, 1<<rounds)
+	for i := range s {
+		s[i] = suite.Scalar().One()
+		for j := range rounds {
+			if (i>>(rounds-1-j))&1 == 1 {
+				s[i].Mul(s[i], u[j])
+			} else {
+				s[i].Mul(s[i], uInv[j])
+			}
+		}
+	}
+	return s
+}
+
+// sumMul returns <scalars, points>, multiplying the points one by one as the
+// scalars may be secret.
+func sumMul(suite Suite, scalars []kyber.Scalar, points []kyber.Point) kyber.Point {
+	sum := suite.Point().Null()
+	tmp := suite.Point()
+	for i, s := range scalars {
+		sum.Add(sum, tmp.Mul(s, points[i]))
+	}
+	return sum
+}
+
+func innerProduct(suite Suite, a, b []kyber.Scalar) kyber.Scalar {
+	sum := suite.Scalar().Zero()
+	tmp := suite.Scalar()
+	for i := range a {
+		sum.Add(sum, tmp.Mul(a[i], b[i]))
+	}
+	return sum
+}
+
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// log2 returns the logarithm of a power of two.
+func log2(n int) int {
+	k := 0
+	for n > 1 {
+		n >>= 1
+		k++
+	}
+	return k
+}
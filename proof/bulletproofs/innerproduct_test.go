@@ -0,0 +1,48 @@
+package bulletproofs
+
+import (
+	"crypto/cipher"
+	"hash"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+)
+
+// g1Suite is the group G1, which has a prime order, with the hash and XOF of
+// its pairing suite.
+type g1Suite struct {
+	kyber.Group
+	s *circl.SuiteBLS12381
+}
+
+func (g *g1Suite) Hash() hash.Hash             { return g.s.Hash() }
+func (g *g1Suite) XOF(seed []byte) kyber.XOF   { return g.s.XOF(seed) }
+func (g *g1Suite) RandomStream() cipher.Stream { return g.s.RandomStream() }
+func (g *g1Suite) IsPrimeOrder() bool          { return true }
+
+func TestInnerProductProof(t *testing.T) {
+	for _, n := range []int{1, 2, 16} {
+		gens := NewGenerators(suite, n)
+		Q := derive(suite, "Q", 0)
+		a, b := make([]kyber.Scalar, n), make([]kyber.Scalar, n)
+		for i := range n {
+			a[i] = suite.Scalar().Pick(suite.RandomStream())
+			b[i] = suite.Scalar().Pick(suite.RandomStream())
+		}
+		proof, err := NewInnerProductProof(suite, Q, gens.G, gens.H, a, b)
+		require.NoError(t, err)
+		P := suite.Point().Mul(innerProduct(suite, a, b), Q)
+		P.Add(P, sumMul(suite, a, gens.G))
+		P.Add(P, sumMul(suite, b, gens.H))
+		require.NoError(t, proof.Verify(suite, Q, P, gens.G, gens.H))
+
+		P.Add(P, Q)
+		require.ErrorIs(t, proof.Verify(suite, Q, P, gens.G, gens.H), ErrInvalidProof)
+	}
+
+	gens := NewGenerators(suite, 3)
+	_, err := NewInnerProductProof(suite, gens.B, gens.G, gens.H, randomScalars(3), randomScalars(3))
+	require.Error(t, err)
+}
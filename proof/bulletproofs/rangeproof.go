@@ -0,0 +1,472 @@
+// Package bulletproofs implements the range proofs of Bünz, Bootle, Boneh,
+// Poelstra, Wuille and Maxwell, "Bulletproofs: Short Proofs for Confidential
+// Transactions and More", on any prime order kyber.Group.
+//
+// A range proof shows that the value v of a Pedersen commitment
+// V = vB + gamma*Blinding lies in [0, 2^n), for n a power of two up to 64,
+// without revealing anything else about v. Its size is logarithmic in n, and
+// the proofs of m values, m being a power of two, can be aggregated into a
+// single proof whose size is logarithmic in n*m. The proofs rely on an inner
+// product argument, which is also available on its own, and are made
+// non-interactive with the Fiat-Shamir heuristic.
+//
+// Verification uses multi-scalar multiplications, and many proofs can be
+// verified at once with VerifyBatch, for a cost much lower than verifying
+// them one by one.
+//
+// The verification equations are only sound in a group of prime order, where
+// no point has a component of small order that cancels out for some values of
+// the challenges and of the random weights. The verifiers thus only accept a
+// group that reports a prime order with an IsPrimeOrder method, such as the
+// prime-order subgroup of edwards25519vartime or the group G1 of
+// bls12381/kilic, and reject edwards25519, whose order has a cofactor of 8.
+package bulletproofs
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/msm"
+	"go.dedis.ch/kyber/v4/internal/wire"
+)
+
+// Suite describes the functionalities needed by this package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// ErrInvalidProof is returned when a proof doesn't verify.
+var ErrInvalidProof = errors.New("invalid proof")
+
+// ErrNotPrimeOrder is returned by the verifiers when the group doesn't report
+// a prime order.
+var ErrNotPrimeOrder = errors.New("bulletproofs: the group doesn't have a prime order")
+
+const rangeLabel = "kyber-bulletproofs-range-v1"
+
+// RangeProof is a proof that the values of one or more Pedersen commitments
+// lie in a range [0, 2^n).
+type RangeProof struct {
+	A, S   kyber.Point // Commitments to the bits of the values and to the blinding vectors
+	T1, T2 kyber.Point // Commitments to the coefficients of t(X)
+	TauX   kyber.Scalar
+	Mu     kyber.Scalar
+	THat   kyber.Scalar
+	IPP    *InnerProductProof
+}
+
+// ProveRange returns the commitment to v with the blinding factor gamma and
+// the proof that v lies in [0, 2^n).
+func ProveRange(suite Suite, gens *Generators, v uint64, gamma kyber.Scalar, n int) (*RangeProof, kyber.Point, error) {
+	proof, V, err := ProveRanges(suite, gens, []uint64{v}, []kyber.Scalar{gamma}, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, V[0], nil
+}
+
+// ProveRanges returns the commitments to the values with the blinding
+// factors gammas and the aggregated proof that they all lie in [0, 2^n). The
+// number of values must be a power of two.
+func ProveRanges(suite Suite, gens *Generators, values []uint64, gammas []kyber.Scalar,
+	n int) (*RangeProof, []kyber.Point, error) {
+	m := len(values)
+	if err := checkParams(gens, n, m); err != nil {
+		return nil, nil, err
+	}
+	if len(gammas) != m {
+		return nil, nil, errors.New("bulletproofs: values and blinding factors of different lengths")
+	}
+	V := make([]kyber.Point, m)
+	for j, v := range values {
+		if n < 64 && v>>n != 0 {
+			return nil, nil, fmt.Errorf("bulletproofs: value %d out of range", j)
+		}
+		V[j] = gens.Commit(scalarUint64(suite, v), gammas[j])
+	}
+
+	N := n * m
+	t := rangeTranscript(suite, n, V)
+	rand := suite.RandomStream()
+	zero, one := suite.Scalar().Zero(), suite.Scalar().One()
+
+	// A commits to the bits aL of the values and to aR = aL - 1
+	aL, aR := make([]kyber.Scalar, N), make([]kyber.Scalar, N)
+	for j, v := range values {
+		for i := range n {
+			if (v>>i)&1 == 1 {
+				aL[j*n+i], aR[j*n+i] = one, zero
+			} else {
+				aL[j*n+i], aR[j*n+i] = zero, suite.Scalar().Neg(one)
+			}
+		}
+	}
+	G, H := gens.G[:N], gens.H[:N]
+	alpha := suite.Scalar().Pick(rand)
+	A := suite.Point().Mul(alpha, gens.Blinding)
+	A.Add(A, sumMul(suite, aL, G))
+	A.Add(A, sumMul(suite, aR, H))
+
+	sL, sR := make([]kyber.Scalar, N), make([]kyber.Scalar, N)
+	for i := range N {
+		sL[i] = suite.Scalar().Pick(rand)
+		sR[i] = suite.Scalar().Pick(rand)
+	}
+	rho := suite.Scalar().Pick(rand)
+	S := suite.Point().Mul(rho, gens.Blinding)
+	S.Add(S, sumMul(suite, sL, G))
+	S.Add(S, sumMul(suite, sR, H))
+
+	t.append("A", A)
+	t.append("S", S)
+	y := t.challenge("y")
+	z := t.challenge("z")
+
+	// l(X) = aL - z + sL*X
+	// r(X) = y^i*(aR + z + sR*X) + z^(2+j)*2^(i mod n)
+	yPow := powers(suite, y, N)
+	zPow := offsetPowers(suite, z, m)
+	twoPow := powers(suite, suite.Scalar().SetInt64(2), n)
+	l0, r0, r1 := make([]kyber.Scalar, N), make([]kyber.Scalar, N), make([]kyber.Scalar, N)
+	for i := range N {
+		l0[i] = suite.Scalar().Sub(aL[i], z)
+		r0[i] = suite.Scalar().Add(aR[i], z)
+		r0[i].Mul(r0[i], yPow[i])
+		r0[i].Add(r0[i], suite.Scalar().Mul(zPow[i/n], twoPow[i%n]))
+		r1[i] = suite.Scalar().Mul(yPow[i], sR[i])
+	}
+	t1 := suite.Scalar().Add(innerProduct(suite, l0, r1), innerProduct(suite, sL, r0))
+	t2 := innerProduct(suite, sL, r1)
+	tau1 := suite.Scalar().Pick(rand)
+	tau2 := suite.Scalar().Pick(rand)
+	T1 := gens.Commit(t1, tau1)
+	T2 := gens.Commit(t2, tau2)
+
+	t.append("T1", T1)
+	t.append("T2", T2)
+	x := t.challenge("x")
+
+	l, r := make([]kyber.Scalar, N), make([]kyber.Scalar, N)
+	for i := range N {
+		l[i] = suite.Scalar().Add(l0[i], suite.Scalar().Mul(sL[i], x))
+		r[i] = suite.Scalar().Add(r0[i], suite.Scalar().Mul(r1[i], x))
+	}
+	tHat := innerProduct(suite, l, r)
+	tauX := suite.Scalar().Mul(tau2, suite.Scalar().Mul(x, x))
+	tauX.Add(tauX, suite.Scalar().Mul(tau1, x))
+	for j := range m {
+		tauX.Add(tauX, suite.Scalar().Mul(zPow[j], gammas[j]))
+	}
+	mu := suite.Scalar().Add(alpha, suite.Scalar().Mul(rho, x))
+
+	t.append("taux", tauX)
+	t.append("mu", mu)
+	t.append("that", tHat)
+	w := t.challenge("w")
+
+	// the inner product argument on the bases G and y^-i*H_i, with Q = wB
+	yInv := powers(suite, suite.Scalar().Inv(y), N)
+	H2 := make([]kyber.Point, N)
+	for i := range N {
+		H2[i] = suite.Point().Mul(yInv[i], H[i])
+	}
+	Q := suite.Point().Mul(w, gens.B)
+	ipp := proveInnerProduct(suite, t, Q, G, H2, l, r)
+	if t.err != nil {
+		return nil, nil, t.err
+	}
+	return &RangeProof{
+		A: A, S: S, T1: T1, T2: T2,
+		TauX: tauX, Mu: mu, THat: tHat,
+		IPP: ipp,
+	}, V, nil
+}
+
+// Verify checks that the proof shows that the values of the commitments V lie
+// in [0, 2^n).
+func (p *RangeProof) Verify(suite Suite, gens *Generators, V []kyber.Point, n int) error {
+	return VerifyBatch(suite, gens, []*RangeProof{p}, [][]kyber.Point{V}, n)
+}
+
+// VerifyBatch checks the proofs, each of them for the commitments of the same
+// index, at once with a single multi-scalar multiplication. It only tells
+// whether all the proofs are valid.
+func VerifyBatch(suite Suite, gens *Generators, proofs []*RangeProof, commitments [][]kyber.Point, n int) error {
+	if !msm.PrimeOrder(suite) {
+		return ErrNotPrimeOrder
+	}
+	if len(proofs) != len(commitments) {
+		return errors.New("bulletproofs: proofs and commitments of different lengths")
+	}
+	maxN := 0
+	for _, V := range commitments {
+		if err := checkParams(gens, n, len(V)); err != nil {
+			return err
+		}
+		maxN = max(maxN, n*len(V))
+	}
+
+	// the coefficients of the shared generators, followed by the points of
+	// the proofs
+	gCoeff, hCoeff := zeros(suite, maxN), zeros(suite, maxN)
+	bCoeff, blindingCoeff := suite.Scalar().Zero(), suite.Scalar().Zero()
+	var scalars []kyber.Scalar
+	var points []kyber.Point
+	rand := suite.RandomStream()
+	for k, p := range proofs {
+		V := commitments[k]
+		if err := p.check(V); err != nil {
+			return err
+		}
+		weight := suite.Scalar().Pick(rand)
+		c := suite.Scalar().Pick(rand)
+		s, err := p.terms(suite, V, n, weight, c)
+		if err != nil {
+			return err
+		}
+		bCoeff.Add(bCoeff, s.b)
+		blindingCoeff.Add(blindingCoeff, s.blinding)
+		for i := range s.g {
+			gCoeff[i].Add(gCoeff[i], s.g[i])
+			hCoeff[i].Add(hCoeff[i], s.h[i])
+		}
+		scalars = append(scalars, s.scalars...)
+		points = append(points, s.points...)
+	}
+	scalars = append(scalars, bCoeff, blindingCoeff)
+	points = append(points, gens.B, gens.Blinding)
+	scalars = append(scalars, gCoeff...)
+	points = append(points, gens.G[:maxN]...)
+	scalars = append(scalars, hCoeff...)
+	points = append(points, gens.H[:maxN]...)
+	if !msm.MultiMul(suite, scalars, points).Equal(suite.Point().Null()) {
+		return fmt.Errorf("bulletproofs: %w", ErrInvalidProof)
+	}
+	return nil
+}
+
+// verificationTerms are the terms of the verification equation of a proof.
+type verificationTerms struct {
+	b, blinding kyber.Scalar
+	g, h        []kyber.Scalar
+	scalars     []kyber.Scalar
+	points      []kyber.Point
+}
+
+// terms returns the terms of the sum of the equations of the proof
+//
+//	THat*B + TauX*Blinding == sum(z^(2+j)*V_j) + delta(y, z)*B + x*T1 + x^2*T2
+//	A + x*S - Mu*Blinding + <-z, G> + <z + z^(2+j)*2^(i mod n)/y^i, H> + THat*Q
+//	  + sum(u_k^2*L_k + u_k^-2*R_k) == a<s, G> + b<1/(s*y^i), H> + ab*Q
+//
+// where Q = wB, the first one multiplied by c and both by weight, so that
+// the sum is null when the proof is valid.
+func (p *RangeProof) terms(suite Suite, V []kyber.Point, n int, weight, c kyber.Scalar) (*verificationTerms, error) {
+	m := len(V)
+	N := n * m
+	t := rangeTranscript(suite, n, V)
+	t.append("A", p.A)
+	t.append("S", p.S)
+	y := t.challenge("y")
+	z := t.challenge("z")
+	t.append("T1", p.T1)
+	t.append("T2", p.T2)
+	x := t.challenge("x")
+	t.append("taux", p.TauX)
+	t.append("mu", p.Mu)
+	t.append("that", p.THat)
+	w := t.challenge("w")
+	u, err := p.IPP.challenges(suite, t, N)
+	if err != nil {
+		return nil, err
+	}
+	if y.Equal(suite.Scalar().Zero()) {
+		return nil, fmt.Errorf("bulletproofs: %w: zero challenge", ErrInvalidProof)
+	}
+
+	yPow := powers(suite, y, N)
+	yInv := powers(suite, suite.Scalar().Inv(y), N)
+	zPow := offsetPowers(suite, z, m)
+	twoPow := powers(suite, suite.Scalar().SetInt64(2), n)
+	s := foldScalars(suite, u)
+	a, b := p.IPP.A, p.IPP.B
+	x2 := suite.Scalar().Mul(x, x)
+	wc := suite.Scalar().Mul(weight, c)
+
+	// delta(y, z) = (z - z^2)*<1, y^i> - sum(z^(3+j))*<1, 2^i>
+	delta := suite.Scalar().Sub(z, suite.Scalar().Mul(z, z))
+	delta.Mul(delta, sum(suite, yPow))
+	sumZ := suite.Scalar().Mul(sum(suite, zPow), z)
+	delta.Sub(delta, sumZ.Mul(sumZ, sum(suite, twoPow)))
+
+	terms := &verificationTerms{g: make([]kyber.Scalar, N), h: make([]kyber.Scalar, N)}
+	// B: c*(THat - delta) + w*(THat - ab)
+	terms.b = suite.Scalar().Mul(c, suite.Scalar().Sub(p.THat, delta))
+	terms.b.Add(terms.b, suite.Scalar().Mul(w, suite.Scalar().Sub(p.THat, suite.Scalar().Mul(a, b))))
+	terms.b.Mul(terms.b, weight)
+	// Blinding: c*TauX - Mu
+	terms.blinding = suite.Scalar().Mul(c, p.TauX)
+	terms.blinding.Sub(terms.blinding, p.Mu)
+	terms.blinding.Mul(terms.blinding, weight)
+	for i := range N {
+		// G_i: -z - a*s_i
+		terms.g[i] = suite.Scalar().Neg(suite.Scalar().Add(z, suite.Scalar().Mul(a, s[i])))
+		terms.g[i].Mul(terms.g[i], weight)
+		// H_i: z + (z^(2+j)*2^(i mod n) - b/s_i)/y^i
+		h := suite.Scalar().Mul(zPow[i/n], twoPow[i%n])
+		h.Sub(h, suite.Scalar().Div(b, s[i]))
+		h.Mul(h, yInv[i])
+		terms.h[i] = h.Add(h, z)
+		terms.h[i].Mul(terms.h[i], weight)
+	}
+
+	terms.scalars = append(terms.scalars, weight, suite.Scalar().Mul(weight, x))
+	terms.points = append(terms.points, p.A, p.S)
+	terms.scalars = append(terms.scalars, suite.Scalar().Neg(suite.Scalar().Mul(wc, x)),
+		suite.Scalar().Neg(suite.Scalar().Mul(wc, x2)))
+	terms.points = append(terms.points, p.T1, p.T2)
+	for j := range m {
+		terms.scalars = append(terms.scalars, suite.Scalar().Neg(suite.Scalar().Mul(wc, zPow[j])))
+		terms.points = append(terms.points, V[j])
+	}
+	for k := range u {
+		u2 := suite.Scalar().Mul(u[k], u[k])
+		terms.scalars = append(terms.scalars, suite.Scalar().Mul(weight, u2),
+			suite.Scalar().Mul(weight, suite.Scalar().Inv(u2)))
+		terms.points = append(terms.points, p.IPP.L[k], p.IPP.R[k])
+	}
+	return terms, nil
+}
+
+// check checks that the proof is complete.
+func (p *RangeProof) check(V []kyber.Point) error {
+	if p == nil || p.A == nil || p.S == nil || p.T1 == nil || p.T2 == nil ||
+		p.TauX == nil || p.Mu == nil || p.THat == nil || p.IPP == nil {
+		return fmt.Errorf("bulletproofs: %w: incomplete proof", ErrInvalidProof)
+	}
+	for _, v := range V {
+		if v == nil {
+			return errors.New("bulletproofs: missing commitment")
+		}
+	}
+	return nil
+}
+
+func rangeTranscript(suite Suite, n int, V []kyber.Point) *transcript {
+	t := newTranscript(suite, rangeLabel)
+	t.appendUint32("n", uint32(n))
+	t.appendUint32("m", uint32(len(V)))
+	t.appendPoints("V", V)
+	return t
+}
+
+// checkParams checks the number of bits n and of values m.
+func checkParams(gens *Generators, n, m int) error {
+	if !isPowerOfTwo(n) || n > 64 {
+		return errors.New("bulletproofs: the number of bits must be a power of two up to 64")
+	}
+	if !isPowerOfTwo(m) {
+		return errors.New("bulletproofs: the number of values must be a power of two")
+	}
+	if n*m > len(gens.G) || n*m > len(gens.H) {
+		return errors.New("bulletproofs: not enough generators")
+	}
+	return nil
+}
+
+// powers returns x^i for i < n.
+func powers(suite Suite, x kyber.Scalar, n int) []kyber.Scalar {
+	p := make([]kyber.Scalar, n)
+	acc := suite.Scalar().One()
+	for i := range p {
+		p[i] = acc.Clone()
+		acc.Mul(acc, x)
+	}
+	return p
+}
+
+// offsetPowers returns z^(2+j) for j < m.
+func offsetPowers(suite Suite, z kyber.Scalar, m int) []kyber.Scalar {
+	p := powers(suite, z, m)
+	z2 := suite.Scalar().Mul(z, z)
+	for j := range p {
+		p[j].Mul(p[j], z2)
+	}
+	return p
+}
+
+func sum(suite Suite, xs []kyber.Scalar) kyber.Scalar {
+	s := suite.Scalar().Zero()
+	for _, x := range xs {
+		s.Add(s, x)
+	}
+	return s
+}
+
+func zeros(suite Suite, n int) []kyber.Scalar {
+	z := make([]kyber.Scalar, n)
+	for i := range z {
+		z[i] = suite.Scalar().Zero()
+	}
+	return z
+}
+
+// scalarUint64 returns v as a scalar, which SetInt64 can't do for values
+// above 2^63.
+func scalarUint64(suite Suite, v uint64) kyber.Scalar {
+	s := suite.Scalar().SetInt64(int64(v >> 1))
+	s.Add(s, s)
+	return s.Add(s, suite.Scalar().SetInt64(int64(v&1)))
+}
+
+// Marshal returns the versioned binary encoding of the proof.
+func (p *RangeProof) Marshal() ([]byte, error) {
+	if p.IPP == nil {
+		return nil, errors.New("bulletproofs: missing inner product proof")
+	}
+	e := wire.NewEncoder(wire.TagBulletproofsRangeProof)
+	e.Point(p.A)
+	e.Point(p.S)
+	e.Point(p.T1)
+	e.Point(p.T2)
+	e.Scalar(p.TauX)
+	e.Scalar(p.Mu)
+	e.Scalar(p.THat)
+	e.Points(p.IPP.L)
+	e.Points(p.IPP.R)
+	e.Scalar(p.IPP.A)
+	e.Scalar(p.IPP.B)
+	return e.Finish()
+}
+
+// Unmarshal decodes a proof encoded by Marshal with points and scalars of the
+// group g.
+func (p *RangeProof) Unmarshal(data []byte, g kyber.Group) error {
+	d := wire.NewDecoder(data, wire.TagBulletproofsRangeProof)
+	proof := RangeProof{
+		A:    d.Point(g),
+		S:    d.Point(g),
+		T1:   d.Point(g),
+		T2:   d.Point(g),
+		TauX: d.Scalar(g),
+		Mu:   d.Scalar(g),
+		THat: d.Scalar(g),
+		IPP:  &InnerProductProof{},
+	}
+	proof.IPP.L = d.Points(g)
+	proof.IPP.R = d.Points(g)
+	proof.IPP.A = d.Scalar(g)
+	proof.IPP.B = d.Scalar(g)
+	if len(proof.IPP.L) != len(proof.IPP.R) {
+		d.Fail(errors.New("bulletproofs: L and R of different lengths"))
+	}
+	if err := d.Finish(); err != nil {
+		return err
+	}
+	*p = proof
+	return nil
+}
@@ -0,0 +1,215 @@
+package bulletproofs
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/pairing/bls12381/circl"
+)
+
+var suite Suite = &g1Suite{circl.NewSuiteBLS12381().G1(), circl.NewSuiteBLS12381()}
+
+func randomScalars(n int) []kyber.Scalar {
+	s := make([]kyber.Scalar, n)
+	for i := range s {
+		s[i] = suite.Scalar().Pick(suite.RandomStream())
+	}
+	return s
+}
+
+func TestRangeProof(t *testing.T) {
+	gens := NewGenerators(suite, 64)
+	for _, n := range []int{8, 32, 64} {
+		for _, v := range []uint64{0, 1, 1<<(n-1) + 3, 1<<n - 1, math.MaxUint64 >> (64 - n)} {
+			gamma := suite.Scalar().Pick(suite.RandomStream())
+			proof, V, err := ProveRange(suite, gens, v, gamma, n)
+			require.NoError(t, err)
+			require.True(t, V.Equal(gens.Commit(scalarUint64(suite, v), gamma)))
+			require.NoError(t, proof.Verify(suite, gens, []kyber.Point{V}, n))
+			require.Len(t, proof.IPP.L, log2(n))
+
+			// the proof is bound to the commitment and to the range
+			V2 := suite.Point().Add(V, gens.B)
+			require.ErrorIs(t, proof.Verify(suite, gens, []kyber.Point{V2}, n), ErrInvalidProof)
+			if n > 8 {
+				require.Error(t, proof.Verify(suite, gens, []kyber.Point{V}, n/2))
+			}
+		}
+	}
+
+	_, _, err := ProveRange(suite, gens, 256, suite.Scalar().One(), 8)
+	require.Error(t, err)
+	_, _, err = ProveRange(suite, gens, 1, suite.Scalar().One(), 12)
+	require.Error(t, err)
+	_, _, err = ProveRange(suite, NewGenerators(suite, 8), 1, suite.Scalar().One(), 16)
+	require.Error(t, err)
+}
+
+func TestRangeProofOutOfRange(t *testing.T) {
+	// a commitment to a value out of the range, proven with the bits of
+	// another value, doesn't verify
+	gens := NewGenerators(suite, 8)
+	gamma := suite.Scalar().Pick(suite.RandomStream())
+	proof, _, err := ProveRange(suite, gens, 255, gamma, 8)
+	require.NoError(t, err)
+	V := gens.Commit(suite.Scalar().SetInt64(256), gamma)
+	require.ErrorIs(t, proof.Verify(suite, gens, []kyber.Point{V}, 8), ErrInvalidProof)
+	V = gens.Commit(suite.Scalar().SetInt64(-1), gamma)
+	require.ErrorIs(t, proof.Verify(suite, gens, []kyber.Point{V}, 8), ErrInvalidProof)
+}
+
+func TestRangeProofAggregated(t *testing.T) {
+	gens := NewGenerators(suite, 128)
+	for _, m := range []int{1, 2, 4} {
+		values := make([]uint64, m)
+		for j := range values {
+			values[j] = uint64(j*1000 + 7)
+		}
+		gammas := randomScalars(m)
+		proof, V, err := ProveRanges(suite, gens, values, gammas, 32)
+		require.NoError(t, err)
+		require.Len(t, V, m)
+		require.NoError(t, proof.Verify(suite, gens, V, 32))
+		require.Len(t, proof.IPP.L, log2(32*m))
+		if m > 1 {
+			V[0], V[1] = V[1], V[0]
+			require.ErrorIs(t, proof.Verify(suite, gens, V, 32), ErrInvalidProof)
+		}
+	}
+
+	_, _, err := ProveRanges(suite, gens, []uint64{1, 2, 3}, randomScalars(3), 8)
+	require.Error(t, err)
+	_, _, err = ProveRanges(suite, gens, []uint64{1, 2}, randomScalars(1), 8)
+	require.Error(t, err)
+	_, _, err = ProveRanges(suite, gens, []uint64{1, 2}, randomScalars(2), 128)
+	require.Error(t, err)
+}
+
+func TestRangeProofTampered(t *testing.T) {
+	gens := NewGenerators(suite, 16)
+	proof, V, err := ProveRange(suite, gens, 42, suite.Scalar().One(), 16)
+	require.NoError(t, err)
+	Vs := []kyber.Point{V}
+	one := suite.Scalar().One()
+	tamper := []func(p *RangeProof){
+		func(p *RangeProof) { p.A = suite.Point().Add(p.A, gens.B) },
+		func(p *RangeProof) { p.S = suite.Point().Add(p.S, gens.B) },
+		func(p *RangeProof) { p.T1 = suite.Point().Add(p.T1, gens.B) },
+		func(p *RangeProof) { p.T2 = suite.Point().Add(p.T2, gens.B) },
+		func(p *RangeProof) { p.TauX = suite.Scalar().Add(p.TauX, one) },
+		func(p *RangeProof) { p.Mu = suite.Scalar().Add(p.Mu, one) },
+		func(p *RangeProof) { p.THat = suite.Scalar().Add(p.THat, one) },
+		func(p *RangeProof) { p.IPP.L[0] = suite.Point().Add(p.IPP.L[0], gens.B) },
+		func(p *RangeProof) { p.IPP.R[3] = suite.Point().Add(p.IPP.R[3], gens.B) },
+		func(p *RangeProof) { p.IPP.A = suite.Scalar().Add(p.IPP.A, one) },
+		func(p *RangeProof) { p.IPP.B = suite.Scalar().Add(p.IPP.B, one) },
+		func(p *RangeProof) { p.IPP.L = p.IPP.L[1:] },
+		func(p *RangeProof) { p.Mu = nil },
+	}
+	for i, f := range tamper {
+		p := *proof
+		p.IPP = &InnerProductProof{
+			L: append([]kyber.Point{}, proof.IPP.L...),
+			R: append([]kyber.Point{}, proof.IPP.R...),
+			A: proof.IPP.A,
+			B: proof.IPP.B,
+		}
+		f(&p)
+		require.ErrorIs(t, p.Verify(suite, gens, Vs, 16), ErrInvalidProof, "tampering %d", i)
+	}
+	require.NoError(t, proof.Verify(suite, gens, Vs, 16))
+}
+
+func TestVerifyBatch(t *testing.T) {
+	gens := NewGenerators(suite, 64)
+	var proofs []*RangeProof
+	var commitments [][]kyber.Point
+	for k := range 5 {
+		m := 1 << (k % 2)
+		values := make([]uint64, m)
+		for j := range values {
+			values[j] = uint64(k*100 + j)
+		}
+		proof, V, err := ProveRanges(suite, gens, values, randomScalars(m), 32)
+		require.NoError(t, err)
+		proofs = append(proofs, proof)
+		commitments = append(commitments, V)
+	}
+	require.NoError(t, VerifyBatch(suite, gens, proofs, commitments, 32))
+	require.NoError(t, VerifyBatch(suite, gens, nil, nil, 32))
+
+	commitments[3][0] = suite.Point().Add(commitments[3][0], gens.B)
+	require.ErrorIs(t, VerifyBatch(suite, gens, proofs, commitments, 32), ErrInvalidProof)
+	require.Error(t, VerifyBatch(suite, gens, proofs, commitments[1:], 32))
+}
+
+func TestRangeProofMarshal(t *testing.T) {
+	gens := NewGenerators(suite, 64)
+	proof, V, err := ProveRange(suite, gens, 1234, suite.Scalar().One(), 64)
+	require.NoError(t, err)
+	data, err := proof.Marshal()
+	require.NoError(t, err)
+	// 4 points, 3 scalars, 2*6 points and 2 scalars
+	pl, sl := suite.PointLen(), suite.ScalarLen()
+	require.Len(t, data, 2+4*pl+3*sl+2*(4+6*pl)+2*sl)
+
+	var decoded RangeProof
+	require.NoError(t, decoded.Unmarshal(data, suite))
+	require.NoError(t, decoded.Verify(suite, gens, []kyber.Point{V}, 64))
+	require.Error(t, decoded.Unmarshal(data[:len(data)-1], suite))
+	require.Error(t, decoded.Unmarshal(append(data, 0), suite))
+
+	proof.IPP = nil
+	_, err = proof.Marshal()
+	require.Error(t, err)
+}
+
+func TestRangeProofNotPrimeOrder(t *testing.T) {
+	ed := edwards25519.NewBlakeSHA256Ed25519()
+	gens := NewGenerators(ed, 8)
+	proof, V, err := ProveRange(ed, gens, 3, ed.Scalar().One(), 8)
+	require.NoError(t, err)
+
+	// (0, -1), the point of order 2, added to A is only detected when its
+	// weight is odd, so edwards25519 is rejected as a whole
+	enc := bytes.Repeat([]byte{0xff}, 32)
+	enc[0], enc[31] = 0xec, 0x7f
+	T := ed.Point()
+	require.NoError(t, T.UnmarshalBinary(enc))
+	proof.A = ed.Point().Add(proof.A, T)
+	require.ErrorIs(t, proof.Verify(ed, gens, []kyber.Point{V}, 8), ErrNotPrimeOrder)
+	require.ErrorIs(t, proof.IPP.Verify(ed, gens.B, V, gens.G, gens.H), ErrNotPrimeOrder)
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	gens := NewGenerators(suite, 8)
+	proof, _, err := ProveRange(suite, gens, 3, suite.Scalar().One(), 8)
+	require.NoError(f, err)
+	data, err := proof.Marshal()
+	require.NoError(f, err)
+	f.Add(data)
+	f.Fuzz(func(_ *testing.T, data []byte) {
+		var p RangeProof
+		_ = p.Unmarshal(data, suite)
+	})
+}
+
+func BenchmarkProveRange(b *testing.B) {
+	gens := NewGenerators(suite, 64)
+	gamma := suite.Scalar().One()
+	for b.Loop() {
+		_, _, _ = ProveRange(suite, gens, 1<<40, gamma, 64)
+	}
+}
+
+func BenchmarkVerifyRange(b *testing.B) {
+	gens := NewGenerators(suite, 64)
+	proof, V, _ := ProveRange(suite, gens, 1<<40, suite.Scalar().One(), 64)
+	for b.Loop() {
+		_ = proof.Verify(suite, gens, []kyber.Point{V}, 64)
+	}
+}
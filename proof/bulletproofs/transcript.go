@@ -0,0 +1,59 @@
+package bulletproofs
+
+import (
+	"encoding"
+	"encoding/binary"
+	"hash"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// transcript is the Fiat-Shamir transcript of a proof: the messages of the
+// prover are hashed along with their labels, and every challenge is derived
+// from the hash of everything appended before it.
+type transcript struct {
+	suite Suite
+	h     hash.Hash
+	err   error
+}
+
+func newTranscript(suite Suite, label string) *transcript {
+	t := &transcript{suite: suite, h: suite.Hash()}
+	t.appendBytes("dom-sep", []byte(label))
+	return t
+}
+
+func (t *transcript) appendBytes(label string, data []byte) {
+	var buf []byte
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(label)))
+	buf = append(buf, label...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+	buf = append(buf, data...)
+	_, _ = t.h.Write(buf)
+}
+
+func (t *transcript) appendUint32(label string, v uint32) {
+	t.appendBytes(label, binary.BigEndian.AppendUint32(nil, v))
+}
+
+func (t *transcript) append(label string, objs ...encoding.BinaryMarshaler) {
+	for _, o := range objs {
+		b, err := o.MarshalBinary()
+		if err != nil && t.err == nil {
+			t.err = err
+		}
+		t.appendBytes(label, b)
+	}
+}
+
+func (t *transcript) appendPoints(label string, ps []kyber.Point) {
+	for _, p := range ps {
+		t.append(label, p)
+	}
+}
+
+// challenge returns the challenge scalar of the transcript so far.
+func (t *transcript) challenge(label string) kyber.Scalar {
+	t.appendBytes(label, nil)
+	return t.suite.Scalar().Pick(t.suite.XOF(t.h.Sum(nil)))
+}
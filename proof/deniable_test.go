@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/group/edwards25519"
 	"go.dedis.ch/kyber/v4/util/random"
@@ -23,7 +24,8 @@ type node struct {
 	outbox chan []byte
 	inbox  chan [][]byte
 
-	log *bytes.Buffer
+	log  *bytes.Buffer
+	errs []error
 }
 
 func (n *node) Step(msg []byte) ([][]byte, error) {
@@ -39,6 +41,7 @@ func (n *node) Random() kyber.XOF {
 
 func runNode(n *node) {
 	errs := (func(Context) []error)(n.proto)(n)
+	n.errs = errs
 
 	fmt.Fprintf(n.log, "node %d finished\n", n.i)
 	for i := range errs {
@@ -121,3 +124,68 @@ func TestDeniable(t *testing.T) {
 		}
 	}
 }
+
+func TestDeniableThreshold(t *testing.T) {
+	nnodes := 3
+
+	suite := testSuite
+	rand := random.New()
+	B := suite.Point().Base()
+
+	// Every node knows the logarithms of X and Y, but not of Z,
+	// and proves that it knows two of the three.
+	pred := Threshold(2, Rep("X", "x", "B"), Rep("Y", "y", "B"), Rep("Z", "z", "B"))
+	pvals := make([]map[string]kyber.Point, nnodes)
+	svals := make([]map[string]kyber.Scalar, nnodes)
+	for i := range nnodes {
+		x := suite.Scalar().Pick(rand)
+		y := suite.Scalar().Pick(rand)
+		svals[i] = map[string]kyber.Scalar{"x": x, "y": y}
+		pvals[i] = map[string]kyber.Point{
+			"B": B,
+			"X": suite.Point().Mul(x, nil),
+			"Y": suite.Point().Mul(y, nil),
+			"Z": suite.Point().Pick(rand),
+		}
+	}
+
+	nodes := make([]*node, nnodes)
+	for i := range nnodes {
+		n := &node{i: i, log: &bytes.Buffer{}}
+		nodes[i] = n
+		prover := pred.Prover(suite, svals[i], pvals[i], map[Predicate]int{pred: 3})
+		vrfs := make([]Verifier, nnodes)
+		for j := range nnodes {
+			if j != i {
+				vrfs[j] = pred.Verifier(suite, pvals[j])
+			}
+		}
+		n.proto = DeniableProver(suite, i, prover, vrfs)
+		n.outbox = make(chan []byte)
+		n.inbox = make(chan [][]byte)
+		go runNode(n)
+	}
+
+	active := nnodes
+	for active > 0 {
+		msgs := make([][]byte, nnodes)
+		for i, n := range nodes {
+			if n == nil {
+				continue
+			}
+			msgs[i] = <-n.outbox
+			if n.done {
+				for j, err := range n.errs {
+					require.NoError(t, err, "node %d verifying %d", i, j)
+				}
+				nodes[i] = nil
+				active--
+			}
+		}
+		for _, n := range nodes {
+			if n != nil {
+				n.inbox <- msgs
+			}
+		}
+	}
+}
@@ -10,8 +10,10 @@ package dleq
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/msm"
 )
 
 // Suite wraps the functionalities needed by the dleq package.
@@ -170,3 +172,100 @@ func (p *Proof) Verify(suite Suite, G kyber.Point, H kyber.Point, xG kyber.Point
 	}
 	return nil
 }
+
+// VerifyBatch examines the validity of the NIZK dlog-equality proofs, the proof
+// i being for the points G[i], H[i], xG[i] and xH[i]. It returns the indices of
+// the invalid proofs, in increasing order, or nil if they are all valid.
+//
+// The conditions of all the proofs are combined with random weights into the
+// two conditions
+//
+//	sum(w_i*(r_i*G_i + c_i*xG_i - vG_i)) == 0
+//	sum(w_i*(r_i*H_i + c_i*xH_i - vH_i)) == 0
+//
+// which are checked with two multi-scalar multiplications. When they don't
+// hold, the proofs are split in halves which are checked recursively to find
+// the invalid ones.
+//
+// The combined conditions are only sound in groups of prime order, since a
+// component of small order cancels out when its weight is a multiple of its
+// order. In the other groups, such as edwards25519, or when the group doesn't
+// report its order with an IsPrimeOrder or a PrimeOrder method, each proof is
+// checked with Verify instead, so that batching brings no speedup there.
+func VerifyBatch(suite Suite, G, H, xG, xH []kyber.Point, proofs []*Proof) ([]int, error) {
+	n := len(proofs)
+	if len(G) != n || len(H) != n || len(xG) != n || len(xH) != n {
+		return nil, fmt.Errorf("invalid: %w", ErrDifferentLengths)
+	}
+
+	var failed, candidates []int
+	for i, p := range proofs {
+		if p == nil || p.C == nil || p.R == nil || p.VG == nil || p.VH == nil ||
+			G[i] == nil || H[i] == nil || xG[i] == nil || xH[i] == nil {
+			failed = append(failed, i)
+		} else {
+			candidates = append(candidates, i)
+		}
+	}
+
+	if !msm.PrimeOrder(suite) {
+		for _, i := range candidates {
+			if proofs[i].Verify(suite, G[i], H[i], xG[i], xH[i]) != nil {
+				failed = append(failed, i)
+			}
+		}
+		slices.Sort(failed)
+		return failed, nil
+	}
+
+	b := &batch{
+		suite: suite,
+		G:     G, H: H, xG: xG, xH: xH,
+		proofs:  proofs,
+		weights: make([]kyber.Scalar, n),
+	}
+	for _, i := range candidates {
+		b.weights[i] = suite.Scalar().Pick(suite.RandomStream())
+	}
+	failed = append(failed, b.search(candidates)...)
+	slices.Sort(failed)
+	return failed, nil
+}
+
+type batch struct {
+	suite        Suite
+	G, H, xG, xH []kyber.Point
+	proofs       []*Proof
+	weights      []kyber.Scalar
+}
+
+// search returns the indices of the invalid proofs among the given ones.
+func (b *batch) search(indices []int) []int {
+	if len(indices) == 0 || b.check(indices) {
+		return nil
+	}
+	if len(indices) == 1 {
+		return indices
+	}
+	half := len(indices) / 2
+	return append(b.search(indices[:half]), b.search(indices[half:])...)
+}
+
+// check checks the weighted sum of the conditions of the given proofs.
+func (b *batch) check(indices []int) bool {
+	scalars := make([]kyber.Scalar, 0, 3*len(indices))
+	gPoints := make([]kyber.Point, 0, 3*len(indices))
+	hPoints := make([]kyber.Point, 0, 3*len(indices))
+	for _, i := range indices {
+		p, w := b.proofs[i], b.weights[i]
+		scalars = append(scalars,
+			b.suite.Scalar().Mul(w, p.R),
+			b.suite.Scalar().Mul(w, p.C),
+			b.suite.Scalar().Neg(w))
+		gPoints = append(gPoints, b.G[i], b.xG[i], p.VG)
+		hPoints = append(hPoints, b.H[i], b.xH[i], p.VH)
+	}
+	null := b.suite.Point().Null()
+	return msm.MultiMul(b.suite, scalars, gPoints).Equal(null) &&
+		msm.MultiMul(b.suite, scalars, hPoints).Equal(null)
+}
@@ -1,6 +1,8 @@
 package dleq
 
 import (
+	"bytes"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -59,3 +61,101 @@ func TestDLEQLengths(t *testing.T) {
 	_, _, _, err := NewDLEQProofBatch(suite, g, h, x)
 	require.ErrorIs(t, err, ErrDifferentLengths)
 }
+
+func TestDLEQVerifyBatch(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n := 40
+	x := make([]kyber.Scalar, n)
+	g := make([]kyber.Point, n)
+	h := make([]kyber.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(rng)
+		g[i] = suite.Point().Pick(rng)
+		h[i] = suite.Point().Pick(rng)
+	}
+	proofs, xG, xH, err := NewDLEQProofBatch(suite, g, h, x)
+	require.NoError(t, err)
+	failed, err := VerifyBatch(suite, g, h, xG, xH, proofs)
+	require.NoError(t, err)
+	require.Nil(t, failed)
+
+	failed, err = VerifyBatch(suite, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, failed)
+
+	// break some of the proofs in different ways
+	one := suite.Scalar().One()
+	proofs[3].R = suite.Scalar().Add(proofs[3].R, one)
+	proofs[17].C = suite.Scalar().Add(proofs[17].C, one)
+	proofs[18].VH = suite.Point().Pick(rng)
+	xG[25] = suite.Point().Pick(rng)
+	proofs[39] = nil
+	failed, err = VerifyBatch(suite, g, h, xG, xH, proofs)
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 17, 18, 25, 39}, failed)
+	for i := range proofs {
+		if proofs[i] != nil && !slices.Contains(failed, i) {
+			require.NoError(t, proofs[i].Verify(suite, g[i], h[i], xG[i], xH[i]))
+		}
+	}
+
+	_, err = VerifyBatch(suite, g, h, xG, xH[1:], proofs)
+	require.ErrorIs(t, err, ErrDifferentLengths)
+}
+
+func TestDLEQVerifyBatchTorsion(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	// (0, -1), the point of order 2
+	enc := bytes.Repeat([]byte{0xff}, 32)
+	enc[0], enc[31] = 0xec, 0x7f
+	T := suite.Point()
+	require.NoError(t, T.UnmarshalBinary(enc))
+	require.True(t, suite.Point().Add(T, T).Equal(suite.Point().Null()))
+
+	n := 8
+	x := make([]kyber.Scalar, n)
+	g := make([]kyber.Point, n)
+	h := make([]kyber.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(rng)
+		g[i] = suite.Point().Pick(rng)
+		h[i] = suite.Point().Pick(rng)
+	}
+	proofs, xG, xH, err := NewDLEQProofBatch(suite, g, h, x)
+	require.NoError(t, err)
+	proofs[5].VG = suite.Point().Add(proofs[5].VG, T)
+	require.Error(t, proofs[5].Verify(suite, g[5], h[5], xG[5], xH[5]))
+
+	// A random weight is even half of the time, so a batch would miss it
+	for range 32 {
+		failed, err := VerifyBatch(suite, g, h, xG, xH, proofs)
+		require.NoError(t, err)
+		require.Equal(t, []int{5}, failed)
+	}
+}
+
+func BenchmarkDLEQVerify(b *testing.B) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	n := 100
+	x := make([]kyber.Scalar, n)
+	g := make([]kyber.Point, n)
+	h := make([]kyber.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(rng)
+		g[i] = suite.Point().Pick(rng)
+		h[i] = suite.Point().Pick(rng)
+	}
+	proofs, xG, xH, _ := NewDLEQProofBatch(suite, g, h, x)
+	b.Run("Single", func(b *testing.B) {
+		for b.Loop() {
+			for i := range proofs {
+				_ = proofs[i].Verify(suite, g[i], h[i], xG[i], xH[i])
+			}
+		}
+	})
+	b.Run("Batch", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = VerifyBatch(suite, g, h, xG, xH, proofs)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+//go:build !constantTime
+
+package dleq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519vartime"
+)
+
+// TestDLEQVerifyBatchPrimeOrder runs VerifyBatch on the prime-order subgroup
+// of edwards25519, where the proofs are checked in a batch.
+func TestDLEQVerifyBatchPrimeOrder(t *testing.T) {
+	suite := edwards25519vartime.NewBlakeSHA256Ed25519(false)
+	n := 20
+	x := make([]kyber.Scalar, n)
+	g := make([]kyber.Point, n)
+	h := make([]kyber.Point, n)
+	for i := range x {
+		x[i] = suite.Scalar().Pick(rng)
+		g[i] = suite.Point().Pick(rng)
+		h[i] = suite.Point().Pick(rng)
+	}
+	proofs, xG, xH, err := NewDLEQProofBatch(suite, g, h, x)
+	require.NoError(t, err)
+	failed, err := VerifyBatch(suite, g, h, xG, xH, proofs)
+	require.NoError(t, err)
+	require.Nil(t, failed)
+
+	proofs[2].R = suite.Scalar().Add(proofs[2].R, suite.Scalar().One())
+	xH[11] = suite.Point().Pick(rng)
+	failed, err = VerifyBatch(suite, g, h, xG, xH, proofs)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 11}, failed)
+}
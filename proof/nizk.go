@@ -0,0 +1,248 @@
+package proof
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/wire"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+)
+
+const nizkLabel = "kyber-proof-nizk-v1"
+
+// NIZK is a non-interactive proof of a predicate in compressed form: instead
+// of the commitments of the Rep predicates, which the verifier recomputes
+// from the challenges and the responses, it holds the challenge derived from
+// them. Its layout doesn't depend on the code that produced it, only on the
+// predicate.
+//
+// The challenge is derived from a transcript of the context, of the
+// predicate, of the public points and of the commitments, so that a proof
+// only verifies for the same statement and context.
+type NIZK struct {
+	// Challenge is the top-level challenge of the proof.
+	Challenge kyber.Scalar
+	// Responses are the sub-challenges of the Or and Threshold predicates
+	// and the responses of the Rep predicates, in the order in which the
+	// predicate produces them.
+	Responses []kyber.Scalar
+}
+
+// NewNIZK returns a proof of the predicate for the given secrets, points and
+// choice of branches, as for Predicate.Prover, bound to the context.
+func NewNIZK(suite Suite, context string, pred Predicate, secrets map[string]kyber.Scalar,
+	points map[string]kyber.Point, choice map[Predicate]int) (*NIZK, error) {
+	prf := proof{}.init(suite, pred)
+	t, err := nizkTranscript(suite, context, pred, prf, points)
+	if err != nil {
+		return nil, err
+	}
+
+	// the secrets of the prover go into the derivation of its nonces
+	var witness []byte
+	for _, name := range prf.svar[1:] {
+		if x, ok := secrets[name]; ok {
+			buf, err := x.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			witness = append(witness, buf...)
+		}
+	}
+
+	ctx := &nizkProver{suite: suite, t: t, witness: witness, nizk: &NIZK{}}
+	if err := prf.prove(pred, secrets, points, choice, ctx); err != nil {
+		return nil, err
+	}
+	return ctx.nizk, nil
+}
+
+// Verify checks the proof of the predicate for the given public points and
+// context.
+func (n *NIZK) Verify(suite Suite, context string, pred Predicate, points map[string]kyber.Point) error {
+	if n.Challenge == nil {
+		return errors.New("invalid proof: missing challenge")
+	}
+	prf := proof{}.init(suite, pred)
+	t, err := nizkTranscript(suite, context, pred, prf, points)
+	if err != nil {
+		return err
+	}
+	vc := &nizkVerifier{nizk: n}
+	prf.vc = vc
+	prf.pval = points
+	prf.vp = make(map[Predicate]*verifierPred)
+	prf.recompute = true
+
+	// Recompute the commitments from the challenges and responses
+	if e := pred.getCommits(prf, nil); e != nil {
+		return e
+	}
+	if e := pred.verify(prf, n.Challenge, nil); e != nil {
+		return e
+	}
+	if vc.i != len(n.Responses) {
+		return errors.New("invalid proof: too many responses")
+	}
+
+	// and check that they give the challenge
+	for _, vp := range prf.commits {
+		if e := t.AppendPoint("commitment", vp.V); e != nil {
+			return e
+		}
+	}
+	if !t.ChallengeScalar("challenge", suite).Equal(n.Challenge) {
+		return errors.New("invalid proof: challenge mismatch")
+	}
+	return nil
+}
+
+// VerifyNIZK decodes a proof encoded by NIZK.Marshal and checks it.
+func VerifyNIZK(suite Suite, context string, pred Predicate, points map[string]kyber.Point, proof []byte) error {
+	var n NIZK
+	if err := n.Unmarshal(proof, suite); err != nil {
+		return err
+	}
+	return n.Verify(suite, context, pred, points)
+}
+
+// Marshal returns the versioned binary encoding of the proof.
+func (n *NIZK) Marshal() ([]byte, error) {
+	e := wire.NewEncoder(wire.TagProofNIZK)
+	e.Scalar(n.Challenge)
+	e.Scalars(n.Responses)
+	return e.Finish()
+}
+
+// Unmarshal decodes a proof encoded by Marshal with scalars of the group g.
+func (n *NIZK) Unmarshal(data []byte, g kyber.Group) error {
+	d := wire.NewDecoder(data, wire.TagProofNIZK)
+	c := d.Scalar(g)
+	r := d.Scalars(g)
+	if err := d.Finish(); err != nil {
+		return err
+	}
+	*n = NIZK{Challenge: c, Responses: r}
+	return nil
+}
+
+// nizkTranscript returns the transcript of the statement, before the
+// commitments.
+func nizkTranscript(suite Suite, context string, pred Predicate, prf *proof,
+	points map[string]kyber.Point) (*transcript.Transcript, error) {
+	t := transcript.New(suite, nizkLabel)
+	t.AppendMessage("context", []byte(context))
+	t.AppendMessage("predicate", []byte(pred.String()))
+	for _, name := range prf.pvar[1:] {
+		P, ok := points[name]
+		if !ok || P == nil {
+			return nil, errors.New("missing point " + name)
+		}
+		t.AppendMessage("name", []byte(name))
+		if err := t.AppendPoint("point", P); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Compressed noninteractive Sigma-protocol prover context
+type nizkProver struct {
+	suite   Suite
+	t       *transcript.Transcript
+	witness []byte
+	nizk    *NIZK
+}
+
+func (c *nizkProver) Put(message any) error {
+	switch m := message.(type) {
+	case kyber.Point:
+		// Commitments only go into the transcript
+		if c.nizk.Challenge != nil {
+			return errors.New("commitment after the challenge")
+		}
+		return c.t.AppendPoint("commitment", m)
+	case kyber.Scalar:
+		c.nizk.Responses = append(c.nizk.Responses, m.Clone())
+	case []kyber.Scalar:
+		for _, s := range m {
+			c.nizk.Responses = append(c.nizk.Responses, s.Clone())
+		}
+	default:
+		return errors.New("unexpected message in compressed proof")
+	}
+	return nil
+}
+
+// Get the challenge from the transcript of the statement and commitments.
+func (c *nizkProver) PubRand(data ...any) error {
+	if len(data) != 1 || c.nizk.Challenge != nil {
+		return errors.New("compressed proofs have a single challenge")
+	}
+	s, ok := data[0].(kyber.Scalar)
+	if !ok {
+		return errors.New("compressed proofs have a scalar challenge")
+	}
+	c.nizk.Challenge = c.t.ChallengeScalar("challenge", c.suite)
+	s.Set(c.nizk.Challenge)
+	return nil
+}
+
+// Get private randomness, derived from the transcript so far, the secrets and
+// the random stream of the suite.
+func (c *nizkProver) PriRand(data ...any) error {
+	stream := c.t.WitnessStream("witness", c.witness, c.suite.RandomStream())
+	return c.suite.Read(stream, data...)
+}
+
+// Compressed noninteractive Sigma-protocol verifier context
+type nizkVerifier struct {
+	nizk *NIZK
+	i    int // index of the next response
+}
+
+func (c *nizkVerifier) next() (kyber.Scalar, error) {
+	if c.i >= len(c.nizk.Responses) {
+		return nil, errors.New("invalid proof: missing responses")
+	}
+	r := c.nizk.Responses[c.i]
+	if r == nil {
+		return nil, errors.New("invalid proof: missing response")
+	}
+	c.i++
+	return r, nil
+}
+
+func (c *nizkVerifier) Get(message any) error {
+	switch m := message.(type) {
+	case kyber.Scalar:
+		r, err := c.next()
+		if err != nil {
+			return err
+		}
+		m.Set(r)
+	case []kyber.Scalar:
+		for i := range m {
+			r, err := c.next()
+			if err != nil {
+				return err
+			}
+			m[i] = r.Clone()
+		}
+	default:
+		return errors.New("unexpected message in compressed proof")
+	}
+	return nil
+}
+
+func (c *nizkVerifier) PubRand(data ...any) error {
+	if len(data) != 1 {
+		return errors.New("compressed proofs have a single challenge")
+	}
+	s, ok := data[0].(kyber.Scalar)
+	if !ok {
+		return errors.New("compressed proofs have a scalar challenge")
+	}
+	s.Set(c.nizk.Challenge)
+	return nil
+}
@@ -0,0 +1,133 @@
+package proof
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+)
+
+func TestNIZK(t *testing.T) {
+	suite := testSuite
+	rand := suite.RandomStream()
+	x := suite.Scalar().Pick(rand)
+	y := suite.Scalar().Pick(rand)
+	B := suite.Point().Base()
+	H := suite.Point().Pick(rand)
+	G := suite.Point().Pick(rand)
+	sval := map[string]kyber.Scalar{"x": x, "y": y}
+	pval := map[string]kyber.Point{
+		"B": B, "H": H, "G": G,
+		"X": suite.Point().Add(suite.Point().Mul(x, B), suite.Point().Mul(y, H)),
+		"Y": suite.Point().Mul(x, G),
+		"Z": suite.Point().Pick(rand),
+		"W": suite.Point().Pick(rand),
+	}
+
+	or := Or(Rep("Y", "x", "G"), Rep("Z", "z", "G"))
+	th := Threshold(2, Rep("X", "x", "B", "y", "H"), Rep("Z", "z", "G"), Rep("Y", "x", "G"), Rep("W", "w", "G"))
+	th2 := Threshold(2, Rep("X", "x", "B", "y", "H"), Rep("Z", "z", "G"), Rep("Y", "x", "G"), Rep("W", "w", "G"))
+	outer := Or(th2, Rep("W", "w", "B"))
+	for _, test := range []struct {
+		pred      Predicate
+		choice    map[Predicate]int
+		responses int
+	}{
+		{Rep("Y", "x", "G"), nil, 1},
+		{And(Rep("X", "x", "B", "y", "H"), Rep("Y", "x", "G")), nil, 2},
+		{or, map[Predicate]int{or: 0}, 4},
+		{th, map[Predicate]int{th: 1<<0 | 1<<2}, 2 + 5},
+		{outer, map[Predicate]int{outer: 0, th2: 1<<0 | 1<<2}, 2 + 2 + 5 + 1},
+	} {
+		pred := test.pred
+		if test.choice == nil {
+			test.choice = map[Predicate]int{}
+		}
+		nizk, err := NewNIZK(suite, "ctx", pred, sval, pval, test.choice)
+		require.NoError(t, err, pred.String())
+		require.Len(t, nizk.Responses, test.responses, pred.String())
+		require.NoError(t, nizk.Verify(suite, "ctx", pred, pval), pred.String())
+
+		data, err := nizk.Marshal()
+		require.NoError(t, err)
+		require.Len(t, data, 2+32+4+32*test.responses)
+		require.NoError(t, VerifyNIZK(suite, "ctx", pred, pval, data))
+
+		// the proof is bound to the context, the statement and the points
+		require.Error(t, nizk.Verify(suite, "other", pred, pval))
+		parsed, err := Parse(pred.String())
+		require.NoError(t, err)
+		require.NoError(t, nizk.Verify(suite, "ctx", parsed, pval))
+		require.Error(t, nizk.Verify(suite, "ctx", And(pred, Rep("Y", "x", "G")), pval))
+		other := map[string]kyber.Point{}
+		for k, v := range pval {
+			other[k] = v
+		}
+		other["G"] = suite.Point().Pick(rand)
+		require.Error(t, nizk.Verify(suite, "ctx", pred, other))
+
+		// tampering
+		for i := range nizk.Responses {
+			r := nizk.Responses[i]
+			nizk.Responses[i] = suite.Scalar().Add(r, suite.Scalar().One())
+			require.Error(t, nizk.Verify(suite, "ctx", pred, pval))
+			nizk.Responses[i] = r
+		}
+		c := nizk.Challenge
+		nizk.Challenge = suite.Scalar().Add(c, suite.Scalar().One())
+		require.Error(t, nizk.Verify(suite, "ctx", pred, pval))
+		nizk.Challenge = c
+		nizk.Responses = append(nizk.Responses, suite.Scalar().One())
+		require.Error(t, nizk.Verify(suite, "ctx", pred, pval))
+		nizk.Responses = nizk.Responses[:len(nizk.Responses)-2]
+		require.Error(t, nizk.Verify(suite, "ctx", pred, pval))
+	}
+
+	// missing points
+	pred := Rep("Y", "x", "V")
+	_, err := NewNIZK(suite, "ctx", pred, sval, pval, nil)
+	require.Error(t, err)
+	require.Error(t, (&NIZK{}).Verify(suite, "ctx", pred, pval))
+}
+
+func TestNIZKSize(t *testing.T) {
+	// the compressed proof is smaller than the one of HashProve as soon as
+	// there are a few commitments
+	suite := testSuite
+	rand := suite.RandomStream()
+	x := suite.Scalar().Pick(rand)
+	B := suite.Point().Base()
+	pval := map[string]kyber.Point{"B": B, "X": suite.Point().Mul(x, nil)}
+	preds := []Predicate{Rep("X", "x", "B")}
+	for i := range 3 {
+		name := string(rune('A' + i))
+		pval[name] = suite.Point().Pick(rand)
+		preds = append(preds, Rep(name, "y"+name, "B"))
+	}
+	pred := Or(preds...)
+	sval := map[string]kyber.Scalar{"x": x}
+	choice := map[Predicate]int{pred: 0}
+
+	nizk, err := NewNIZK(suite, "ctx", pred, sval, pval, choice)
+	require.NoError(t, err)
+	compressed, err := nizk.Marshal()
+	require.NoError(t, err)
+	full, err := HashProve(suite, "ctx", pred.Prover(suite, sval, pval, choice))
+	require.NoError(t, err)
+	require.Less(t, len(compressed), len(full))
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	suite := testSuite
+	x := suite.Scalar().Pick(suite.RandomStream())
+	pred := Rep("X", "x", "B")
+	pval := map[string]kyber.Point{"B": suite.Point().Base(), "X": suite.Point().Mul(x, nil)}
+	nizk, err := NewNIZK(suite, "ctx", pred, map[string]kyber.Scalar{"x": x}, pval, nil)
+	require.NoError(f, err)
+	data, err := nizk.Marshal()
+	require.NoError(f, err)
+	f.Add(data)
+	f.Fuzz(func(_ *testing.T, data []byte) {
+		_ = VerifyNIZK(suite, "ctx", pred, pval, data)
+	})
+}
@@ -0,0 +1,296 @@
+package proof
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError is returned by Parse for malformed predicates.
+type SyntaxError struct {
+	Offset int    // byte offset of the offending token in the input
+	Token  string // offending token, empty at the end of the input
+	Msg    string // description of the error
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("proof: syntax error at offset %d, end of input: %s", e.Offset, e.Msg)
+	}
+	return fmt.Sprintf("proof: syntax error at offset %d, near %q: %s", e.Offset, e.Token, e.Msg)
+}
+
+// Parse returns the predicate described by s, in the syntax produced by the
+// String method of predicates:
+//
+//	X=x*B+y*H && (Y=x*G || Z=z*G)
+//	threshold(2, X=x*B, Y=y*B, Z=z*B)
+//
+// Rep predicates are written P=x1*B1+...+xn*Bn, And and Or predicates are
+// written with the && and || operators, && binding tighter than ||, and
+// Threshold predicates as threshold(k, sub1, ..., subn). Parentheses group
+// sub-predicates, and spaces are ignored. Variable names are made of
+// letters, digits and underscores, and don't start with a digit.
+//
+// Chains of the same operator give a single And or Or predicate with all
+// the operands, so that Parse(pred.String()) is equivalent to pred.
+func Parse(s string) (Predicate, error) {
+	p := &parser{lex: lexer{input: s}}
+	p.next()
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected token")
+	}
+	return pred, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokInt
+	tokEqual
+	tokStar
+	tokPlus
+	tokComma
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokInvalid
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+func (l *lexer) next() token {
+	for l.pos < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		l.pos += size
+	}
+	start := l.pos
+	if start == len(l.input) {
+		return token{tokEOF, "", start}
+	}
+	tok := func(kind tokenKind, size int) token {
+		l.pos += size
+		return token{kind, l.input[start:l.pos], start}
+	}
+
+	r, size := utf8.DecodeRuneInString(l.input[start:])
+	switch {
+	case r == '=':
+		return tok(tokEqual, 1)
+	case r == '*':
+		return tok(tokStar, 1)
+	case r == '+':
+		return tok(tokPlus, 1)
+	case r == ',':
+		return tok(tokComma, 1)
+	case r == '(':
+		return tok(tokLParen, 1)
+	case r == ')':
+		return tok(tokRParen, 1)
+	case r == '&' || r == '|':
+		if start+1 < len(l.input) && rune(l.input[start+1]) == r {
+			if r == '&' {
+				return tok(tokAnd, 2)
+			}
+			return tok(tokOr, 2)
+		}
+		return tok(tokInvalid, 1)
+	case '0' <= r && r <= '9':
+		end := start
+		for end < len(l.input) && '0' <= l.input[end] && l.input[end] <= '9' {
+			end++
+		}
+		return tok(tokInt, end-start)
+	case isIdentRune(r):
+		end := start
+		for end < len(l.input) {
+			r, size := utf8.DecodeRuneInString(l.input[end:])
+			if !isIdentRune(r) && !unicode.IsDigit(r) {
+				break
+			}
+			end += size
+		}
+		return tok(tokIdent, end-start)
+	default:
+		return tok(tokInvalid, size)
+	}
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	or        = and { "||" and }
+//	and       = primary { "&&" primary }
+//	primary   = "(" or ")" | threshold | rep
+//	threshold = "threshold" "(" int { "," or } ")"
+//	rep       = ident "=" ident "*" ident { "+" ident "*" ident }
+type parser struct {
+	lex lexer
+	tok token
+}
+
+func (p *parser) next() {
+	p.tok = p.lex.next()
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{
+		Offset: p.tok.offset,
+		Token:  p.tok.text,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+// expect consumes a token of the given kind and returns its text.
+func (p *parser) expect(kind tokenKind, what string) (string, error) {
+	if p.tok.kind != kind {
+		return "", p.errorf("expected %s", what)
+	}
+	text := p.tok.text
+	p.next()
+	return text, nil
+}
+
+func (p *parser) parseOr() (Predicate, error) {
+	sub, err := p.parseList(tokOr, p.parseAnd)
+	if err != nil {
+		return nil, err
+	}
+	if len(sub) == 1 {
+		return sub[0], nil
+	}
+	return Or(sub...), nil
+}
+
+func (p *parser) parseAnd() (Predicate, error) {
+	sub, err := p.parseList(tokAnd, p.parsePrimary)
+	if err != nil {
+		return nil, err
+	}
+	if len(sub) == 1 {
+		return sub[0], nil
+	}
+	return And(sub...), nil
+}
+
+// parseList parses operands separated by the operator.
+func (p *parser) parseList(op tokenKind, operand func() (Predicate, error)) ([]Predicate, error) {
+	var sub []Predicate
+	for {
+		pred, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		sub = append(sub, pred)
+		if p.tok.kind != op {
+			return sub, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parsePrimary() (Predicate, error) {
+	switch p.tok.kind {
+	case tokLParen:
+		p.next()
+		pred, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return pred, nil
+	case tokIdent:
+		name := p.tok.text
+		p.next()
+		if name == "threshold" && p.tok.kind == tokLParen {
+			return p.parseThreshold()
+		}
+		return p.parseRep(name)
+	default:
+		return nil, p.errorf("expected predicate")
+	}
+}
+
+func (p *parser) parseThreshold() (Predicate, error) {
+	p.next() // "("
+	if p.tok.kind != tokInt {
+		return nil, p.errorf("expected threshold")
+	}
+	kTok := p.tok
+	k, err := strconv.Atoi(p.tok.text)
+	if err != nil {
+		return nil, p.errorf("invalid threshold")
+	}
+	p.next()
+	var sub []Predicate
+	for p.tok.kind == tokComma {
+		p.next()
+		pred, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		sub = append(sub, pred)
+	}
+	if _, err := p.expect(tokRParen, "',' or ')'"); err != nil {
+		return nil, err
+	}
+	if k < 1 || k > len(sub) {
+		return nil, &SyntaxError{
+			Offset: kTok.offset,
+			Token:  kTok.text,
+			Msg:    fmt.Sprintf("threshold out of range for %d sub-predicates", len(sub)),
+		}
+	}
+	return Threshold(k, sub...), nil
+}
+
+// parseRep parses a Rep predicate whose point P was already consumed.
+func (p *parser) parseRep(P string) (Predicate, error) {
+	if _, err := p.expect(tokEqual, "'='"); err != nil {
+		return nil, err
+	}
+	var SB []string
+	for {
+		x, err := p.expect(tokIdent, "scalar name")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokStar, "'*'"); err != nil {
+			return nil, err
+		}
+		B, err := p.expect(tokIdent, "point name")
+		if err != nil {
+			return nil, err
+		}
+		SB = append(SB, x, B)
+		if p.tok.kind != tokPlus {
+			return Rep(P, SB...), nil
+		}
+		p.next()
+	}
+}
@@ -0,0 +1,111 @@
+package proof
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+)
+
+func TestParse(t *testing.T) {
+	preds := []Predicate{
+		Rep("X", "x", "B"),
+		Rep("X", "x", "B", "y", "H"),
+		And(Rep("X", "x", "B"), Rep("Y", "x", "G"), Rep("Z", "z", "G")),
+		Or(Rep("X", "x", "B"), Rep("Y", "y", "B")),
+		Or(And(Rep("X", "x", "B"), Rep("Y", "y", "B")), Rep("Z", "z", "B")),
+		And(Rep("X", "x", "B", "y", "H"), Or(Rep("Y", "x", "G"), Rep("Z", "z", "G"))),
+		Threshold(2, Rep("X", "x", "B"), Or(Rep("Y", "y", "B"), Rep("W", "w", "B")),
+			And(Rep("Z", "z", "B"), Rep("V", "v", "B"))),
+		Or(Threshold(1, Rep("X1", "x_1", "B")), Rep("threshold", "x", "threshold")),
+		Rep("Xé", "é", "B"),
+	}
+	for _, pred := range preds {
+		parsed, err := Parse(pred.String())
+		require.NoError(t, err, pred.String())
+		require.Equal(t, pred.String(), parsed.String())
+	}
+
+	pred, err := Parse(" X = x * B + y*H&&( Y=x*G||Z=z*G )\n")
+	require.NoError(t, err)
+	require.Equal(t, "X=x*B+y*H && (Y=x*G || Z=z*G)", pred.String())
+	pred, err = Parse("((X=x*B))")
+	require.NoError(t, err)
+	require.Equal(t, "X=x*B", pred.String())
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, test := range []struct {
+		input  string
+		offset int
+		token  string
+	}{
+		{"", 0, ""},
+		{"X", 1, ""},
+		{"X=", 2, ""},
+		{"X=x", 3, ""},
+		{"X=x*", 4, ""},
+		{"X=x*B+", 6, ""},
+		{"X=x*B +&& Y=y*B", 7, "&&"},
+		{"X=x*B & Y=y*B", 6, "&"},
+		{"X=x*B || ", 9, ""},
+		{"X=x*B Y=y*B", 6, "Y"},
+		{"(X=x*B", 6, ""},
+		{"X=x*B)", 5, ")"},
+		{"X=1*B", 2, "1"},
+		{"X=x*B # comment", 6, "#"},
+		{"threshold(X=x*B)", 10, "X"},
+		{"threshold(2, X=x*B)", 10, "2"},
+		{"threshold(0, X=x*B)", 10, "0"},
+		{"threshold(1 X=x*B)", 12, "X"},
+		{"threshold(99999999999999999999, X=x*B)", 10, "99999999999999999999"},
+	} {
+		_, err := Parse(test.input)
+		var syntaxErr *SyntaxError
+		require.True(t, errors.As(err, &syntaxErr), "%q: %v", test.input, err)
+		require.Equal(t, test.offset, syntaxErr.Offset, "%q: %v", test.input, err)
+		require.Equal(t, test.token, syntaxErr.Token, "%q: %v", test.input, err)
+	}
+}
+
+func TestParseProve(t *testing.T) {
+	suite := testSuite
+	rand := suite.RandomStream()
+	x := suite.Scalar().Pick(rand)
+	y := suite.Scalar().Pick(rand)
+	B := suite.Point().Base()
+	H := suite.Point().Pick(rand)
+	G := suite.Point().Pick(rand)
+	X := suite.Point().Add(suite.Point().Mul(x, B), suite.Point().Mul(y, H))
+	pval := map[string]kyber.Point{
+		"B": B, "H": H, "G": G, "X": X,
+		"Y": suite.Point().Mul(x, G),
+		"Z": suite.Point().Pick(rand),
+	}
+	sval := map[string]kyber.Scalar{"x": x, "y": y}
+
+	pred, err := Parse("(X=x*B+y*H && Y=x*G) || Z=z*G")
+	require.NoError(t, err)
+	proof, err := HashProve(suite, "TEST", pred.Prover(suite, sval, pval, map[Predicate]int{pred: 0}))
+	require.NoError(t, err)
+
+	// the verifier parses the predicate on its own
+	pred, err = Parse(pred.String())
+	require.NoError(t, err)
+	require.NoError(t, HashVerify(suite, "TEST", pred.Verifier(suite, pval), proof))
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add("X=x*B+y*H && (Y=x*G || Z=z*G)")
+	f.Add("threshold(2, X=x*B, Y=y*B, Z=z*B || W=w*B)")
+	f.Fuzz(func(t *testing.T, s string) {
+		pred, err := Parse(s)
+		if err != nil {
+			return
+		}
+		parsed, err := Parse(pred.String())
+		require.NoError(t, err)
+		require.Equal(t, pred.String(), parsed.String())
+	})
+}
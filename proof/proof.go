@@ -7,9 +7,12 @@ package proof
 
 import (
 	"errors"
+	"math/bits"
+	"strconv"
 	"strings"
 
 	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
 )
 
 // Suite defines the functionalities needed for this package to operate
@@ -30,7 +33,8 @@ Atomic predicates in this system are statements of the form P=x1*B1+...+xn*Bn,
 indicating the prover knows secrets x1,...,xn that make the statement true,
 where P and B1,...,Bn are public points known to the verifier.
 These atomic Rep (representation) predicates may be combined
-with logical And and Or combinators to form composite statements.
+with logical And and Or combinators, and with Threshold combinators
+stating that k of n sub-predicates are true, to form composite statements.
 Predicate objects, once created, are immutable and safe to share
 or reuse for any number of proofs and verifications.
 
@@ -120,6 +124,11 @@ type proof struct {
 	// verifier-specific state
 	vc VerifierContext
 	vp map[Predicate]*verifierPred // per-predicate verifier state
+
+	// compressed verifier: the commitments aren't part of the proof,
+	// they are recomputed from the challenges and responses
+	recompute bool
+	commits   []*verifierPred // Rep predicates in commitment order
 }
 type proverPred struct {
 	w  kyber.Scalar   // secret pre-challenge
@@ -280,7 +289,9 @@ func (rp *repPred) getCommits(prf *proof, pr []kyber.Scalar) error {
 	prf.vp[rp] = vp
 
 	// Get the commitment for this representation
-	if e := prf.vc.Get(vp.V); e != nil {
+	if prf.recompute {
+		prf.commits = append(prf.commits, vp)
+	} else if e := prf.vc.Get(vp.V); e != nil {
 		return e
 	}
 
@@ -314,6 +325,10 @@ func (rp *repPred) verify(prf *proof, c kyber.Scalar, pr []kyber.Scalar) error {
 		P.Mul(r[s], prf.pval[t.B])
 		V.Add(V, P)
 	}
+	if prf.recompute {
+		vp.V = V
+		return nil
+	}
 	if !V.Equal(vp.V) {
 		return errors.New("invalid proof: commit mismatch")
 	}
@@ -634,6 +649,247 @@ func (op *orPred) Verifier(suite Suite,
 	return proof{}.init(suite, op).verifier(op, points)
 }
 
+////////// Threshold predicate //////////
+
+type thresholdPred struct {
+	k   int         // number of sub-predicates known to the prover
+	sub []Predicate // sub-predicates
+}
+
+// Threshold predicate states that the prover knows
+// at least k of the sub-predicates to be true,
+// but the proof does not reveal any information about which.
+// With k=1 it is equivalent to Or, and with k=len(sub) to And.
+//
+// The proof is the one of Cramer, Damgard and Schoenmakers,
+// "Proofs of Partial Knowledge and Simplified Design
+// of Witness Hiding Protocols":
+// the sub-challenges are the values at 1,...,n of a polynomial
+// of degree n-k whose value at 0 is the challenge of the predicate,
+// so that the prover can choose the sub-challenges
+// of at most n-k sub-predicates it doesn't know.
+//
+// As with Or, Threshold predicates can't be nested in And predicates.
+// When proving, the caller passes in the choice map
+// the bitmask of the sub-predicates the prover knows
+// for each Threshold predicate in the proof-obligated path:
+// bit i is set if the prover knows sub-predicate i.
+// A proof of a Threshold predicate can thus have at most
+// bits.UintSize sub-predicates; proving one with more fails.
+func Threshold(k int, sub ...Predicate) Predicate {
+	if k < 1 || k > len(sub) {
+		panic("threshold out of range")
+	}
+	return &thresholdPred{k, sub}
+}
+
+// Return a string representation of this threshold predicate,
+// mainly for debugging.
+func (tp *thresholdPred) String() string {
+	return tp.precString(precNone)
+}
+
+func (tp *thresholdPred) precString(_ int) string {
+	var b strings.Builder
+	b.WriteString("threshold(")
+	b.WriteString(strconv.Itoa(tp.k))
+	for _, sub := range tp.sub {
+		b.WriteString(", ")
+		b.WriteString(sub.precString(precNone))
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+func (tp *thresholdPred) enumVars(prf *proof) {
+	for i := range tp.sub {
+		tp.sub[i].enumVars(prf)
+	}
+}
+
+func (tp *thresholdPred) commit(prf *proof, w kyber.Scalar, pv []kyber.Scalar) error {
+	if pv != nil { // only happens within an AND expression
+		return errors.New("can't have threshold predicates within AND predicates")
+	}
+	n := len(tp.sub)
+
+	// Create per-predicate prover state
+	wi := make([]kyber.Scalar, n)
+	pp := &proverPred{w, nil, wi}
+	prf.pp[tp] = pp
+
+	// Choose pre-challenges for our subs.
+	switch w {
+	case nil:
+		// We're on a proof-obligated branch;
+		// choose random pre-challenges for the subs we don't prove.
+		known, err := tp.known(prf)
+		if err != nil {
+			return err
+		}
+		for i := range tp.sub {
+			if !known[i] {
+				wi[i] = prf.s.Scalar()
+				if err := prf.pc.PriRand(wi[i]); err != nil {
+					return err
+				}
+			} // else wi[i] == nil for proof-obligated subs
+		}
+	default:
+		// Since w != nil, we're in a non-obligated branch,
+		// so choose random pre-challenges for n-k subs,
+		// and derive the others from the polynomial
+		// through them and the master pre-challenge w.
+		free := n - tp.k
+		for i := range free {
+			wi[i] = prf.s.Scalar()
+			if err := prf.pc.PriRand(wi[i]); err != nil {
+				return err
+			}
+		}
+		if err := tp.interpolate(prf, w, wi); err != nil {
+			return err
+		}
+	}
+
+	return commitmentProducer(prf, wi, tp.sub)
+}
+
+// known returns the k sub-predicates the prover proves,
+// i.e. the first k ones whose bit is set in the choice.
+func (tp *thresholdPred) known(prf *proof) ([]bool, error) {
+	if len(tp.sub) > bits.UintSize {
+		return nil, errors.New("too many sub-predicates for the choice of threshold predicate " +
+			tp.String())
+	}
+	choice, ok := prf.choice[tp]
+	if !ok {
+		return nil, errors.New("no choice of proof branches for threshold predicate " +
+			tp.String())
+	}
+	known := make([]bool, len(tp.sub))
+	count := 0
+	for i := 0; i < len(tp.sub) && count < tp.k; i++ {
+		if uint(choice)>>i&1 == 1 {
+			known[i] = true
+			count++
+		}
+	}
+	if count < tp.k {
+		return nil, errors.New("not enough proof branches for threshold predicate " +
+			tp.String())
+	}
+	return known, nil
+}
+
+// interpolate fills the nil sub-challenges with the values
+// of the polynomial of degree n-k through (0, c) and the others.
+// It is recovered with the share package, shifted by one:
+// the share of index j, which the package places at j+1,
+// holds the value at j, so that (0, c) is the share of index 0.
+func (tp *thresholdPred) interpolate(prf *proof, c kyber.Scalar, ci []kyber.Scalar) error {
+	shares := []*share.PriShare{{I: 0, V: c}}
+	for i := range ci {
+		if ci[i] != nil {
+			shares = append(shares, &share.PriShare{I: uint32(i + 1), V: ci[i]})
+		}
+	}
+	poly, err := share.RecoverPriPoly(prf.s, shares, uint32(len(shares)), uint32(len(ci)+1))
+	if err != nil {
+		return err
+	}
+	for i := range ci {
+		if ci[i] == nil {
+			ci[i] = poly.Eval(uint32(i + 1)).V
+		}
+	}
+	return nil
+}
+
+func (tp *thresholdPred) respond(prf *proof, c kyber.Scalar, pr []kyber.Scalar) error {
+	pp := prf.pp[tp]
+	if pr != nil {
+		return errors.New("threshold predicates can't be nested in anything else")
+	}
+
+	ci := pp.wi
+	if pp.w == nil {
+		// Calculate the challenges for the proof-obligated subtrees
+		if err := tp.interpolate(prf, c, ci); err != nil {
+			return err
+		}
+	}
+
+	// Send the sub-challenges which determine the polynomial,
+	// if it isn't constant.
+	free := len(tp.sub) - tp.k
+	if free > 0 {
+		if e := prf.pc.Put(ci[:free]); e != nil {
+			return e
+		}
+	}
+
+	// Recursively compute responses in all subtrees
+	for i := range tp.sub {
+		if e := tp.sub[i].respond(prf, ci[i], nil); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Get from the verifier all the commitments needed for this predicate
+func (tp *thresholdPred) getCommits(prf *proof, _ []kyber.Scalar) error {
+	for i := range tp.sub {
+		if e := tp.sub[i].getCommits(prf, nil); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (tp *thresholdPred) verify(prf *proof, c kyber.Scalar, pr []kyber.Scalar) error {
+	if pr != nil {
+		return errors.New("threshold predicates can't be in anything else")
+	}
+
+	// Get the prover's free sub-challenges,
+	// and derive the others from the polynomial through them.
+	// Any set of sub-challenges is valid as long as it is on
+	// a polynomial of degree n-k whose value at 0 is c.
+	free := len(tp.sub) - tp.k
+	ci := make([]kyber.Scalar, len(tp.sub))
+	if free > 0 {
+		if e := prf.vc.Get(ci[:free]); e != nil {
+			return e
+		}
+	}
+	if err := tp.interpolate(prf, c, ci); err != nil {
+		return err
+	}
+
+	// Recursively verify all subs
+	for i := range tp.sub {
+		if e := tp.sub[i].verify(prf, ci[i], nil); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (tp *thresholdPred) Prover(suite Suite, secrets map[string]kyber.Scalar,
+	points map[string]kyber.Point,
+	choice map[Predicate]int) Prover {
+	return proof{}.init(suite, tp).prover(tp, secrets, points, choice)
+}
+
+func (tp *thresholdPred) Verifier(suite Suite,
+	points map[string]kyber.Point) Verifier {
+	return proof{}.init(suite, tp).verifier(tp, points)
+}
+
 /*
 type lin struct {
 	a1,a2,b kyber.Scalar
@@ -0,0 +1,143 @@
+package proof
+
+import (
+	"fmt"
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+func TestThreshold(t *testing.T) {
+	suite := testSuite
+	rand := suite.RandomStream()
+	B := suite.Point().Base()
+
+	// we know x0, x1 and x3, but not the logarithms of X2 and X4
+	n := 5
+	sval := map[string]kyber.Scalar{}
+	pval := map[string]kyber.Point{"B": B}
+	reps := make([]Predicate, n)
+	for i := range n {
+		x := suite.Scalar().Pick(rand)
+		X := suite.Point().Mul(x, nil)
+		if i == 2 || i == 4 {
+			X = suite.Point().Pick(rand)
+		} else {
+			sval[fmt.Sprintf("x%d", i)] = x
+		}
+		pval[fmt.Sprintf("X%d", i)] = X
+		reps[i] = Rep(fmt.Sprintf("X%d", i), fmt.Sprintf("x%d", i), "B")
+	}
+	known := 1<<0 | 1<<1 | 1<<3
+
+	prove := func(pred Predicate, choice map[Predicate]int) error {
+		proof, err := HashProve(suite, "TEST", pred.Prover(suite, sval, pval, choice))
+		if err != nil {
+			return err
+		}
+		return HashVerify(suite, "TEST", pred.Verifier(suite, pval), proof)
+	}
+
+	for k := 1; k <= 3; k++ {
+		pred := Threshold(k, reps...)
+		require.NoError(t, prove(pred, map[Predicate]int{pred: known}), "k=%d", k)
+	}
+	pred := Threshold(2, reps...)
+	require.NoError(t, prove(pred, map[Predicate]int{pred: 1<<1 | 1<<3}))
+	require.Error(t, prove(pred, map[Predicate]int{pred: 1 << 1}))
+	require.Error(t, prove(pred, nil))
+
+	// we can't prove more than we know
+	sval["x2"] = suite.Scalar().Pick(rand)
+	pred = Threshold(4, reps...)
+	require.Error(t, prove(pred, map[Predicate]int{pred: known | 1<<2}))
+	delete(sval, "x2")
+	require.Panics(t, func() { Threshold(3, reps[:2]...) })
+
+	// all of them
+	pred = Threshold(2, reps[0], reps[1])
+	require.NoError(t, prove(pred, map[Predicate]int{pred: 3}))
+
+	// composition with And and Or
+	and := And(reps[0], reps[1])
+	or := Or(reps[2], reps[3])
+	pred = Threshold(2, and, or, reps[4])
+	require.NoError(t, prove(pred, map[Predicate]int{pred: 3, or: 1}))
+	th := Threshold(2, reps[2], reps[3], reps[4])
+	pred = Or(th, reps[0])
+	require.NoError(t, prove(pred, map[Predicate]int{pred: 1}))
+	require.Error(t, prove(pred, map[Predicate]int{pred: 0, th: 1 << 3}))
+	pred = Threshold(1, Threshold(2, reps[2], reps[3], reps[4]),
+		Threshold(2, reps[0], reps[1], Rep("X2", "x2", "B")))
+	require.NoError(t, prove(pred, map[Predicate]int{pred: 2, pred.(*thresholdPred).sub[1]: 3}))
+	pred = And(reps[0], Threshold(1, reps[1], reps[2]))
+	require.Error(t, prove(pred, map[Predicate]int{}))
+}
+
+func TestThresholdInvalid(t *testing.T) {
+	suite := testSuite
+	rand := suite.RandomStream()
+	B := suite.Point().Base()
+	x := suite.Scalar().Pick(rand)
+	sval := map[string]kyber.Scalar{"x": x}
+	pval := map[string]kyber.Point{
+		"B": B,
+		"X": suite.Point().Mul(x, nil),
+		"Y": suite.Point().Pick(rand),
+		"Z": suite.Point().Pick(rand),
+	}
+	// a 2-of-3 proof where the prover only knows x: simulate a proof with a
+	// 1-of-3 prover and try to pass it off as 2-of-3
+	one := Threshold(1, Rep("X", "x", "B"), Rep("Y", "y", "B"), Rep("Z", "z", "B"))
+	two := Threshold(2, Rep("X", "x", "B"), Rep("Y", "y", "B"), Rep("Z", "z", "B"))
+	proof, err := HashProve(suite, "TEST", one.Prover(suite, sval, pval, map[Predicate]int{one: 1}))
+	require.NoError(t, err)
+	require.NoError(t, HashVerify(suite, "TEST", one.Verifier(suite, pval), proof))
+	require.Error(t, HashVerify(suite, "TEST", two.Verifier(suite, pval), proof))
+}
+
+func TestThresholdTooManySubs(t *testing.T) {
+	suite := testSuite
+	x := suite.Scalar().Pick(suite.RandomStream())
+	sval := map[string]kyber.Scalar{"x": x}
+	pval := map[string]kyber.Point{"B": suite.Point().Base(), "X": suite.Point().Mul(x, nil)}
+	reps := make([]Predicate, bits.UintSize+1)
+	for i := range reps {
+		reps[i] = Rep("X", "x", "B")
+	}
+	pred := Threshold(1, reps...)
+	_, err := HashProve(suite, "TEST", pred.Prover(suite, sval, pval, map[Predicate]int{pred: 1}))
+	require.Error(t, err)
+	pred = Threshold(1, reps[:bits.UintSize]...)
+	_, err = HashProve(suite, "TEST", pred.Prover(suite, sval, pval, map[Predicate]int{pred: 1}))
+	require.NoError(t, err)
+}
+
+func TestThresholdInterpolate(t *testing.T) {
+	suite := testSuite
+	rand := suite.RandomStream()
+	prf := &proof{s: suite}
+	n, k := 9, 4
+	poly := share.NewPriPoly(suite, uint32(n-k+1), nil, rand)
+	// the sub-challenges are the values at 1,...,n of the polynomial,
+	// which the share of index i-1 holds
+	ci := make([]kyber.Scalar, n)
+	for i := range n - k {
+		ci[2*i%n] = poly.Eval(uint32(2 * i % n)).V
+	}
+	tp := &thresholdPred{k: k, sub: make([]Predicate, n)}
+	require.NoError(t, tp.interpolate(prf, poly.Secret(), ci))
+	for i := range ci {
+		require.True(t, ci[i].Equal(poly.Eval(uint32(i)).V), "sub-challenge %d", i)
+	}
+}
+
+func Example_threshold() {
+	pred := Threshold(2, Rep("X", "x", "B"), Rep("Y", "y", "B"),
+		Or(Rep("Z", "z", "B"), Rep("W", "w", "B")))
+	fmt.Println(pred.String())
+	// Output: threshold(2, X=x*B, Y=y*B, Z=z*B || W=w*B)
+}
@@ -0,0 +1,108 @@
+package proof
+
+import (
+	"bytes"
+
+	"go.dedis.ch/kyber/v4/proof/transcript"
+)
+
+// Transcript-based noninteractive Sigma-protocol prover context
+type transcriptProver struct {
+	suite Suite
+	t     *transcript.Transcript
+	proof bytes.Buffer
+	msg   bytes.Buffer
+}
+
+func (c *transcriptProver) Put(message any) error {
+	return c.suite.Write(&c.msg, message)
+}
+
+func (c *transcriptProver) consumeMsg() {
+	if c.msg.Len() > 0 {
+		// Absorb the message into the transcript and append it to the proof
+		c.t.AppendMessage("message", c.msg.Bytes())
+		c.proof.Write(c.msg.Bytes())
+		c.msg.Reset()
+	}
+}
+
+// Get a challenge that depends on the transcript and every bit in the proof
+// so far.
+func (c *transcriptProver) PubRand(data ...any) error {
+	c.consumeMsg()
+	return c.suite.Read(c.t.ChallengeStream("challenge"), data...)
+}
+
+// Get private randomness, derived from the transcript so far and from the
+// random stream of the suite.
+func (c *transcriptProver) PriRand(data ...any) error {
+	stream := c.t.WitnessStream("prirand", nil, c.suite.RandomStream())
+	return c.suite.Read(stream, data...)
+}
+
+// Obtain the encoded proof once the Sigma protocol is complete.
+func (c *transcriptProver) Proof() []byte {
+	c.consumeMsg()
+	return c.proof.Bytes()
+}
+
+// Transcript-based noninteractive Sigma-protocol verifier context
+type transcriptVerifier struct {
+	suite Suite
+	t     *transcript.Transcript
+	proof *bytes.Reader // Reader of the proof
+	prbuf []byte        // Bytes of the proof not absorbed yet
+}
+
+func (c *transcriptVerifier) consumeMsg() {
+	l := len(c.prbuf) - c.proof.Len() // How many bytes read?
+	if l > 0 {
+		c.t.AppendMessage("message", c.prbuf[:l])
+		c.prbuf = c.prbuf[l:]
+	}
+}
+
+// Read structured data from the proof
+func (c *transcriptVerifier) Get(message any) error {
+	return c.suite.Read(c.proof, message)
+}
+
+// Get a challenge that depends on the transcript and every bit in the proof
+// so far.
+func (c *transcriptVerifier) PubRand(data ...any) error {
+	c.consumeMsg()
+	return c.suite.Read(c.t.ChallengeStream("challenge"), data...)
+}
+
+// TranscriptProve runs a given Sigma-protocol prover with a ProverContext
+// that produces a non-interactive proof via the Fiat-Shamir heuristic, like
+// HashProve, but with the challenges drawn from the transcript t.
+//
+// The messages of the prover are absorbed into t, so that t can be used
+// to bind the proof to the rest of a protocol: everything appended to t
+// before the call is committed to by the proof, and the challenges drawn
+// from t after the call depend on the proof. The private randomness of the
+// prover is derived from a fork of t and from the random stream of the suite.
+func TranscriptProve(suite Suite, t *transcript.Transcript, prover Prover) ([]byte, error) {
+	ctx := &transcriptProver{suite: suite, t: t}
+	if e := (func(ProverContext) error)(prover)(ctx); e != nil {
+		return nil, e
+	}
+	return ctx.Proof(), nil
+}
+
+// TranscriptVerify verifies a proof generated with TranscriptProve. The
+// transcript t must be in the same state as the one given to TranscriptProve.
+// Returns nil if the proof checks out, or an error on any failure.
+func TranscriptVerify(suite Suite, t *transcript.Transcript, verifier Verifier, proof []byte) error {
+	ctx := &transcriptVerifier{
+		suite: suite,
+		t:     t,
+		proof: bytes.NewReader(proof),
+		prbuf: proof,
+	}
+	err := (func(VerifierContext) error)(verifier)(ctx)
+	ctx.consumeMsg()
+	return err
+}
@@ -0,0 +1,150 @@
+// Package transcript implements Fiat-Shamir transcripts in the style of
+// Merlin, on top of the XOF of a kyber suite.
+//
+// A Transcript absorbs the messages of a protocol, each of them along with a
+// label, and derives the challenges of the verifier from everything absorbed
+// before them. Labels and lengths are absorbed with every message, so that
+// distinct sequences of messages never produce the same challenges, and the
+// label given to New separates the protocols using the same suite.
+//
+// The nonces of a prover can be derived from a fork of the transcript, with
+// WitnessStream, which mixes the secrets of the prover and fresh randomness
+// into a copy of the transcript: the nonces stay unpredictable even with a
+// weak source of randomness, as long as the secrets are.
+//
+// Transcripts also drive the generic Sigma-protocols of the proof package,
+// through proof.TranscriptProve and proof.TranscriptVerify.
+package transcript
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+const (
+	protocolLabel = "kyber-transcript-v1"
+	seedSize      = 64
+)
+
+// Transcript is the Fiat-Shamir transcript of a protocol. It isn't safe for
+// concurrent use.
+type Transcript struct {
+	factory kyber.XOFFactory
+	xof     kyber.XOF
+	// reading is set once output has been read from the XOF, which must be
+	// reseeded before absorbing more data
+	reading bool
+}
+
+// New returns a transcript for the protocol with the given label, using the
+// XOF of the suite.
+func New(suite kyber.XOFFactory, label string) *Transcript {
+	t := &Transcript{
+		factory: suite,
+		xof:     suite.XOF([]byte(protocolLabel)),
+	}
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// AppendMessage absorbs the message with its label.
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	if t.reading {
+		t.xof.Reseed()
+		t.reading = false
+	}
+	var buf []byte
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(label)))
+	buf = append(buf, label...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(msg)))
+	buf = append(buf, msg...)
+	_, _ = t.xof.Write(buf)
+}
+
+// AppendUint64 absorbs v, in big-endian, with its label.
+func (t *Transcript) AppendUint64(label string, v uint64) {
+	t.AppendMessage(label, binary.BigEndian.AppendUint64(nil, v))
+}
+
+// AppendPoint absorbs the binary encoding of p with its label.
+func (t *Transcript) AppendPoint(label string, p kyber.Point) error {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	t.AppendMessage(label, buf)
+	return nil
+}
+
+// AppendScalar absorbs the binary encoding of s with its label.
+func (t *Transcript) AppendScalar(label string, s kyber.Scalar) error {
+	buf, err := s.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	t.AppendMessage(label, buf)
+	return nil
+}
+
+// ChallengeBytes fills dst with the challenge of the given label, which
+// depends on everything absorbed so far and on the length of dst. The
+// challenge is itself absorbed into the transcript, so that two successive
+// challenges are different.
+func (t *Transcript) ChallengeBytes(label string, dst []byte) {
+	t.AppendUint64(label, uint64(len(dst)))
+	_, err := t.xof.Read(dst)
+	if err != nil {
+		panic("transcript: " + err.Error())
+	}
+	t.reading = true
+}
+
+// ChallengeScalar returns the challenge scalar of the group g with the given
+// label.
+func (t *Transcript) ChallengeScalar(label string, g kyber.Group) kyber.Scalar {
+	return g.Scalar().Pick(t.ChallengeStream(label))
+}
+
+// ChallengeStream returns an unbounded stream of challenge bytes with the
+// given label. The stream is independent from the transcript, which can be
+// appended to while the stream is in use.
+func (t *Transcript) ChallengeStream(label string) kyber.XOF {
+	seed := make([]byte, seedSize)
+	t.ChallengeBytes(label, seed)
+	return t.factory.XOF(seed)
+}
+
+// Clone returns a copy of the transcript in its current state.
+func (t *Transcript) Clone() *Transcript {
+	return &Transcript{
+		factory: t.factory,
+		xof:     t.xof.Clone(),
+		reading: t.reading,
+	}
+}
+
+// Fork returns a copy of the transcript in its current state, separated from
+// it and from the other forks by the label. The transcript itself is
+// unchanged.
+func (t *Transcript) Fork(label string) *Transcript {
+	f := t.Clone()
+	f.AppendMessage("fork", []byte(label))
+	return f
+}
+
+// WitnessStream returns a stream of private randomness for the nonces of a
+// prover, derived from a fork of the transcript into which the witness and
+// bytes read from rand are absorbed. The transcript itself is unchanged.
+//
+// The witness is the encoding of the secrets of the prover, or nil when the
+// caller doesn't have them: the stream then only relies on rand.
+func (t *Transcript) WitnessStream(label string, witness []byte, rand cipher.Stream) kyber.XOF {
+	f := t.Fork("witness")
+	f.AppendMessage(label, witness)
+	random := make([]byte, seedSize)
+	rand.XORKeyStream(random, random)
+	f.AppendMessage("rng", random)
+	return f.ChallengeStream("nonce")
+}
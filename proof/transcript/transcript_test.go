@@ -0,0 +1,105 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+)
+
+var suite = edwards25519.NewBlakeSHA256Ed25519()
+
+func challenge(t *Transcript) []byte {
+	c := make([]byte, 32)
+	t.ChallengeBytes("c", c)
+	return c
+}
+
+func TestTranscript(t *testing.T) {
+	run := func(label string, msgs ...string) []byte {
+		tr := New(suite, label)
+		for _, m := range msgs {
+			tr.AppendMessage("m", []byte(m))
+		}
+		return challenge(tr)
+	}
+	require.Equal(t, run("proto", "a", "b"), run("proto", "a", "b"))
+	require.NotEqual(t, run("proto", "a", "b"), run("other", "a", "b"))
+	require.NotEqual(t, run("proto", "a", "b"), run("proto", "b", "a"))
+	require.NotEqual(t, run("proto", "ab"), run("proto", "a", "b"))
+	require.NotEqual(t, run("proto", "ab", ""), run("proto", "a", "b"))
+
+	// the label is part of the message
+	t1, t2 := New(suite, "proto"), New(suite, "proto")
+	t1.AppendMessage("ab", []byte("c"))
+	t2.AppendMessage("a", []byte("bc"))
+	require.NotEqual(t, challenge(t1), challenge(t2))
+
+	// successive challenges differ, and depend on their length
+	tr := New(suite, "proto")
+	c1, c2 := challenge(tr), challenge(tr)
+	require.NotEqual(t, c1, c2)
+	t1, t2 = New(suite, "proto"), New(suite, "proto")
+	short := make([]byte, 16)
+	t1.ChallengeBytes("c", short)
+	require.NotEqual(t, short, challenge(t2)[:16])
+
+	// appending after a challenge
+	t1, t2 = New(suite, "proto"), New(suite, "proto")
+	challenge(t1)
+	challenge(t2)
+	t1.AppendUint64("n", 1)
+	t2.AppendUint64("n", 2)
+	require.NotEqual(t, challenge(t1), challenge(t2))
+}
+
+func TestTranscriptGroupElements(t *testing.T) {
+	x := suite.Scalar().Pick(suite.RandomStream())
+	X := suite.Point().Mul(x, nil)
+	t1, t2 := New(suite, "proto"), New(suite, "proto")
+	require.NoError(t, t1.AppendPoint("X", X))
+	require.NoError(t, t1.AppendScalar("x", x))
+	buf, _ := X.MarshalBinary()
+	t2.AppendMessage("X", buf)
+	buf, _ = x.MarshalBinary()
+	t2.AppendMessage("x", buf)
+	c := t1.ChallengeScalar("c", suite)
+	require.True(t, c.Equal(t2.ChallengeScalar("c", suite)))
+	require.False(t, c.Equal(t1.ChallengeScalar("c", suite)))
+}
+
+func TestTranscriptFork(t *testing.T) {
+	tr := New(suite, "proto")
+	tr.AppendMessage("m", []byte("a"))
+	clone := tr.Clone()
+	require.Equal(t, challenge(tr), challenge(clone))
+	require.Equal(t, challenge(tr), challenge(clone))
+
+	f1, f2 := tr.Fork("1"), tr.Fork("2")
+	require.NotEqual(t, challenge(f1), challenge(f2))
+	require.Equal(t, challenge(tr.Fork("1")), challenge(tr.Fork("1")))
+
+	// the forks don't change the transcript
+	clone = tr.Clone()
+	tr.Fork("1").AppendMessage("m", []byte("b"))
+	require.Equal(t, challenge(tr), challenge(clone))
+}
+
+func TestWitnessStream(t *testing.T) {
+	tr := New(suite, "proto")
+	nonce := func(tr *Transcript, witness string, seed string) []byte {
+		n := make([]byte, 32)
+		tr.WitnessStream("x", []byte(witness), blake2xb.New([]byte(seed))).XORKeyStream(n, n)
+		return n
+	}
+	n := nonce(tr, "secret", "seed")
+	require.Equal(t, n, nonce(tr, "secret", "seed"))
+	require.NotEqual(t, n, nonce(tr, "other", "seed"))
+	require.NotEqual(t, n, nonce(tr, "secret", "other"))
+	other := New(suite, "proto")
+	other.AppendMessage("m", nil)
+	require.NotEqual(t, n, nonce(other, "secret", "seed"))
+	// the witness stream doesn't change the transcript
+	require.Equal(t, challenge(tr), challenge(New(suite, "proto")))
+}
@@ -0,0 +1,53 @@
+package proof
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+)
+
+func TestTranscriptProve(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	x := suite.Scalar().Pick(suite.RandomStream())
+	y := suite.Scalar().Pick(suite.RandomStream())
+	B := suite.Point().Base()
+	X := suite.Point().Mul(x, nil)
+	Y := suite.Point().Mul(y, X)
+
+	// prove (X=x*B && Y=y*X) || B=y*X with the first branch
+	pred := Or(And(Rep("X", "x", "B"), Rep("Y", "y", "X")), Rep("B", "y", "X"))
+	choice := map[Predicate]int{pred: 0}
+	sval := map[string]kyber.Scalar{"x": x, "y": y}
+	pval := map[string]kyber.Point{"B": B, "X": X, "Y": Y}
+
+	newTranscript := func(context string) *transcript.Transcript {
+		tr := transcript.New(suite, "test")
+		tr.AppendMessage("context", []byte(context))
+		return tr
+	}
+	prover := newTranscript("ctx")
+	proof, err := TranscriptProve(suite, prover, pred.Prover(suite, sval, pval, choice))
+	require.NoError(t, err)
+
+	verifier := newTranscript("ctx")
+	require.NoError(t, TranscriptVerify(suite, verifier, pred.Verifier(suite, pval), proof))
+	// the transcripts are in the same state after the proof
+	require.True(t, prover.ChallengeScalar("next", suite).Equal(verifier.ChallengeScalar("next", suite)))
+
+	// the proof is bound to the transcript
+	err = TranscriptVerify(suite, newTranscript("other"), pred.Verifier(suite, pval), proof)
+	require.ErrorContains(t, err, "invalid proof")
+	err = HashVerify(suite, "ctx", pred.Verifier(suite, pval), proof)
+	require.Error(t, err)
+	proof[0] ^= 1
+	err = TranscriptVerify(suite, newTranscript("ctx"), pred.Verifier(suite, pval), proof)
+	require.Error(t, err)
+
+	// proofs aren't deterministic
+	proof2, err := TranscriptProve(suite, newTranscript("ctx"), pred.Prover(suite, sval, pval, choice))
+	require.NoError(t, err)
+	require.NotEqual(t, proof, proof2)
+}
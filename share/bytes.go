@@ -0,0 +1,170 @@
+package share
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/hybrid"
+	"go.dedis.ch/kyber/v4/internal/wire"
+)
+
+// Byte strings of any length are shared with a hybrid scheme: the secret is
+// encrypted with AES-256-GCM under a key derived from a random scalar, and
+// only the scalar is shared with Shamir's scheme. Every share carries the
+// ciphertext, so that any t of them are enough to recover the secret, and the
+// authentication of the ciphertext detects wrong or tampered shares instead of
+// returning garbage.
+
+// byteShareInfo is the HKDF info and the prefix of the associated data of the
+// encryption of a shared byte string.
+var byteShareInfo = []byte("kyber-share-bytes-v1")
+
+// ErrInvalidByteShares is returned by CombineBytes when the shares don't
+// decrypt the secret, because one of them is wrong or has been tampered with.
+var ErrInvalidByteShares = errors.New("share: invalid shares of byte string")
+
+// ByteShare is a share of a byte string.
+type ByteShare struct {
+	I          uint32       // Index of the share
+	T          uint32       // Threshold needed to recover the secret
+	V          kyber.Scalar // Share of the encryption key
+	Ciphertext []byte       // Encrypted secret, the same in all the shares
+}
+
+// SplitBytes splits the secret into n shares such that any t of them allow to
+// recover it with CombineBytes.
+func SplitBytes(g kyber.Group, secret []byte, t, n uint32, rand cipher.Stream) ([]*ByteShare, error) {
+	if t == 0 || t > n {
+		return nil, fmt.Errorf("share: invalid threshold %d for %d shares", t, n)
+	}
+	key := g.Scalar().Pick(rand)
+	aead, err := byteShareAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := hybrid.Seal(aead, secret, byteShareAD(t))
+
+	priShares := NewPriPoly(g, t, key, rand).Shares(n)
+	shares := make([]*ByteShare, n)
+	for i, s := range priShares {
+		shares[i] = &ByteShare{
+			I:          s.I,
+			T:          t,
+			V:          s.V,
+			Ciphertext: ciphertext,
+		}
+	}
+	return shares, nil
+}
+
+// CombineBytes recovers the secret from the shares given by SplitBytes. Nil
+// shares are ignored, but there must be at least the threshold of them. When
+// there are more, all of them are checked to lie on the same polynomial, so
+// that a bad share is detected even if the others decrypt the secret. The
+// error doesn't tell which share is bad: any of them may be, including those
+// the polynomial is interpolated from.
+func CombineBytes(g kyber.Group, shares []*ByteShare) ([]byte, error) {
+	var first *ByteShare
+	priShares := make([]*PriShare, 0, len(shares))
+	seen := make(map[uint32]bool, len(shares))
+	for _, s := range shares {
+		if s == nil || s.V == nil {
+			continue
+		}
+		if first == nil {
+			first = s
+		}
+		if s.T != first.T || !bytes.Equal(s.Ciphertext, first.Ciphertext) {
+			return nil, fmt.Errorf("share: share %d is from another secret", s.I)
+		}
+		if seen[s.I] {
+			return nil, fmt.Errorf("share: duplicate share %d", s.I)
+		}
+		seen[s.I] = true
+		priShares = append(priShares, &PriShare{I: s.I, V: s.V})
+	}
+	if first == nil || uint32(len(priShares)) < first.T {
+		return nil, errors.New("share: not enough shares to recover byte string")
+	}
+	t := first.T
+
+	indices := sortedIndices(seen)
+	l, err := NewLagrange(g, indices[:t])
+	if err != nil {
+		return nil, err
+	}
+	key, err := l.Interpolate(priShares)
+	if err != nil {
+		return nil, err
+	}
+	if len(priShares) > int(t) {
+		poly, err := RecoverPriPoly(g, priShares, t, indices[len(indices)-1]+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range priShares {
+			if !poly.Eval(s.I).V.Equal(s.V) {
+				return nil, fmt.Errorf("%w: inconsistent shares", ErrInvalidByteShares)
+			}
+		}
+	}
+
+	aead, err := byteShareAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := hybrid.Open(aead, first.Ciphertext, byteShareAD(t))
+	if err != nil {
+		return nil, ErrInvalidByteShares
+	}
+	return secret, nil
+}
+
+// Marshal returns the versioned binary encoding of the share.
+func (s *ByteShare) Marshal() ([]byte, error) {
+	enc := wire.NewEncoder(wire.TagShareByteShare)
+	enc.Uint32(s.I)
+	enc.Uint32(s.T)
+	enc.Scalar(s.V)
+	enc.Bytes(s.Ciphertext)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a share of the given group encoded by Marshal.
+func (s *ByteShare) Unmarshal(data []byte, g kyber.Group) error {
+	dec := wire.NewDecoder(data, wire.TagShareByteShare)
+	i := dec.Uint32()
+	t := dec.Uint32()
+	v := dec.Scalar(g)
+	ciphertext := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	if t == 0 {
+		return errors.New("share: invalid threshold 0")
+	}
+	s.I = i
+	s.T = t
+	s.V = v
+	s.Ciphertext = ciphertext
+	return nil
+}
+
+// byteShareAEAD returns the AES-256-GCM instance keyed by the shared scalar.
+func byteShareAEAD(key kyber.Scalar) (cipher.AEAD, error) {
+	buf, err := key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return hybrid.NewAEAD(buf, nil, byteShareInfo)
+}
+
+// byteShareAD returns the associated data of the encryption, which binds the
+// threshold to the ciphertext.
+func byteShareAD(t uint32) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte{}, byteShareInfo...), t)
+}
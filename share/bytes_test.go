@@ -0,0 +1,128 @@
+package share
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/internal/wire"
+)
+
+func TestSplitBytes(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := uint32(7)
+	t := uint32(4)
+	secret := []byte("correct horse battery staple, and a rather long API key")
+
+	shares, err := SplitBytes(g, secret, t, n, g.RandomStream())
+	require.NoError(test, err)
+	require.Len(test, shares, int(n))
+
+	// any t shares recover the secret
+	recovered, err := CombineBytes(g, []*ByteShare{shares[6], shares[1], nil, shares[4], shares[2]})
+	require.NoError(test, err)
+	require.Equal(test, secret, recovered)
+	recovered, err = CombineBytes(g, shares)
+	require.NoError(test, err)
+	require.Equal(test, secret, recovered)
+
+	// but not less
+	_, err = CombineBytes(g, shares[:t-1])
+	require.Error(test, err)
+	_, err = CombineBytes(g, nil)
+	require.Error(test, err)
+
+	// through the binary encoding
+	decoded := make([]*ByteShare, t)
+	for i := range decoded {
+		buff, err := shares[i].Marshal()
+		require.NoError(test, err)
+		decoded[i] = new(ByteShare)
+		require.NoError(test, decoded[i].Unmarshal(buff, g))
+	}
+	recovered, err = CombineBytes(g, decoded)
+	require.NoError(test, err)
+	require.Equal(test, secret, recovered)
+
+	// empty secret
+	shares, err = SplitBytes(g, nil, 1, 1, g.RandomStream())
+	require.NoError(test, err)
+	recovered, err = CombineBytes(g, shares)
+	require.NoError(test, err)
+	require.Empty(test, recovered)
+
+	_, err = SplitBytes(g, secret, n+1, n, g.RandomStream())
+	require.Error(test, err)
+	_, err = SplitBytes(g, secret, 0, n, g.RandomStream())
+	require.Error(test, err)
+}
+
+func TestCombineBytesTampered(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := uint32(5)
+	t := uint32(3)
+	secret := []byte("secret")
+
+	shares, err := SplitBytes(g, secret, t, n, g.RandomStream())
+	require.NoError(test, err)
+	good := shares[1].V
+	shares[1].V = g.Scalar().Pick(g.RandomStream())
+
+	// with exactly t shares, the tampering is detected
+	_, err = CombineBytes(g, shares[:t])
+	require.ErrorIs(test, err, ErrInvalidByteShares)
+
+	// with more, the inconsistency is detected
+	_, err = CombineBytes(g, shares)
+	require.ErrorIs(test, err, ErrInvalidByteShares)
+	shares[1].V = good
+
+	// also when the tampered share has the lowest index, and the error
+	// doesn't blame the first honest share that disagrees with it
+	good = shares[0].V
+	shares[0].V = g.Scalar().Pick(g.RandomStream())
+	_, err = CombineBytes(g, shares)
+	require.ErrorIs(test, err, ErrInvalidByteShares)
+	require.NotContains(test, err.Error(), "share 3")
+	shares[0].V = good
+
+	// tampered ciphertext and threshold
+	other := *shares[0]
+	other.Ciphertext = append([]byte{}, other.Ciphertext...)
+	other.Ciphertext[0] ^= 1
+	_, err = CombineBytes(g, []*ByteShare{&other, shares[1], shares[2]})
+	require.Error(test, err)
+	tampered := make([]*ByteShare, t)
+	for i := range tampered {
+		s := *shares[i]
+		s.Ciphertext = other.Ciphertext
+		tampered[i] = &s
+	}
+	_, err = CombineBytes(g, tampered)
+	require.ErrorIs(test, err, ErrInvalidByteShares)
+	for _, s := range tampered {
+		s.Ciphertext = shares[0].Ciphertext
+		s.T = t - 1
+	}
+	_, err = CombineBytes(g, tampered)
+	require.ErrorIs(test, err, ErrInvalidByteShares)
+
+	// shares of another secret
+	others, err := SplitBytes(g, secret, t, n, g.RandomStream())
+	require.NoError(test, err)
+	_, err = CombineBytes(g, []*ByteShare{shares[0], shares[1], others[2]})
+	require.Error(test, err)
+
+	// duplicate share
+	_, err = CombineBytes(g, []*ByteShare{shares[0], shares[1], shares[1]})
+	require.Error(test, err)
+
+	// invalid encodings
+	buff, err := shares[0].Marshal()
+	require.NoError(test, err)
+	s := new(ByteShare)
+	require.ErrorIs(test, s.Unmarshal(buff[:10], g), wire.ErrShort)
+	require.ErrorIs(test, s.Unmarshal(append(buff, 0), g), wire.ErrTrailing)
+	buff[1] = wire.TagVSSResponse
+	require.ErrorIs(test, s.Unmarshal(buff, g), wire.ErrTag)
+}
@@ -211,6 +211,8 @@ type DistKeyGenerator struct {
 	oldPresent bool
 	// public polynomial of the old group
 	olddpub *share.PubPoly
+	// indicates whether we are refreshing the shares of an existing group
+	isRefresh bool
 }
 
 // NewDistKeyHandler takes a Config and returns a DistKeyGenerator that is able
@@ -310,24 +312,7 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 		canReceive = true
 		oldThreshold = uint32(len(c.PublicCoeffs))
 	}
-	var statuses *StatusMatrix
-	if c.FastSync {
-		// in fast sync mode, we set every shares to complaint by default and
-		// expect everyone to send success for correct shares
-		statuses = NewStatusMatrix(c.OldNodes, c.NewNodes, Complaint)
-	} else {
-		// in normal mode, every shares of other nodes is expected to be
-		// correct, unless honest nodes send a complaint
-		statuses = NewStatusMatrix(c.OldNodes, c.NewNodes, Success)
-		if canReceive {
-			// we set the statuses of the shares we expect to receive as complaint
-			// by default, so if we miss one share or there's an invalid share,
-			// it'll generate a complaint
-			for _, node := range c.OldNodes {
-				statuses.Set(node.Index, nidx, Complaint)
-			}
-		}
-	}
+	statuses := initialStatuses(c, canReceive, nidx)
 	dkg := &DistKeyGenerator{
 		state:       InitPhase,
 		suite:       c.Suite,
@@ -353,6 +338,28 @@ func NewDistKeyHandler(c *Config) (*DistKeyGenerator, error) {
 	return dkg, err
 }
 
+// initialStatuses returns the status matrix a node starts the protocol with,
+// depending on the mode chosen in the config.
+func initialStatuses(c *Config, canReceive bool, nidx Index) *StatusMatrix {
+	if c.FastSync {
+		// in fast sync mode, we set every shares to complaint by default and
+		// expect everyone to send success for correct shares
+		return NewStatusMatrix(c.OldNodes, c.NewNodes, Complaint)
+	}
+	// in normal mode, every shares of other nodes is expected to be
+	// correct, unless honest nodes send a complaint
+	statuses := NewStatusMatrix(c.OldNodes, c.NewNodes, Success)
+	if canReceive {
+		// we set the statuses of the shares we expect to receive as complaint
+		// by default, so if we miss one share or there's an invalid share,
+		// it'll generate a complaint
+		for _, node := range c.OldNodes {
+			statuses.Set(node.Index, nidx, Complaint)
+		}
+	}
+	return statuses
+}
+
 func (d *DistKeyGenerator) Deals() (*DealBundle, error) {
 	if !d.canIssue {
 		return nil, errors.New("new members can't issue deals")
@@ -453,6 +460,13 @@ func (d *DistKeyGenerator) ProcessDeals(bundles []*DealBundle) (*ResponseBundle,
 			continue
 		}
 		pubPoly := share.NewPubPoly(d.c.Suite, d.c.Suite.Point().Base(), bundle.Public)
+		if d.isRefresh && !pubPoly.Commit().Equal(d.c.Suite.Point().Null()) {
+			// a refresh polynomial must share zero, otherwise the dealer is
+			// trying to change the distributed secret
+			d.evicted = append(d.evicted, bundle.DealerIndex)
+			d.c.Error("Refresh deal with non-zero secret")
+			continue
+		}
 		if seenIndex[bundle.DealerIndex] {
 			// already saw a bundle from the same dealer - clear sign of
 			// cheating so we evict him from the list
@@ -900,6 +914,10 @@ func (d *DistKeyGenerator) computeResult() (*Result, error) {
 		// instead of adding, in this case, we interpolate all shares
 		return d.computeResharingResult()
 	}
+	if d.isRefresh {
+		// the zero-shares are added on top of the current share
+		return d.computeRefreshResult()
+	}
 
 	return d.computeDKGResult()
 }
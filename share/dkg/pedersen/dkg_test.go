@@ -146,6 +146,14 @@ func RunDKG(t *testing.T, tns []*TestNode, conf Config,
 	dm MapDeal, rm MapResponse, jm MapJustif) []*Result {
 
 	SetupNodes(tns, &conf)
+	return RunSteps(t, tns, dm, rm, jm)
+}
+
+// RunSteps runs all the phases of the protocol with the generators already
+// setup in the test nodes.
+func RunSteps(t *testing.T, tns []*TestNode,
+	dm MapDeal, rm MapResponse, jm MapJustif) []*Result {
+
 	var deals []*DealBundle
 	for _, node := range tns {
 		d, err := node.dkg.Deals()
@@ -84,6 +84,20 @@ func NewProtocol(c *Config, b Board, phaser Phaser, skipVerification bool) (*Pro
 	if err != nil {
 		return nil, err
 	}
+	return newProtocol(dkg, b, phaser, skipVerification), nil
+}
+
+// NewRefreshProtocol is similar to NewProtocol but runs a proactive refresh of
+// the shares of the group instead, as described in NewRefreshHandler.
+func NewRefreshProtocol(c *Config, b Board, phaser Phaser, skipVerification bool) (*Protocol, error) {
+	dkg, err := NewRefreshHandler(c)
+	if err != nil {
+		return nil, err
+	}
+	return newProtocol(dkg, b, phaser, skipVerification), nil
+}
+
+func newProtocol(dkg *DistKeyGenerator, b Board, phaser Phaser, skipVerification bool) *Protocol {
 	p := &Protocol{
 		board:     b,
 		phaser:    phaser,
@@ -93,7 +107,7 @@ func NewProtocol(c *Config, b Board, phaser Phaser, skipVerification bool) (*Pro
 		skipVerif: skipVerification,
 	}
 	go p.Start()
-	return p, nil
+	return p
 }
 
 func (p *Protocol) Info(keyvals ...any) {
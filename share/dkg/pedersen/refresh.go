@@ -0,0 +1,140 @@
+package dkg
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// NewRefreshHandler takes a Config and returns a DistKeyGenerator that is able
+// to drive a proactive refresh of an existing distributed key. A refresh keeps
+// the same group, the same threshold and the same public key, but gives new
+// shares to every participant: each dealer shares a random polynomial whose
+// constant term is zero and every share holder adds the zero-shares it
+// received to its current share. Shares obtained before the refresh can not
+// be combined with shares obtained after it.
+//
+// One must fill the following fields: Suite, Longterm, NewNodes, Share, Nonce
+// and Auth. OldNodes can be left empty, and must be equal to NewNodes
+// otherwise. Threshold can be left empty, and must be equal to the threshold
+// of the current distributed key otherwise.
+func NewRefreshHandler(c *Config) (*DistKeyGenerator, error) {
+	if len(c.NewNodes) == 0 {
+		return nil, errors.New("dkg: can't run with empty node list")
+	}
+	if len(c.Nonce) != NonceLength {
+		return nil, errors.New("dkg: invalid nonce length")
+	}
+	if c.Auth == nil {
+		return nil, errors.New("dkg: need authentication scheme")
+	}
+	if c.Share == nil || c.Share.Share == nil || len(c.Share.Commits) == 0 {
+		return nil, errors.New("dkg: refresh needs the current distributed key share")
+	}
+	if c.OldNodes != nil && !sameNodes(c.OldNodes, c.NewNodes) {
+		return nil, errors.New("dkg: refresh can not change the list of nodes")
+	}
+
+	threshold := uint32(len(c.Share.Commits))
+	if c.Threshold == 0 {
+		c.Threshold = threshold
+	} else if c.Threshold != threshold {
+		return nil, fmt.Errorf("dkg: refresh threshold %d does not match current threshold %d",
+			c.Threshold, threshold)
+	}
+
+	pub := c.Suite.Point().Mul(c.Longterm, nil)
+	nidx, present := findPub(c.NewNodes, pub)
+	if !present {
+		return nil, errors.New("dkg: public key not found in list of nodes")
+	}
+	if c.Share.Share.I != nidx {
+		return nil, fmt.Errorf("dkg: share index %d does not match node index %d", c.Share.Share.I, nidx)
+	}
+
+	// dealers and share holders are the same nodes
+	c.OldNodes = c.NewNodes
+	c.OldThreshold = threshold
+	if err := c.CheckForDuplicates(); err != nil {
+		return nil, err
+	}
+
+	olddpub := share.NewPubPoly(c.Suite, c.Suite.Point().Base(), c.Share.Commits)
+	if !olddpub.Check(c.Share.Share) {
+		return nil, errors.New("dkg: current share does not correspond to its public polynomial")
+	}
+
+	dpriv := share.NewPriPoly(c.Suite, threshold, c.Suite.Scalar().Zero(), c.Suite.RandomStream())
+	dpub := dpriv.Commit(c.Suite.Point().Base())
+	return &DistKeyGenerator{
+		state:       InitPhase,
+		suite:       c.Suite,
+		long:        c.Longterm,
+		pub:         pub,
+		canReceive:  true,
+		canIssue:    true,
+		isRefresh:   true,
+		dpriv:       dpriv,
+		dpub:        dpub,
+		olddpub:     olddpub,
+		oidx:        nidx,
+		nidx:        nidx,
+		c:           c,
+		oldT:        threshold,
+		newT:        threshold,
+		newPresent:  true,
+		oldPresent:  true,
+		statuses:    initialStatuses(c, true, nidx),
+		validShares: make(map[uint32]kyber.Scalar),
+		allPublics:  make(map[uint32]*share.PubPoly),
+	}, nil
+}
+
+// computeRefreshResult adds the zero-shares received from the qualified
+// dealers to the current share, and their public polynomials to the current
+// public polynomial.
+func (d *DistKeyGenerator) computeRefreshResult() (*Result, error) {
+	res, err := d.computeDKGResult()
+	if err != nil {
+		return nil, err
+	}
+
+	zeroPub := share.NewPubPoly(d.suite, d.suite.Point().Base(), res.Key.Commits)
+	finalPub, err := d.olddpub.Add(zeroPub)
+	if err != nil {
+		return nil, err
+	}
+	if !finalPub.Commit().Equal(d.olddpub.Commit()) {
+		return nil, errors.New("dkg: refresh changed the distributed public key")
+	}
+
+	finalShare := &share.PriShare{
+		I: d.nidx,
+		V: d.suite.Scalar().Add(d.c.Share.Share.V, res.Key.Share.V),
+	}
+	if !finalPub.Check(finalShare) {
+		return nil, errors.New("dkg: refreshed share does not correspond to public polynomial")
+	}
+
+	_, commits := finalPub.Info()
+	res.Key = &DistKeyShare{
+		Commits: commits,
+		Share:   finalShare,
+	}
+	return res, nil
+}
+
+func sameNodes(a, b []Node) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, n := range a {
+		pub, ok := findIndex(b, n.Index)
+		if !ok || !pub.Equal(n.Public) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,263 @@
+package dkg
+
+import (
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+	"go.dedis.ch/kyber/v4/util/random"
+)
+
+func SetupRefreshNodes(nodes []*TestNode, c *Config) {
+	nonce := GetNonce()
+	for _, n := range nodes {
+		c2 := *c
+		c2.Longterm = n.Private
+		c2.Nonce = nonce
+		c2.Share = n.res.Key
+		dkg, err := NewRefreshHandler(&c2)
+		if err != nil {
+			panic(err)
+		}
+		n.dkg = dkg
+	}
+}
+
+func sharesOf(results []*Result) []*share.PriShare {
+	shares := make([]*share.PriShare, len(results))
+	for i, res := range results {
+		shares[i] = res.Key.PriShare()
+	}
+	return shares
+}
+
+func TestRefresh(t *testing.T) {
+	n := uint32(5)
+	thr := uint32(3)
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  list,
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	results := RunDKG(t, tns, conf, nil, nil, nil)
+	testResults(t, suite, thr, n, results)
+	for i, tn := range tns {
+		tn.res = results[i]
+	}
+	secret, err := share.RecoverSecret(suite, sharesOf(results), thr, n)
+	require.NoError(t, err)
+
+	refreshConf := Config{
+		Suite:    suite,
+		NewNodes: list,
+		Auth:     schnorr.NewScheme(suite),
+	}
+	SetupRefreshNodes(tns, &refreshConf)
+	newResults := RunSteps(t, tns, nil, nil, nil)
+	require.Len(t, newResults, int(n))
+	testResults(t, suite, thr, n, newResults)
+
+	// same public key and same secret, but different public polynomial and
+	// different shares
+	require.True(t, results[0].Key.Public().Equal(newResults[0].Key.Public()))
+	require.False(t, results[0].PublicEqual(newResults[0]))
+	newSecret, err := share.RecoverSecret(suite, sharesOf(newResults), thr, n)
+	require.NoError(t, err)
+	require.True(t, secret.Equal(newSecret))
+	for i := range results {
+		require.Equal(t, results[i].Key.Share.I, newResults[i].Key.Share.I)
+		require.False(t, results[i].Key.Share.V.Equal(newResults[i].Key.Share.V))
+	}
+
+	// old shares are useless once mixed with new ones
+	oldShares := sharesOf(results)
+	newShares := sharesOf(newResults)
+	mixed := append(slices.Clone(oldShares[:thr-1]), newShares[thr-1])
+	mixedSecret, err := share.RecoverSecret(suite, mixed, thr, n)
+	require.NoError(t, err)
+	require.False(t, secret.Equal(mixedSecret))
+	newPub := share.NewPubPoly(suite, nil, newResults[0].Key.Commits)
+	for _, old := range oldShares {
+		require.False(t, newPub.Check(old))
+	}
+}
+
+func TestRefreshNonZeroDeal(t *testing.T) {
+	n := uint32(5)
+	thr := uint32(3)
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  list,
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	results := RunDKG(t, tns, conf, nil, nil, nil)
+	for i, tn := range tns {
+		tn.res = results[i]
+	}
+
+	refreshConf := Config{
+		Suite:    suite,
+		NewNodes: list,
+		Auth:     schnorr.NewScheme(suite),
+	}
+	SetupRefreshNodes(tns, &refreshConf)
+	cheater := list[0].Index
+	// the cheater tries to shift the distributed secret
+	dm := func(deals []*DealBundle) []*DealBundle {
+		for _, d := range deals {
+			if d.DealerIndex == cheater {
+				public := suite.Point().Pick(random.New())
+				d.Public = append([]kyber.Point{public}, d.Public[1:]...)
+			}
+		}
+		return deals
+	}
+	// the cheater does not look at its own deal and finishes right away, while
+	// the honest nodes wait for the justification phase
+	cheaterResults := RunSteps(t, tns, dm, nil, nil)
+	require.Len(t, cheaterResults, 1)
+	var newResults []*Result
+	for _, tn := range tns[1:] {
+		require.True(t, slices.Contains(tn.dkg.evicted, cheater))
+		res, err := tn.dkg.ProcessJustifications(nil)
+		require.NoError(t, err)
+		newResults = append(newResults, res)
+	}
+	for _, res := range newResults {
+		for _, node := range res.QUAL {
+			require.NotEqual(t, cheater, node.Index)
+		}
+		require.True(t, results[0].Key.Public().Equal(res.Key.Public()))
+	}
+	testResults(t, suite, thr, n, newResults)
+}
+
+func TestRefreshInvalidConfig(t *testing.T) {
+	n := uint32(4)
+	thr := uint32(3)
+	suite := edwards25519.NewBlakeSHA256Ed25519()
+	tns := GenerateTestNodes(suite, n)
+	list := NodesFromTest(tns)
+	conf := Config{
+		Suite:     suite,
+		NewNodes:  list,
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(suite),
+	}
+	results := RunDKG(t, tns, conf, nil, nil, nil)
+
+	newConf := func() *Config {
+		return &Config{
+			Suite:    suite,
+			Longterm: tns[0].Private,
+			NewNodes: list,
+			Share:    results[0].Key,
+			Nonce:    GetNonce(),
+			Auth:     schnorr.NewScheme(suite),
+		}
+	}
+	_, err := NewRefreshHandler(newConf())
+	require.NoError(t, err)
+
+	c := newConf()
+	c.Share = nil
+	_, err = NewRefreshHandler(c)
+	require.Error(t, err)
+
+	c = newConf()
+	c.Threshold = thr + 1
+	_, err = NewRefreshHandler(c)
+	require.Error(t, err)
+
+	c = newConf()
+	c.OldNodes = list[1:]
+	_, err = NewRefreshHandler(c)
+	require.Error(t, err)
+
+	c = newConf()
+	c.Share = results[1].Key
+	_, err = NewRefreshHandler(c)
+	require.Error(t, err)
+}
+
+func TestProtoRefreshFast(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		n := uint32(5)
+		thr := uint32(4)
+		period := 1 * time.Second
+		suite := edwards25519.NewBlakeSHA256Ed25519()
+		tns := GenerateTestNodes(suite, n)
+		list := NodesFromTest(tns)
+		conf := Config{
+			Suite:     suite,
+			NewNodes:  list,
+			Threshold: thr,
+			Auth:      schnorr.NewScheme(suite),
+		}
+		results := RunDKG(t, tns, conf, nil, nil, nil)
+		for i, tn := range tns {
+			tn.res = results[i]
+		}
+
+		network := NewTestNetwork(n)
+		nonce := GetNonce()
+		for _, tn := range tns {
+			c := &Config{
+				Suite:    suite,
+				Longterm: tn.Private,
+				NewNodes: list,
+				Share:    tn.res.Key,
+				Nonce:    nonce,
+				Auth:     schnorr.NewScheme(suite),
+				FastSync: true,
+			}
+			tn.phaser = NewTimePhaserFunc(func(Phase) {
+				time.Sleep(period)
+			})
+			tn.board = network.BoardFor(tn.Index)
+			proto, err := NewRefreshProtocol(c, tn.board, tn.phaser, false)
+			require.NoError(t, err)
+			tn.proto = proto
+		}
+
+		var resCh = make(chan OptionResult, 1)
+		for _, node := range tns {
+			go func(n *TestNode) { resCh <- <-n.proto.WaitEnd() }(node)
+		}
+		for _, node := range tns {
+			go node.phaser.Start()
+		}
+
+		var newResults []*Result
+		for optRes := range resCh {
+			require.NoError(t, optRes.Error)
+			newResults = append(newResults, optRes.Result)
+			if len(newResults) == int(n) {
+				break
+			}
+		}
+		testResults(t, suite, thr, n, newResults)
+		var pub kyber.Point = results[0].Key.Public()
+		require.True(t, pub.Equal(newResults[0].Key.Public()))
+		// we let the phaser finish all phases
+		for range 3 {
+			time.Sleep(period + 100*time.Millisecond)
+			synctest.Wait()
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package dkg
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4/internal/wire"
+)
+
+// Marshal returns the versioned binary encoding of the DealBundle.
+func (d *DealBundle) Marshal() ([]byte, error) {
+	enc := wire.NewEncoder(wire.TagDKGDealBundle)
+	enc.Uint32(d.DealerIndex)
+	enc.Uint32(uint32(len(d.Deals)))
+	for _, deal := range d.Deals {
+		enc.Uint32(deal.ShareIndex)
+		enc.Bytes(deal.EncryptedShare)
+	}
+	enc.Points(d.Public)
+	enc.Bytes(d.SessionID)
+	enc.Bytes(d.Signature)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a DealBundle encoded with Marshal. It returns an error if
+// the encoding is invalid, if the public polynomial is empty, or if a share
+// holder receives more than one deal.
+func (d *DealBundle) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagDKGDealBundle)
+	dealerIndex := dec.Uint32()
+	// a deal takes at least its index and the length of its share
+	deals := make([]Deal, dec.Count(8))
+	seen := make(map[uint32]bool, len(deals))
+	for i := range deals {
+		deals[i].ShareIndex = dec.Uint32()
+		deals[i].EncryptedShare = dec.Bytes()
+		if seen[deals[i].ShareIndex] {
+			dec.Fail(fmt.Errorf("dkg: duplicate deal for share holder %d", deals[i].ShareIndex))
+		}
+		seen[deals[i].ShareIndex] = true
+	}
+	public := dec.Points(suite)
+	sid := dec.Bytes()
+	signature := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	if len(public) == 0 {
+		return errors.New("dkg: empty public polynomial in deal bundle")
+	}
+	d.DealerIndex = dealerIndex
+	d.Deals = deals
+	d.Public = public
+	d.SessionID = sid
+	d.Signature = signature
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the ResponseBundle.
+func (b *ResponseBundle) Marshal() ([]byte, error) {
+	enc := wire.NewEncoder(wire.TagDKGResponseBundle)
+	enc.Uint32(b.ShareIndex)
+	enc.Uint32(uint32(len(b.Responses)))
+	for _, resp := range b.Responses {
+		enc.Uint32(resp.DealerIndex)
+		enc.Byte(byte(resp.Status))
+	}
+	enc.Bytes(b.SessionID)
+	enc.Bytes(b.Signature)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a ResponseBundle encoded with Marshal. It returns an error
+// if the encoding is invalid, if a status is unknown or if there is more than
+// one response for a dealer.
+func (b *ResponseBundle) Unmarshal(data []byte) error {
+	dec := wire.NewDecoder(data, wire.TagDKGResponseBundle)
+	shareIndex := dec.Uint32()
+	responses := make([]Response, dec.Count(5))
+	seen := make(map[uint32]bool, len(responses))
+	for i := range responses {
+		responses[i].DealerIndex = dec.Uint32()
+		responses[i].Status = Status(dec.Byte())
+		if responses[i].Status != Success && responses[i].Status != Complaint {
+			dec.Fail(fmt.Errorf("dkg: invalid status %d", responses[i].Status))
+		}
+		if seen[responses[i].DealerIndex] {
+			dec.Fail(fmt.Errorf("dkg: duplicate response for dealer %d", responses[i].DealerIndex))
+		}
+		seen[responses[i].DealerIndex] = true
+	}
+	sid := dec.Bytes()
+	signature := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	b.ShareIndex = shareIndex
+	b.Responses = responses
+	b.SessionID = sid
+	b.Signature = signature
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the JustificationBundle.
+func (j *JustificationBundle) Marshal() ([]byte, error) {
+	enc := wire.NewEncoder(wire.TagDKGJustificationBundle)
+	enc.Uint32(j.DealerIndex)
+	enc.Uint32(uint32(len(j.Justifications)))
+	for _, just := range j.Justifications {
+		enc.Uint32(just.ShareIndex)
+		enc.Scalar(just.Share)
+	}
+	enc.Bytes(j.SessionID)
+	enc.Bytes(j.Signature)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a JustificationBundle encoded with Marshal. It returns an
+// error if the encoding is invalid or if there is more than one justification
+// for a share holder.
+func (j *JustificationBundle) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagDKGJustificationBundle)
+	dealerIndex := dec.Uint32()
+	justifs := make([]Justification, dec.Count(4+suite.ScalarLen()))
+	seen := make(map[uint32]bool, len(justifs))
+	for i := range justifs {
+		justifs[i].ShareIndex = dec.Uint32()
+		justifs[i].Share = dec.Scalar(suite)
+		if seen[justifs[i].ShareIndex] {
+			dec.Fail(fmt.Errorf("dkg: duplicate justification for share holder %d", justifs[i].ShareIndex))
+		}
+		seen[justifs[i].ShareIndex] = true
+	}
+	sid := dec.Bytes()
+	signature := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	j.DealerIndex = dealerIndex
+	j.Justifications = justifs
+	j.SessionID = sid
+	j.Signature = signature
+	return nil
+}
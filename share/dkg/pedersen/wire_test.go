@@ -0,0 +1,187 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/internal/wire"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+var wireSuite = edwards25519.NewBlakeSHA256Ed25519()
+
+func wireDeals(t testing.TB, bundles []*DealBundle) []*DealBundle {
+	out := make([]*DealBundle, len(bundles))
+	for i, b := range bundles {
+		buff, err := b.Marshal()
+		require.NoError(t, err)
+		out[i] = new(DealBundle)
+		require.NoError(t, out[i].Unmarshal(buff, wireSuite))
+	}
+	return out
+}
+
+func wireResponses(t testing.TB, bundles []*ResponseBundle) []*ResponseBundle {
+	out := make([]*ResponseBundle, len(bundles))
+	for i, b := range bundles {
+		buff, err := b.Marshal()
+		require.NoError(t, err)
+		out[i] = new(ResponseBundle)
+		require.NoError(t, out[i].Unmarshal(buff))
+	}
+	return out
+}
+
+func wireJustifs(t testing.TB, bundles []*JustificationBundle) []*JustificationBundle {
+	out := make([]*JustificationBundle, len(bundles))
+	for i, b := range bundles {
+		buff, err := b.Marshal()
+		require.NoError(t, err)
+		out[i] = new(JustificationBundle)
+		require.NoError(t, out[i].Unmarshal(buff, wireSuite))
+	}
+	return out
+}
+
+// TestDKGWire runs a DKG with a complaint where every packet goes through its
+// binary encoding.
+func TestDKGWire(t *testing.T) {
+	n := uint32(5)
+	thr := uint32(3)
+	tns := GenerateTestNodes(wireSuite, n)
+	conf := Config{
+		Suite:     wireSuite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: thr,
+		Auth:      schnorr.NewScheme(wireSuite),
+	}
+	dm := func(deals []*DealBundle) []*DealBundle {
+		// the first dealer doesn't give any deal to the second node
+		deals[0].Deals = deals[0].Deals[1:]
+		hash, err := deals[0].Hash()
+		require.NoError(t, err)
+		deals[0].Signature, err = conf.Auth.Sign(tns[0].Private, hash)
+		require.NoError(t, err)
+		return wireDeals(t, deals)
+	}
+	var justifs []*JustificationBundle
+	jm := func(bundles []*JustificationBundle) []*JustificationBundle {
+		justifs = bundles
+		return wireJustifs(t, bundles)
+	}
+	rm := func(bundles []*ResponseBundle) []*ResponseBundle {
+		return wireResponses(t, bundles)
+	}
+	results := RunDKG(t, tns, conf, dm, rm, jm)
+	require.Len(t, justifs, 1)
+	testResults(t, wireSuite, thr, n, results)
+}
+
+func TestWireInvalid(t *testing.T) {
+	deal := &DealBundle{
+		DealerIndex: 1,
+		Deals: []Deal{
+			{ShareIndex: 0, EncryptedShare: []byte{1, 2, 3}},
+			{ShareIndex: 0, EncryptedShare: []byte{4, 5, 6}},
+		},
+		Public:    []kyber.Point{wireSuite.Point().Base()},
+		SessionID: []byte("session"),
+	}
+	buff, err := deal.Marshal()
+	require.NoError(t, err)
+	require.ErrorContains(t, new(DealBundle).Unmarshal(buff, wireSuite), "duplicate")
+
+	deal.Deals = deal.Deals[:1]
+	buff, err = deal.Marshal()
+	require.NoError(t, err)
+	require.NoError(t, new(DealBundle).Unmarshal(buff, wireSuite))
+	require.ErrorIs(t, new(DealBundle).Unmarshal(buff[:len(buff)-1], wireSuite), wire.ErrShort)
+	require.ErrorIs(t, new(DealBundle).Unmarshal(append(buff, 0), wireSuite), wire.ErrTrailing)
+	require.ErrorIs(t, new(ResponseBundle).Unmarshal(buff), wire.ErrTag)
+
+	deal.Public = nil
+	buff, err = deal.Marshal()
+	require.NoError(t, err)
+	require.Error(t, new(DealBundle).Unmarshal(buff, wireSuite))
+
+	resp := &ResponseBundle{
+		ShareIndex: 1,
+		Responses:  []Response{{DealerIndex: 0, Status: 2}},
+	}
+	buff, err = resp.Marshal()
+	require.NoError(t, err)
+	require.ErrorContains(t, new(ResponseBundle).Unmarshal(buff), "status")
+
+	resp.Responses = []Response{{DealerIndex: 0, Status: Complaint}, {DealerIndex: 0, Status: Success}}
+	buff, err = resp.Marshal()
+	require.NoError(t, err)
+	require.ErrorContains(t, new(ResponseBundle).Unmarshal(buff), "duplicate")
+
+	justif := &JustificationBundle{
+		DealerIndex: 0,
+		Justifications: []Justification{
+			{ShareIndex: 1, Share: wireSuite.Scalar().One()},
+			{ShareIndex: 1, Share: wireSuite.Scalar().One()},
+		},
+	}
+	buff, err = justif.Marshal()
+	require.NoError(t, err)
+	require.ErrorContains(t, new(JustificationBundle).Unmarshal(buff, wireSuite), "duplicate")
+
+	justif.Justifications[1].Share = nil
+	_, err = justif.Marshal()
+	require.Error(t, err)
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	tns := GenerateTestNodes(wireSuite, 4)
+	conf := Config{
+		Suite:     wireSuite,
+		NewNodes:  NodesFromTest(tns),
+		Threshold: 3,
+		Auth:      schnorr.NewScheme(wireSuite),
+		FastSync:  true,
+	}
+	SetupNodes(tns, &conf)
+	deals, err := tns[0].dkg.Deals()
+	require.NoError(f, err)
+	_, err = tns[1].dkg.Deals()
+	require.NoError(f, err)
+	resps, err := tns[1].dkg.ProcessDeals([]*DealBundle{deals})
+	require.NoError(f, err)
+	justifs := &JustificationBundle{
+		DealerIndex:    0,
+		Justifications: []Justification{{ShareIndex: 1, Share: wireSuite.Scalar().One()}},
+		SessionID:      deals.SessionID,
+		Signature:      deals.Signature,
+	}
+	for _, m := range []interface{ Marshal() ([]byte, error) }{deals, resps, justifs} {
+		buff, err := m.Marshal()
+		require.NoError(f, err)
+		f.Add(buff)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// anything that decodes must encode back to a message that decodes
+		// to the same encoding
+		if d := new(DealBundle); d.Unmarshal(data, wireSuite) == nil {
+			requireStable(t, d, wireDeals(t, []*DealBundle{d})[0])
+		}
+		if r := new(ResponseBundle); r.Unmarshal(data) == nil {
+			requireStable(t, r, wireResponses(t, []*ResponseBundle{r})[0])
+		}
+		if j := new(JustificationBundle); j.Unmarshal(data, wireSuite) == nil {
+			requireStable(t, j, wireJustifs(t, []*JustificationBundle{j})[0])
+		}
+	})
+}
+
+func requireStable(t *testing.T, m, decoded interface{ Marshal() ([]byte, error) }) {
+	buff, err := m.Marshal()
+	require.NoError(t, err)
+	buff2, err := decoded.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, buff, buff2)
+}
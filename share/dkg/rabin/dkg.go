@@ -33,6 +33,10 @@
 //     must be broadcasted to all the QUAL participant.
 //  7. At this point, every QUAL participant can issue the distributed key by
 //     calling `DistKeyShare()`.
+//
+// Instead of calling these steps manually, one can use a Protocol which runs
+// the whole flow over a Board, moving from one phase to the next one according
+// to a Phaser.
 package dkg
 
 import (
@@ -655,7 +655,7 @@ func checkDks(dks1, dks2 *DistKeyShare) bool {
 	return true
 }
 
-func fullExchange(t *testing.T) {
+func fullExchange(t testing.TB) {
 	dkgs = dkgGen()
 	// full secret sharing exchange
 	// 1. broadcast deals
@@ -0,0 +1,389 @@
+package dkg
+
+import (
+	"errors"
+	"time"
+)
+
+// Phase is a type that represents the different stages of the DKG protocol
+// when it is driven by a Protocol.
+type Phase int
+
+const (
+	InitPhase Phase = iota
+	DealPhase
+	ResponsePhase
+	JustifPhase
+	CommitPhase
+	ComplaintPhase
+	ReconstructPhase
+	FinishPhase
+)
+
+func (p Phase) String() string {
+	switch p {
+	case InitPhase:
+		return "init"
+	case DealPhase:
+		return "deal"
+	case ResponsePhase:
+		return "response"
+	case JustifPhase:
+		return "justification"
+	case CommitPhase:
+		return "commit"
+	case ComplaintPhase:
+		return "complaint"
+	case ReconstructPhase:
+		return "reconstruct"
+	case FinishPhase:
+		return "finished"
+	default:
+		return "unknown"
+	}
+}
+
+// Board is the interface between the dkg protocol and the external world. It
+// consists in pushing packets out to other nodes and receiving in packets from
+// the other nodes. Deals are private and must only be delivered to the
+// participant at the given index, while all other packets are broadcasted to
+// every participant.
+type Board interface {
+	PushDeal(to uint32, d *Deal)
+	IncomingDeal() <-chan Deal
+	PushResponse(*Response)
+	IncomingResponse() <-chan Response
+	PushJustification(*Justification)
+	IncomingJustification() <-chan Justification
+	PushSecretCommits(*SecretCommits)
+	IncomingSecretCommits() <-chan SecretCommits
+	PushComplaintCommits(*ComplaintCommits)
+	IncomingComplaintCommits() <-chan ComplaintCommits
+	PushReconstructCommits(*ReconstructCommits)
+	IncomingReconstructCommits() <-chan ReconstructCommits
+}
+
+// Phaser must signal on its channel when the protocol should move to a next
+// phase. Phase must be sequential: DealPhase (start), ResponsePhase,
+// JustifPhase, CommitPhase, ComplaintPhase, ReconstructPhase and then
+// FinishPhase. The CommitPhase marks the end of the VSS part of the protocol,
+// i.e. the moment where all participants that have not responded to a deal
+// are considered to have complained about it.
+type Phaser interface {
+	NextPhase() chan Phase
+}
+
+// TimePhaser is a phaser that sleeps between the different phases and send the
+// signal over its channel.
+type TimePhaser struct {
+	out   chan Phase
+	sleep func(Phase)
+}
+
+func NewTimePhaser(p time.Duration) *TimePhaser {
+	return NewTimePhaserFunc(func(Phase) { time.Sleep(p) })
+}
+
+func NewTimePhaserFunc(sleepPeriod func(Phase)) *TimePhaser {
+	return &TimePhaser{
+		out:   make(chan Phase, 7),
+		sleep: sleepPeriod,
+	}
+}
+
+func (t *TimePhaser) Start() {
+	for p := DealPhase; p < FinishPhase; p++ {
+		t.out <- p
+		t.sleep(p)
+	}
+	t.out <- FinishPhase
+}
+
+func (t *TimePhaser) NextPhase() chan Phase {
+	return t.out
+}
+
+// Protocol contains the logic to run the DKG protocol over a generic
+// communication channel, called Board. It handles the receival of packets,
+// the ordering of the phases and the termination. Packets received before the
+// phase in which they must be processed are kept until the protocol reaches
+// that phase.
+//
+// In FastSync mode, the protocol does not wait for the phaser when it can
+// safely move on: it processes the responses as soon as it received a deal
+// from every other participant, and it reveals its secret commitments as soon
+// as every deal is certified. The result is delivered as soon as all the
+// commitments of the QUAL set are known, but the protocol keeps answering the
+// complaints of the other participants until the phaser signals the
+// FinishPhase.
+type Protocol struct {
+	board    Board
+	phaser   Phaser
+	dkg      *DistKeyGenerator
+	fastSync bool
+	res      chan OptionResult
+	phase    Phase
+	// indicates whether the result has already been sent out
+	done bool
+
+	deals        []*Deal
+	resps        []*Response
+	justifs      []*Justification
+	commits      []*SecretCommits
+	complaints   []*ComplaintCommits
+	reconstructs []*ReconstructCommits
+}
+
+// NewProtocol returns a Protocol driving the given DistKeyGenerator and starts
+// it in the background. The result can be read from WaitEnd.
+func NewProtocol(dkg *DistKeyGenerator, b Board, phaser Phaser, fastSync bool) *Protocol {
+	p := &Protocol{
+		board:    b,
+		phaser:   phaser,
+		dkg:      dkg,
+		fastSync: fastSync,
+		res:      make(chan OptionResult, 1),
+		phase:    InitPhase,
+	}
+	go p.Start()
+	return p
+}
+
+// Start runs the protocol until the phaser signals the FinishPhase, or until
+// an unrecoverable error happens.
+func (p *Protocol) Start() {
+	for {
+		select {
+		case newPhase := <-p.phaser.NextPhase():
+			if !p.moveTo(newPhase) || newPhase == FinishPhase {
+				return
+			}
+		case deal := <-p.board.IncomingDeal():
+			p.deals = append(p.deals, &deal)
+			if p.phase >= DealPhase {
+				p.processDeals()
+			}
+		case resp := <-p.board.IncomingResponse():
+			p.resps = append(p.resps, &resp)
+			if p.phase >= ResponsePhase {
+				p.processResponses()
+			}
+		case justif := <-p.board.IncomingJustification():
+			p.justifs = append(p.justifs, &justif)
+			if p.phase >= JustifPhase {
+				p.processJustifications()
+			}
+		case sc := <-p.board.IncomingSecretCommits():
+			p.commits = append(p.commits, &sc)
+			if p.phase >= CommitPhase {
+				p.processSecretCommits()
+			}
+		case cc := <-p.board.IncomingComplaintCommits():
+			p.complaints = append(p.complaints, &cc)
+			if p.phase >= ComplaintPhase {
+				p.processComplaintCommits()
+			}
+		case rc := <-p.board.IncomingReconstructCommits():
+			p.reconstructs = append(p.reconstructs, &rc)
+			if p.phase >= ReconstructPhase {
+				p.processReconstructCommits()
+			}
+		}
+		if p.fastSync && !p.fastForward() {
+			return
+		}
+	}
+}
+
+// WaitEnd returns the channel on which the result of the protocol is sent.
+func (p *Protocol) WaitEnd() <-chan OptionResult {
+	return p.res
+}
+
+// OptionResult holds either the distributed key share generated by the
+// protocol, or the error that made it abort.
+type OptionResult struct {
+	Result *DistKeyShare
+	Error  error
+}
+
+// moveTo runs all the phases between the current one and the given one. It
+// returns false if the protocol must be stopped.
+func (p *Protocol) moveTo(phase Phase) bool {
+	for p.phase < phase {
+		p.phase++
+		if !p.enter(p.phase) {
+			return false
+		}
+	}
+	return true
+}
+
+// fastForward moves to the next phases without waiting for the phaser when
+// all the expected packets have been received, whether they arrived before or
+// after the current phase was entered. It returns false if the protocol must
+// be stopped.
+func (p *Protocol) fastForward() bool {
+	n := len(p.dkg.participants)
+	if p.phase == DealPhase && len(p.dkg.verifiers) == n {
+		// a deal from every participant, including ours
+		if !p.moveTo(ResponsePhase) {
+			return false
+		}
+	}
+	if p.phase >= ResponsePhase && p.phase < CommitPhase && len(p.dkg.QUAL()) == n {
+		// every deal is certified, there is nothing left to justify
+		if !p.moveTo(CommitPhase) {
+			return false
+		}
+	}
+	if p.phase >= CommitPhase && !p.done && p.dkg.Finished() {
+		p.sendResult()
+	}
+	return true
+}
+
+// enter performs the actions required when the protocol enters the given
+// phase. It returns false if the protocol must be stopped.
+func (p *Protocol) enter(phase Phase) bool {
+	switch phase {
+	case InitPhase:
+	case DealPhase:
+		deals, err := p.dkg.Deals()
+		if err != nil {
+			p.sendError(err)
+			return false
+		}
+		for i, deal := range deals {
+			p.board.PushDeal(uint32(i), deal)
+		}
+		p.processDeals()
+	case ResponsePhase:
+		p.processResponses()
+	case JustifPhase:
+		p.processJustifications()
+	case CommitPhase:
+		// end of the VSS part: participants that did not answer are
+		// considered as complaining, by our verifiers and by our dealer
+		p.dkg.SetTimeout()
+		p.dkg.dealer.SetTimeout()
+		if !p.dkg.Certified() {
+			p.sendError(errors.New("dkg: not enough certified deals"))
+			return false
+		}
+		if sc, err := p.dkg.SecretCommits(); err == nil {
+			p.board.PushSecretCommits(sc)
+		}
+		p.processSecretCommits()
+	case ComplaintPhase:
+		p.processComplaintCommits()
+	case ReconstructPhase:
+		p.processReconstructCommits()
+	case FinishPhase:
+		if p.done {
+			return true
+		}
+		if !p.dkg.Finished() {
+			p.sendError(errors.New("dkg: protocol not finished, missing commitments"))
+			return false
+		}
+		p.sendResult()
+	}
+	return true
+}
+
+func (p *Protocol) processDeals() {
+	for _, deal := range p.deals {
+		resp, err := p.dkg.ProcessDeal(deal)
+		if err != nil {
+			continue
+		}
+		p.board.PushResponse(resp)
+	}
+	p.deals = nil
+}
+
+func (p *Protocol) processResponses() {
+	// responses about a deal not yet received are kept for later
+	var pending []*Response
+	for _, resp := range p.resps {
+		if resp.Response.Index == p.dkg.index {
+			// our own responses are already taken into account
+			continue
+		}
+		if _, ok := p.dkg.verifiers[resp.Index]; !ok {
+			pending = append(pending, resp)
+			continue
+		}
+		j, err := p.dkg.ProcessResponse(resp)
+		if err != nil || j == nil {
+			continue
+		}
+		p.board.PushJustification(j)
+	}
+	p.resps = pending
+}
+
+func (p *Protocol) processJustifications() {
+	for _, j := range p.justifs {
+		if j.Index == p.dkg.index {
+			continue
+		}
+		_ = p.dkg.ProcessJustification(j)
+	}
+	p.justifs = nil
+}
+
+func (p *Protocol) processSecretCommits() {
+	for _, sc := range p.commits {
+		if sc.Index == p.dkg.index {
+			continue
+		}
+		cc, err := p.dkg.ProcessSecretCommits(sc)
+		if err != nil || cc == nil {
+			continue
+		}
+		p.board.PushComplaintCommits(cc)
+	}
+	p.commits = nil
+}
+
+func (p *Protocol) processComplaintCommits() {
+	for _, cc := range p.complaints {
+		if cc.Index == p.dkg.index {
+			continue
+		}
+		rc, err := p.dkg.ProcessComplaintCommits(cc)
+		if err != nil {
+			continue
+		}
+		p.board.PushReconstructCommits(rc)
+	}
+	p.complaints = nil
+}
+
+func (p *Protocol) processReconstructCommits() {
+	for _, rc := range p.reconstructs {
+		_ = p.dkg.ProcessReconstructCommits(rc)
+	}
+	p.reconstructs = nil
+}
+
+func (p *Protocol) sendResult() {
+	dks, err := p.dkg.DistKeyShare()
+	p.done = true
+	p.res <- OptionResult{
+		Result: dks,
+		Error:  err,
+	}
+}
+
+func (p *Protocol) sendError(err error) {
+	if p.done {
+		return
+	}
+	p.done = true
+	p.res <- OptionResult{
+		Error: err,
+	}
+}
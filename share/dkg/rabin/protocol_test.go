@@ -0,0 +1,230 @@
+package dkg
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.dedis.ch/kyber/v4/share"
+)
+
+type TestNetwork struct {
+	boards []*TestBoard
+	noops  []uint32
+}
+
+func NewTestNetwork(n uint32) *TestNetwork {
+	t := &TestNetwork{}
+	for i := range n {
+		t.boards = append(t.boards, NewTestBoard(i, n, t))
+	}
+	return t
+}
+
+func (n *TestNetwork) SetNoop(index uint32) {
+	n.noops = append(n.noops, index)
+}
+
+func (n *TestNetwork) isNoop(i uint32) bool {
+	return slices.Contains(n.noops, i)
+}
+
+func broadcast[T any](n *TestNetwork, get func(*TestBoard) chan T, v T) {
+	for _, board := range n.boards {
+		if !n.isNoop(board.index) {
+			get(board) <- v
+		}
+	}
+}
+
+type TestBoard struct {
+	index        uint32
+	network      *TestNetwork
+	deals        chan Deal
+	resps        chan Response
+	justifs      chan Justification
+	commits      chan SecretCommits
+	complaints   chan ComplaintCommits
+	reconstructs chan ReconstructCommits
+}
+
+func NewTestBoard(index uint32, n uint32, network *TestNetwork) *TestBoard {
+	return &TestBoard{
+		index:        index,
+		network:      network,
+		deals:        make(chan Deal, n),
+		resps:        make(chan Response, n*n),
+		justifs:      make(chan Justification, n*n),
+		commits:      make(chan SecretCommits, n),
+		complaints:   make(chan ComplaintCommits, n*n),
+		reconstructs: make(chan ReconstructCommits, n*n),
+	}
+}
+
+func (t *TestBoard) PushDeal(to uint32, d *Deal) {
+	if t.network.isNoop(to) {
+		return
+	}
+	t.network.boards[to].deals <- *d
+}
+
+func (t *TestBoard) PushResponse(r *Response) {
+	broadcast(t.network, func(b *TestBoard) chan Response { return b.resps }, *r)
+}
+
+func (t *TestBoard) PushJustification(j *Justification) {
+	broadcast(t.network, func(b *TestBoard) chan Justification { return b.justifs }, *j)
+}
+
+func (t *TestBoard) PushSecretCommits(sc *SecretCommits) {
+	broadcast(t.network, func(b *TestBoard) chan SecretCommits { return b.commits }, *sc)
+}
+
+func (t *TestBoard) PushComplaintCommits(cc *ComplaintCommits) {
+	broadcast(t.network, func(b *TestBoard) chan ComplaintCommits { return b.complaints }, *cc)
+}
+
+func (t *TestBoard) PushReconstructCommits(rc *ReconstructCommits) {
+	broadcast(t.network, func(b *TestBoard) chan ReconstructCommits { return b.reconstructs }, *rc)
+}
+
+func (t *TestBoard) IncomingDeal() <-chan Deal {
+	return t.deals
+}
+
+func (t *TestBoard) IncomingResponse() <-chan Response {
+	return t.resps
+}
+
+func (t *TestBoard) IncomingJustification() <-chan Justification {
+	return t.justifs
+}
+
+func (t *TestBoard) IncomingSecretCommits() <-chan SecretCommits {
+	return t.commits
+}
+
+func (t *TestBoard) IncomingComplaintCommits() <-chan ComplaintCommits {
+	return t.complaints
+}
+
+func (t *TestBoard) IncomingReconstructCommits() <-chan ReconstructCommits {
+	return t.reconstructs
+}
+
+// runProto runs the protocol for all participants except the absent ones, with
+// a phaser sleeping for period between the phases, and returns the results in
+// the order they are received.
+func runProto(t *testing.T, period time.Duration, fastSync bool, absent ...uint32) []OptionResult {
+	return runProtoPhasers(t, func() *TimePhaser { return NewTimePhaser(period) }, fastSync, absent...)
+}
+
+func runProtoPhasers(t *testing.T, newPhaser func() *TimePhaser, fastSync bool, absent ...uint32) []OptionResult {
+	network := NewTestNetwork(nbParticipants)
+	for _, idx := range absent {
+		network.SetNoop(idx)
+	}
+	gens := dkgGen()
+	var phasers []*TimePhaser
+	var protos []*Protocol
+	for i, gen := range gens {
+		if slices.Contains(absent, uint32(i)) {
+			continue
+		}
+		phaser := newPhaser()
+		phasers = append(phasers, phaser)
+		protos = append(protos, NewProtocol(gen, network.boards[i], phaser, fastSync))
+	}
+	for _, phaser := range phasers {
+		go phaser.Start()
+	}
+	results := make([]OptionResult, 0, len(protos))
+	for _, proto := range protos {
+		results = append(results, <-proto.WaitEnd())
+	}
+	return results
+}
+
+func checkResults(t *testing.T, results []OptionResult) {
+	shares := make([]*share.PriShare, 0, len(results))
+	for _, res := range results {
+		require.NoError(t, res.Error)
+		require.NotNil(t, res.Result)
+		require.True(t, checkDks(res.Result, results[0].Result))
+		shares = append(shares, res.Result.Share)
+	}
+	thr := nbParticipants/2 + 1
+	secret, err := share.RecoverSecret(suite, shares, thr, nbParticipants)
+	require.NoError(t, err)
+	require.True(t, suite.Point().Mul(secret, nil).Equal(results[0].Result.Public()))
+}
+
+func TestProtoFull(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		period := time.Second
+		start := time.Now()
+		results := runProto(t, period, false)
+		require.Len(t, results, int(nbParticipants))
+		checkResults(t, results)
+		// the result is only given once the phaser reached the end
+		require.GreaterOrEqual(t, time.Since(start), 6*period)
+	})
+}
+
+func TestProtoFullFast(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		// the phasers only signal the DealPhase until the results are in
+		var mu sync.Mutex
+		var signaled []Phase
+		release := make(chan struct{})
+		defer close(release)
+		newPhaser := func() *TimePhaser {
+			return NewTimePhaserFunc(func(p Phase) {
+				mu.Lock()
+				signaled = append(signaled, p)
+				mu.Unlock()
+				if p == DealPhase {
+					<-release
+				}
+			})
+		}
+		results := runProtoPhasers(t, newPhaser, true)
+		require.Len(t, results, int(nbParticipants))
+		checkResults(t, results)
+		// everything went well so no need to wait for the phaser
+		mu.Lock()
+		require.Equal(t, slices.Repeat([]Phase{DealPhase}, int(nbParticipants)), signaled)
+		mu.Unlock()
+	})
+}
+
+func TestProtoAbsent(t *testing.T) {
+	for _, fastSync := range []bool{false, true} {
+		synctest.Test(t, func(t *testing.T) {
+			period := time.Second
+			absent := uint32(nbParticipants - 1)
+			results := runProto(t, period, fastSync, absent)
+			require.Len(t, results, int(nbParticipants-1))
+			checkResults(t, results)
+			time.Sleep(7 * period)
+		})
+	}
+}
+
+func TestProtoNotCertified(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		period := time.Second
+		// only two participants are present for a threshold of four
+		absent := []uint32{2, 3, 4, 5, 6}
+		results := runProto(t, period, true, absent...)
+		for _, res := range results {
+			require.Error(t, res.Error)
+			require.Nil(t, res.Result)
+		}
+		time.Sleep(7 * period)
+	})
+}
@@ -0,0 +1,228 @@
+package dkg
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4/internal/wire"
+	"go.dedis.ch/kyber/v4/share"
+	vss "go.dedis.ch/kyber/v4/share/vss/rabin"
+)
+
+// Marshal returns the versioned binary encoding of the Deal. The encrypted deal
+// is embedded using its own Marshal encoding.
+func (d *Deal) Marshal() ([]byte, error) {
+	if d.Deal == nil {
+		return nil, errors.New("dkg: nil encrypted deal")
+	}
+	buff, err := d.Deal.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	enc := wire.NewEncoder(wire.TagRabinDeal)
+	enc.Uint32(d.Index)
+	enc.Bytes(buff)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a Deal encoded with Marshal.
+func (d *Deal) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagRabinDeal)
+	index := dec.Uint32()
+	buff := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	encD := new(vss.EncryptedDeal)
+	if err := encD.Unmarshal(buff, suite); err != nil {
+		return err
+	}
+	d.Index = index
+	d.Deal = encD
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the Response. The vss
+// response is embedded using its own Marshal encoding.
+func (r *Response) Marshal() ([]byte, error) {
+	if r.Response == nil {
+		return nil, errors.New("dkg: nil response")
+	}
+	buff, err := r.Response.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	enc := wire.NewEncoder(wire.TagRabinResponse)
+	enc.Uint32(r.Index)
+	enc.Bytes(buff)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a Response encoded with Marshal.
+func (r *Response) Unmarshal(data []byte) error {
+	dec := wire.NewDecoder(data, wire.TagRabinResponse)
+	index := dec.Uint32()
+	buff := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	resp := new(vss.Response)
+	if err := resp.Unmarshal(buff); err != nil {
+		return err
+	}
+	r.Index = index
+	r.Response = resp
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the Justification. The vss
+// justification is embedded using its own Marshal encoding.
+func (j *Justification) Marshal() ([]byte, error) {
+	if j.Justification == nil {
+		return nil, errors.New("dkg: nil justification")
+	}
+	buff, err := j.Justification.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	enc := wire.NewEncoder(wire.TagRabinJustification)
+	enc.Uint32(j.Index)
+	enc.Bytes(buff)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a Justification encoded with Marshal.
+func (j *Justification) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagRabinJustification)
+	index := dec.Uint32()
+	buff := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	justif := new(vss.Justification)
+	if err := justif.Unmarshal(buff, suite); err != nil {
+		return err
+	}
+	j.Index = index
+	j.Justification = justif
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the SecretCommits.
+func (sc *SecretCommits) Marshal() ([]byte, error) {
+	enc := wire.NewEncoder(wire.TagRabinSecretCommits)
+	enc.Uint32(sc.Index)
+	enc.Points(sc.Commitments)
+	enc.Bytes(sc.SessionID)
+	enc.Bytes(sc.Signature)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a SecretCommits encoded with Marshal. It returns an error
+// if there are no commitments.
+func (sc *SecretCommits) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagRabinSecretCommits)
+	index := dec.Uint32()
+	commitments := dec.Points(suite)
+	sid := dec.Bytes()
+	signature := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	if len(commitments) == 0 {
+		return errors.New("dkg: no commitments in secret commits")
+	}
+	sc.Index = index
+	sc.Commitments = commitments
+	sc.SessionID = sid
+	sc.Signature = signature
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the ComplaintCommits. The
+// deal is embedded using its own Marshal encoding.
+func (cc *ComplaintCommits) Marshal() ([]byte, error) {
+	if cc.Deal == nil {
+		return nil, errors.New("dkg: nil deal in complaint")
+	}
+	buff, err := cc.Deal.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	enc := wire.NewEncoder(wire.TagRabinComplaintCommits)
+	enc.Uint32(cc.Index)
+	enc.Uint32(cc.DealerIndex)
+	enc.Bytes(buff)
+	enc.Bytes(cc.Signature)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a ComplaintCommits encoded with Marshal. It returns an
+// error if the deal is malformed or if it has not been issued to the
+// complaining participant.
+func (cc *ComplaintCommits) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagRabinComplaintCommits)
+	index := dec.Uint32()
+	dealerIndex := dec.Uint32()
+	buff := dec.Bytes()
+	signature := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	deal := new(vss.Deal)
+	if err := deal.Unmarshal(buff, suite); err != nil {
+		return err
+	}
+	switch {
+	case deal.SecShare == nil || deal.SecShare.V == nil ||
+		deal.RndShare == nil || deal.RndShare.V == nil:
+		return errors.New("dkg: missing share in complaint deal")
+	case deal.SecShare.I != index || deal.RndShare.I != index:
+		return errors.New("dkg: complaint deal issued to another participant")
+	case len(deal.Commitments) == 0 || uint32(len(deal.Commitments)) != deal.T:
+		return errors.New("dkg: invalid number of commitments in complaint deal")
+	}
+	cc.Index = index
+	cc.DealerIndex = dealerIndex
+	cc.Deal = deal
+	cc.Signature = signature
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the ReconstructCommits.
+func (rc *ReconstructCommits) Marshal() ([]byte, error) {
+	if rc.Share == nil {
+		return nil, errors.New("dkg: nil reconstruct share")
+	}
+	enc := wire.NewEncoder(wire.TagRabinReconstructCommits)
+	enc.Bytes(rc.SessionID)
+	enc.Uint32(rc.Index)
+	enc.Uint32(rc.DealerIndex)
+	enc.Uint32(rc.Share.I)
+	enc.Scalar(rc.Share.V)
+	enc.Bytes(rc.Signature)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a ReconstructCommits encoded with Marshal. It returns an
+// error if the share does not belong to the issuing participant.
+func (rc *ReconstructCommits) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagRabinReconstructCommits)
+	sid := dec.Bytes()
+	index := dec.Uint32()
+	dealerIndex := dec.Uint32()
+	shareIndex := dec.Uint32()
+	shareValue := dec.Scalar(suite)
+	signature := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	if shareIndex != index {
+		return errors.New("dkg: reconstruct share index does not match")
+	}
+	rc.SessionID = sid
+	rc.Index = index
+	rc.DealerIndex = dealerIndex
+	rc.Share = &share.PriShare{I: shareIndex, V: shareValue}
+	rc.Signature = signature
+	return nil
+}
@@ -0,0 +1,207 @@
+package dkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.dedis.ch/kyber/v4/internal/wire"
+	vss "go.dedis.ch/kyber/v4/share/vss/rabin"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+type wireMessage interface {
+	Marshal() ([]byte, error)
+}
+
+type wireMessages struct {
+	deal   *Deal
+	resp   *Response
+	justif *Justification
+	sc     *SecretCommits
+	cc     *ComplaintCommits
+	rc     *ReconstructCommits
+}
+
+func (m *wireMessages) all() []wireMessage {
+	return []wireMessage{m.deal, m.resp, m.justif, m.sc, m.cc, m.rc}
+}
+
+func genWireMessages(t testing.TB) *wireMessages {
+	fullExchange(t)
+	deals, err := dkgs[0].Deals()
+	require.NoError(t, err)
+	deal := dkgs[1].verifiers[0].Deal()
+	sc, err := dkgs[0].SecretCommits()
+	require.NoError(t, err)
+
+	cc := &ComplaintCommits{
+		Index:       1,
+		DealerIndex: 0,
+		Deal:        deal,
+	}
+	cc.Signature, err = schnorr.Sign(suite, dkgs[1].long, cc.Hash(suite))
+	require.NoError(t, err)
+
+	rc := &ReconstructCommits{
+		SessionID:   deal.SessionID,
+		Index:       1,
+		DealerIndex: 0,
+		Share:       deal.SecShare,
+	}
+	rc.Signature, err = schnorr.Sign(suite, dkgs[1].long, rc.Hash(suite))
+	require.NoError(t, err)
+
+	return &wireMessages{
+		deal: deals[1],
+		resp: &Response{
+			Index: 0,
+			Response: &vss.Response{
+				SessionID: deal.SessionID,
+				Index:     1,
+				Approved:  true,
+				Signature: randomBytes(64),
+			},
+		},
+		justif: &Justification{
+			Index: 0,
+			Justification: &vss.Justification{
+				SessionID: deal.SessionID,
+				Index:     1,
+				Deal:      deal,
+				Signature: randomBytes(64),
+			},
+		},
+		sc: sc,
+		cc: cc,
+		rc: rc,
+	}
+}
+
+func TestWireDeal(t *testing.T) {
+	m := genWireMessages(t)
+	buff, err := m.deal.Marshal()
+	require.NoError(t, err)
+	decoded := new(Deal)
+	require.NoError(t, decoded.Unmarshal(buff, suite))
+	// the decoded deal is accepted by its recipient
+	dkg := dkgGen()[1]
+	resp, err := dkg.ProcessDeal(decoded)
+	require.NoError(t, err)
+	require.True(t, resp.Response.Approved)
+
+	_, err = (&Deal{Index: 1}).Marshal()
+	require.Error(t, err)
+}
+
+func TestWireResponse(t *testing.T) {
+	m := genWireMessages(t)
+	buff, err := m.resp.Marshal()
+	require.NoError(t, err)
+	decoded := new(Response)
+	require.NoError(t, decoded.Unmarshal(buff))
+	require.Equal(t, m.resp, decoded)
+	require.ErrorIs(t, decoded.Unmarshal(buff[:len(buff)-1]), wire.ErrShort)
+}
+
+func TestWireJustification(t *testing.T) {
+	m := genWireMessages(t)
+	buff, err := m.justif.Marshal()
+	require.NoError(t, err)
+	decoded := new(Justification)
+	require.NoError(t, decoded.Unmarshal(buff, suite))
+	require.Equal(t, m.justif.Index, decoded.Index)
+	require.Equal(t, m.justif.Justification.Hash(suite), decoded.Justification.Hash(suite))
+	require.Equal(t, m.justif.Justification.Signature, decoded.Justification.Signature)
+}
+
+func TestWireSecretCommits(t *testing.T) {
+	m := genWireMessages(t)
+	buff, err := m.sc.Marshal()
+	require.NoError(t, err)
+	decoded := new(SecretCommits)
+	require.NoError(t, decoded.Unmarshal(buff, suite))
+	require.Equal(t, m.sc.Hash(suite), decoded.Hash(suite))
+	cc, err := dkgs[1].ProcessSecretCommits(decoded)
+	require.NoError(t, err)
+	require.Nil(t, cc)
+
+	require.ErrorIs(t, decoded.Unmarshal(append(buff, 0), suite), wire.ErrTrailing)
+
+	empty := &SecretCommits{Index: 0, SessionID: m.sc.SessionID}
+	buff, err = empty.Marshal()
+	require.NoError(t, err)
+	require.Error(t, decoded.Unmarshal(buff, suite))
+}
+
+func TestWireComplaintCommits(t *testing.T) {
+	m := genWireMessages(t)
+	buff, err := m.cc.Marshal()
+	require.NoError(t, err)
+	decoded := new(ComplaintCommits)
+	require.NoError(t, decoded.Unmarshal(buff, suite))
+	require.Equal(t, m.cc.Hash(suite), decoded.Hash(suite))
+	require.Equal(t, m.cc.Signature, decoded.Signature)
+
+	// deal given to another participant
+	bad := *m.cc
+	bad.Index = 2
+	buff, err = bad.Marshal()
+	require.NoError(t, err)
+	require.Error(t, decoded.Unmarshal(buff, suite))
+}
+
+func TestWireReconstructCommits(t *testing.T) {
+	m := genWireMessages(t)
+	buff, err := m.rc.Marshal()
+	require.NoError(t, err)
+	decoded := new(ReconstructCommits)
+	require.NoError(t, decoded.Unmarshal(buff, suite))
+	require.Equal(t, m.rc.Hash(suite), decoded.Hash(suite))
+	require.Equal(t, m.rc.SessionID, decoded.SessionID)
+	require.Equal(t, m.rc.Signature, decoded.Signature)
+
+	// wrong message type
+	require.ErrorIs(t, new(SecretCommits).Unmarshal(buff, suite), wire.ErrTag)
+
+	bad := *m.rc
+	bad.Index = 2
+	buff, err = bad.Marshal()
+	require.NoError(t, err)
+	require.Error(t, decoded.Unmarshal(buff, suite))
+}
+
+// requireStable checks that a decoded message encodes to a message that
+// decodes again to the same encoding.
+func requireStable(t *testing.T, m wireMessage, decode func([]byte) (wireMessage, error)) {
+	buff, err := m.Marshal()
+	require.NoError(t, err)
+	m2, err := decode(buff)
+	require.NoError(t, err)
+	buff2, err := m2.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, buff, buff2)
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	for _, m := range genWireMessages(f).all() {
+		buff, err := m.Marshal()
+		require.NoError(f, err)
+		f.Add(buff)
+	}
+	decoders := []func([]byte) (wireMessage, error){
+		func(b []byte) (wireMessage, error) { d := new(Deal); return d, d.Unmarshal(b, suite) },
+		func(b []byte) (wireMessage, error) { r := new(Response); return r, r.Unmarshal(b) },
+		func(b []byte) (wireMessage, error) { j := new(Justification); return j, j.Unmarshal(b, suite) },
+		func(b []byte) (wireMessage, error) { sc := new(SecretCommits); return sc, sc.Unmarshal(b, suite) },
+		func(b []byte) (wireMessage, error) { cc := new(ComplaintCommits); return cc, cc.Unmarshal(b, suite) },
+		func(b []byte) (wireMessage, error) { rc := new(ReconstructCommits); return rc, rc.Unmarshal(b, suite) },
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, decode := range decoders {
+			if m, err := decode(data); err == nil {
+				requireStable(t, m, decode)
+			}
+		}
+	})
+}
@@ -0,0 +1,300 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// Lagrange holds the Lagrange coefficients of a fixed set of share indices for
+// the interpolation at a given point. Computing them is the expensive part of
+// an interpolation, so a Lagrange can be reused for all the recoveries made
+// with the same set of signers, e.g. for every threshold signature issued by
+// the same quorum.
+type Lagrange struct {
+	g       kyber.Group
+	indices []uint32
+	coeffs  map[uint32]kyber.Scalar
+}
+
+// NewLagrange returns the Lagrange coefficients of the given share indices for
+// the interpolation at x = 0, i.e. for recovering the secret.
+func NewLagrange(g kyber.Group, indices []uint32) (*Lagrange, error) {
+	return NewLagrangeAt(g, indices, g.Scalar().Zero())
+}
+
+// NewLagrangeAt returns the Lagrange coefficients of the given share indices
+// for the interpolation at x. Note that the x-coordinate of the share of index
+// i is i+1. The indices must be distinct.
+func NewLagrangeAt(g kyber.Group, indices []uint32, x kyber.Scalar) (*Lagrange, error) {
+	if len(indices) == 0 {
+		return nil, errors.New("share: no indices to interpolate")
+	}
+	sorted := slices.Clone(indices)
+	slices.Sort(sorted)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return nil, fmt.Errorf("share: duplicate index %d", sorted[i])
+		}
+	}
+	coeffs := lagrangeCoefficients(g, sorted, x)
+	l := &Lagrange{
+		g:       g,
+		indices: sorted,
+		coeffs:  make(map[uint32]kyber.Scalar, len(sorted)),
+	}
+	for i, idx := range sorted {
+		l.coeffs[idx] = coeffs[i]
+	}
+	return l, nil
+}
+
+// Indices returns the sorted share indices of the interpolation.
+func (l *Lagrange) Indices() []uint32 {
+	return slices.Clone(l.indices)
+}
+
+// Coefficient returns the Lagrange coefficient of the share of index i, or
+// nil if i is not part of the interpolation.
+func (l *Lagrange) Coefficient(i uint32) kyber.Scalar {
+	c, ok := l.coeffs[i]
+	if !ok {
+		return nil
+	}
+	return c.Clone()
+}
+
+// Interpolate returns the value at the interpolation point of the polynomial
+// defined by the shares. There must be exactly one share for every index of
+// the Lagrange; shares with another index are ignored.
+func (l *Lagrange) Interpolate(shares []*PriShare) (kyber.Scalar, error) {
+	acc := l.g.Scalar().Zero()
+	tmp := l.g.Scalar()
+	seen := make(map[uint32]bool, len(l.indices))
+	for _, s := range shares {
+		if s == nil || s.V == nil {
+			continue
+		}
+		c, ok := l.coeffs[s.I]
+		if !ok {
+			continue
+		}
+		if seen[s.I] {
+			return nil, fmt.Errorf("share: duplicate share %d", s.I)
+		}
+		seen[s.I] = true
+		acc.Add(acc, tmp.Mul(c, s.V))
+	}
+	if len(seen) != len(l.indices) {
+		return nil, errors.New("share: missing shares to interpolate")
+	}
+	return acc, nil
+}
+
+// InterpolateCommit is the public version of Interpolate.
+func (l *Lagrange) InterpolateCommit(shares []*PubShare) (kyber.Point, error) {
+	acc := l.g.Point().Null()
+	tmp := l.g.Point()
+	seen := make(map[uint32]bool, len(l.indices))
+	for _, s := range shares {
+		if s == nil || s.V == nil {
+			continue
+		}
+		c, ok := l.coeffs[s.I]
+		if !ok {
+			continue
+		}
+		if seen[s.I] {
+			return nil, fmt.Errorf("share: duplicate share %d", s.I)
+		}
+		seen[s.I] = true
+		acc.Add(acc, tmp.Mul(c, s.V))
+	}
+	if len(seen) != len(l.indices) {
+		return nil, errors.New("share: missing shares to interpolate")
+	}
+	return acc, nil
+}
+
+// lagrangeCoefficients returns the Lagrange coefficients at x of the sorted
+// and distinct indices, i.e. for each i the product of (x - x_j) / (x_i - x_j)
+// for all j != i. The numerators are computed in linear time with prefix and
+// suffix products, and the denominators with lagrangeInvDenominators, so that
+// a single inversion is needed.
+func lagrangeCoefficients(g kyber.Group, indices []uint32, x kyber.Scalar) []kyber.Scalar {
+	t := len(indices)
+	xs := xCoordinates(g, indices)
+	// prefix[i] is the product of (x - x_j) for j < i and suffix[i] for j >= i
+	prefix := make([]kyber.Scalar, t+1)
+	suffix := make([]kyber.Scalar, t+1)
+	prefix[0] = g.Scalar().One()
+	suffix[t] = g.Scalar().One()
+	tmp := g.Scalar()
+	for i := range t {
+		prefix[i+1] = g.Scalar().Mul(prefix[i], tmp.Sub(x, xs[i]))
+	}
+	for i := t - 1; i >= 0; i-- {
+		suffix[i] = g.Scalar().Mul(suffix[i+1], tmp.Sub(x, xs[i]))
+	}
+
+	coeffs := lagrangeInvDenominators(g, indices, xs)
+	for i := range coeffs {
+		coeffs[i].Mul(coeffs[i], prefix[i])
+		coeffs[i].Mul(coeffs[i], suffix[i+1])
+	}
+	return coeffs
+}
+
+// lagrangeInvDenominators returns for each i the inverse of the product of
+// (x_i - x_j) for all j != i, where x_i = indices[i] + 1.
+//
+// When the indices cover most of the domain 1..N, with N the largest
+// x-coordinate, the product over the whole domain has the closed form
+// (x_i - 1)! (-1)^(N - x_i) (N - x_i)!, so only the m missing x-coordinates
+// have to be divided out. This takes O(N + t*m) multiplications and a single
+// inversion instead of O(t^2) for the direct computation, which is used
+// otherwise, with a batch inversion.
+func lagrangeInvDenominators(g kyber.Group, indices []uint32, xs []kyber.Scalar) []kyber.Scalar {
+	t := len(indices)
+	n := uint64(indices[t-1]) + 1
+	missing := n - uint64(t)
+	if missing >= uint64(t) {
+		dens := make([]kyber.Scalar, t)
+		tmp := g.Scalar()
+		for i := range dens {
+			dens[i] = g.Scalar().One()
+			for j := range xs {
+				if i != j {
+					dens[i].Mul(dens[i], tmp.Sub(xs[i], xs[j]))
+				}
+			}
+		}
+		batchInvert(g, dens)
+		return dens
+	}
+
+	// factorials and inverse factorials up to n
+	fact := make([]kyber.Scalar, n+1)
+	fact[0] = g.Scalar().One()
+	for k := uint64(1); k <= n; k++ {
+		fact[k] = g.Scalar().Mul(fact[k-1], g.Scalar().SetInt64(int64(k)))
+	}
+	invFact := make([]kyber.Scalar, n+1)
+	invFact[n] = g.Scalar().Inv(fact[n])
+	for k := n; k > 0; k-- {
+		invFact[k-1] = g.Scalar().Mul(invFact[k], g.Scalar().SetInt64(int64(k)))
+	}
+
+	// x-coordinates of the domain that are not interpolated
+	gaps := make([]kyber.Scalar, 0, missing)
+	next := 0
+	for k := uint64(1); k <= n; k++ {
+		if next < t && uint64(indices[next])+1 == k {
+			next++
+			continue
+		}
+		gaps = append(gaps, g.Scalar().SetInt64(int64(k)))
+	}
+
+	invs := make([]kyber.Scalar, t)
+	tmp := g.Scalar()
+	for i, idx := range indices {
+		a := uint64(idx) + 1
+		// 1 / (x_i - 1)! (N - x_i)! times the missing factors
+		inv := g.Scalar().Mul(invFact[a-1], invFact[n-a])
+		if (n-a)%2 == 1 {
+			inv.Neg(inv)
+		}
+		for _, k := range gaps {
+			inv.Mul(inv, tmp.Sub(xs[i], k))
+		}
+		invs[i] = inv
+	}
+	return invs
+}
+
+// batchInvert replaces each scalar of the slice by its inverse, using a
+// single inversion (Montgomery's trick). The scalars must be non-zero.
+func batchInvert(g kyber.Group, scalars []kyber.Scalar) {
+	if len(scalars) == 0 {
+		return
+	}
+	// acc[i] is the product of the scalars up to i included
+	acc := make([]kyber.Scalar, len(scalars))
+	acc[0] = scalars[0].Clone()
+	for i := 1; i < len(scalars); i++ {
+		acc[i] = g.Scalar().Mul(acc[i-1], scalars[i])
+	}
+	inv := g.Scalar().Inv(acc[len(acc)-1])
+	for i := len(scalars) - 1; i > 0; i-- {
+		// inv is the inverse of acc[i]
+		s := scalars[i].Clone()
+		scalars[i].Mul(inv, acc[i-1])
+		inv.Mul(inv, s)
+	}
+	scalars[0].Set(inv)
+}
+
+// xCoordinates returns the x-coordinates of the shares of the given indices.
+func xCoordinates(g kyber.Group, indices []uint32) []kyber.Scalar {
+	xs := make([]kyber.Scalar, len(indices))
+	for i, idx := range indices {
+		xs[i] = g.Scalar().SetInt64(1 + int64(idx))
+	}
+	return xs
+}
+
+// sortedIndices returns the sorted keys of the map returned by xyScalar or
+// xyCommit.
+func sortedIndices[V any](m map[uint32]V) []uint32 {
+	indices := make([]uint32, 0, len(m))
+	for i := range m {
+		indices = append(indices, i)
+	}
+	slices.Sort(indices)
+	return indices
+}
+
+// lagrangePolys calls fn with the coefficients of the Lagrange basis
+// polynomial L_j of each of the sorted and distinct indices. Every L_j is
+// obtained by dividing the master polynomial, the product of (X - x_j) for
+// all j, by (X - x_j), which takes O(t^2) operations overall instead of O(t^3)
+// when the basis polynomials are multiplied out one by one. The slice given
+// to fn is reused between calls.
+func lagrangePolys(g kyber.Group, indices []uint32, fn func(j int, basis []kyber.Scalar)) {
+	t := len(indices)
+	xs := xCoordinates(g, indices)
+
+	// master[k] is the coefficient of X^k of the master polynomial
+	master := make([]kyber.Scalar, t+1)
+	master[0] = g.Scalar().One()
+	for i := 1; i <= t; i++ {
+		master[i] = g.Scalar().Zero()
+	}
+	tmp := g.Scalar()
+	for j, x := range xs {
+		// multiply by (X - x)
+		for k := j + 1; k > 0; k-- {
+			master[k].Sub(master[k-1], tmp.Mul(master[k], x))
+		}
+		master[0].Mul(master[0], tmp.Neg(x))
+	}
+
+	invDens := lagrangeInvDenominators(g, indices, xs)
+	basis := make([]kyber.Scalar, t)
+	for k := range basis {
+		basis[k] = g.Scalar()
+	}
+	q := g.Scalar()
+	for j, x := range xs {
+		// synthetic division of the master polynomial by (X - x)
+		q.Set(master[t])
+		for k := t - 1; k >= 0; k-- {
+			basis[k].Mul(q, invDens[j])
+			q.Add(master[k], tmp.Mul(q, x))
+		}
+		fn(j, basis)
+	}
+}
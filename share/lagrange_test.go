@@ -0,0 +1,196 @@
+package share
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+)
+
+// naiveCoefficient computes the Lagrange coefficient at x of index i over the
+// given indices with the textbook formula.
+func naiveCoefficient(g kyber.Group, i uint32, indices []uint32, x kyber.Scalar) kyber.Scalar {
+	xi := g.Scalar().SetInt64(1 + int64(i))
+	num := g.Scalar().One()
+	den := g.Scalar().One()
+	for _, j := range indices {
+		if j == i {
+			continue
+		}
+		xj := g.Scalar().SetInt64(1 + int64(j))
+		num.Mul(num, g.Scalar().Sub(x, xj))
+		den.Mul(den, g.Scalar().Sub(xi, xj))
+	}
+	return num.Div(num, den)
+}
+
+func TestLagrangeCoefficients(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	rnd := rand.New(rand.NewSource(42))
+	for _, tc := range []struct {
+		n, t int
+	}{
+		{1, 1},
+		{5, 5},   // full domain
+		{50, 45}, // dense, through the factorials
+		{50, 10}, // sparse, through the direct computation
+		{300, 299},
+	} {
+		indices := make([]uint32, 0, tc.t)
+		for _, i := range rnd.Perm(tc.n)[:tc.t] {
+			indices = append(indices, uint32(i))
+		}
+		for _, x := range []kyber.Scalar{
+			g.Scalar().Zero(),
+			g.Scalar().SetInt64(int64(tc.n) + 3),
+			g.Scalar().Pick(g.RandomStream()),
+		} {
+			l, err := NewLagrangeAt(g, indices, x)
+			require.NoError(test, err)
+			for _, i := range indices {
+				require.True(test, naiveCoefficient(g, i, indices, x).Equal(l.Coefficient(i)),
+					"n=%d t=%d i=%d", tc.n, tc.t, i)
+			}
+		}
+	}
+}
+
+func TestLagrangeInterpolate(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := uint32(20)
+	t := uint32(7)
+	poly := NewPriPoly(g, t, nil, g.RandomStream())
+	pub := poly.Commit(nil)
+	shares := poly.Shares(n)
+	pubShares := pub.Shares(n)
+
+	// the same coefficients are reused for many secrets of the same signers
+	indices := []uint32{19, 2, 3, 11, 5, 8, 0}
+	l, err := NewLagrange(g, indices)
+	require.NoError(test, err)
+	require.Equal(test, []uint32{0, 2, 3, 5, 8, 11, 19}, l.Indices())
+	require.Nil(test, l.Coefficient(1))
+	for range 3 {
+		poly := NewPriPoly(g, t, nil, g.RandomStream())
+		sec, err := l.Interpolate(poly.Shares(n))
+		require.NoError(test, err)
+		require.True(test, poly.Secret().Equal(sec))
+	}
+
+	sec, err := l.Interpolate(shares)
+	require.NoError(test, err)
+	require.True(test, poly.Secret().Equal(sec))
+	commit, err := l.InterpolateCommit(pubShares)
+	require.NoError(test, err)
+	require.True(test, pub.Commit().Equal(commit))
+
+	// interpolation at a share
+	l, err = NewLagrangeAt(g, indices, g.Scalar().SetInt64(2))
+	require.NoError(test, err)
+	v, err := l.Interpolate(shares)
+	require.NoError(test, err)
+	require.True(test, shares[1].V.Equal(v))
+
+	// missing and duplicate shares
+	_, err = l.Interpolate(shares[:10])
+	require.Error(test, err)
+	_, err = l.Interpolate(append(shares, shares[0]))
+	require.Error(test, err)
+	_, err = l.InterpolateCommit(pubShares[:10])
+	require.Error(test, err)
+
+	_, err = NewLagrange(g, nil)
+	require.Error(test, err)
+	_, err = NewLagrange(g, []uint32{1, 2, 1})
+	require.Error(test, err)
+}
+
+func TestBatchInvert(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	scalars := make([]kyber.Scalar, 10)
+	expected := make([]kyber.Scalar, len(scalars))
+	for i := range scalars {
+		scalars[i] = g.Scalar().Pick(g.RandomStream())
+		expected[i] = g.Scalar().Inv(scalars[i])
+	}
+	batchInvert(g, scalars)
+	for i := range scalars {
+		require.True(test, expected[i].Equal(scalars[i]))
+	}
+	batchInvert(g, nil)
+}
+
+func TestRecoverLarge(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := uint32(90)
+	t := uint32(50)
+	poly := NewPriPoly(g, t, nil, g.RandomStream())
+	pub := poly.Commit(nil)
+	shares := poly.Shares(n)
+	pubShares := pub.Shares(n)
+	// drop some shares so that the indices are not contiguous
+	for i := 0; i < int(n); i += 3 {
+		shares[i] = nil
+		pubShares[i] = nil
+	}
+
+	sec, err := RecoverSecret(g, shares, t, n)
+	require.NoError(test, err)
+	require.True(test, poly.Secret().Equal(sec))
+
+	recovered, err := RecoverPriPoly(g, shares, t, n)
+	require.NoError(test, err)
+	require.True(test, poly.Equal(recovered))
+
+	recoveredPub, err := RecoverPubPoly(g, pubShares, t, n)
+	require.NoError(test, err)
+	require.True(test, pub.Equal(recoveredPub))
+}
+
+func BenchmarkRecoverSecret(b *testing.B) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	for _, n := range []uint32{100, 1000, 4000} {
+		t := n/2 + 1
+		shares := NewPriPoly(g, t, nil, g.RandomStream()).Shares(n)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				_, _ = RecoverSecret(g, shares, t, n)
+			}
+		})
+	}
+}
+
+func BenchmarkLagrangeInterpolate(b *testing.B) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	for _, n := range []uint32{100, 1000, 4000} {
+		t := n/2 + 1
+		shares := NewPriPoly(g, t, nil, g.RandomStream()).Shares(n)
+		indices := make([]uint32, t)
+		for i := range indices {
+			indices[i] = uint32(i)
+		}
+		l, err := NewLagrange(g, indices)
+		require.NoError(b, err)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				_, _ = l.Interpolate(shares)
+			}
+		})
+	}
+}
+
+func BenchmarkRecoverPriPoly(b *testing.B) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	for _, n := range []uint32{100, 1000} {
+		t := n/2 + 1
+		shares := NewPriPoly(g, t, nil, g.RandomStream()).Shares(n)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				_, _ = RecoverPriPoly(g, shares, t, n)
+			}
+		})
+	}
+}
@@ -6,7 +6,9 @@
 // scheme allows a committer to commit to a secret sharing polynomial so that
 // a verifier can check the claimed evaluations of the committed polynomial.
 // Both schemes of this package are core building blocks for more advanced
-// secret sharing techniques.
+// secret sharing techniques. Shares and commitments can be interpolated at any
+// point, which allows, e.g., to repair the lost share of a participant. Byte
+// strings of any length can be shared with SplitBytes.
 package share
 
 import (
@@ -92,6 +94,17 @@ func (p *PriPoly) Eval(i uint32) *PriShare {
 	return &PriShare{i, v}
 }
 
+// EvalAt computes p(x) for an arbitrary x. Note that the share of index i is
+// p(i+1), see Eval.
+func (p *PriPoly) EvalAt(x kyber.Scalar) kyber.Scalar {
+	v := p.g.Scalar().Zero()
+	for j := int64(p.Threshold()) - 1; j >= 0; j-- {
+		v.Mul(v, x)
+		v.Add(v, p.coeffs[j])
+	}
+	return v
+}
+
 // Shares creates a list of n private shares p(1),...,p(n).
 func (p *PriPoly) Shares(n uint32) []*PriShare {
 	shares := make([]*PriShare, n)
@@ -180,30 +193,37 @@ func (p *PriPoly) Coefficients() []kyber.Scalar {
 // RecoverSecret reconstructs the shared secret p(0) from a list of private
 // shares using Lagrange interpolation.
 func RecoverSecret(g kyber.Group, shares []*PriShare, t, n uint32) (kyber.Scalar, error) {
-	x, y := xyScalar(g, shares, t, n)
-	if uint32(len(x)) < t {
+	return RecoverScalarAt(g, shares, t, n, g.Scalar().Zero())
+}
+
+// RecoverShareAt reconstructs the private share of index i, i.e. p(i+1), from
+// a list of private shares using Lagrange interpolation. This allows to
+// recover the lost share of a participant, see also the share repair protocol
+// which does it without revealing the shares of the helpers.
+func RecoverShareAt(g kyber.Group, shares []*PriShare, t, n, i uint32) (*PriShare, error) {
+	v, err := RecoverScalarAt(g, shares, t, n, g.Scalar().SetInt64(1+int64(i)))
+	if err != nil {
+		return nil, err
+	}
+	return &PriShare{I: i, V: v}, nil
+}
+
+// RecoverScalarAt reconstructs p(x) for an arbitrary x from a list of private
+// shares using Lagrange interpolation. RecoverSecret is the special case
+// x = 0.
+func RecoverScalarAt(g kyber.Group, shares []*PriShare, t, n uint32, x kyber.Scalar) (kyber.Scalar, error) {
+	xs, ys := xyScalar(g, shares, t, n)
+	if uint32(len(xs)) < t {
 		return nil, errors.New("share: not enough shares to recover secret")
 	}
 
+	indices := sortedIndices(xs)
+	coeffs := lagrangeCoefficients(g, indices, x)
 	acc := g.Scalar().Zero()
-	num := g.Scalar()
-	den := g.Scalar()
 	tmp := g.Scalar()
-
-	for i, xi := range x {
-		yi := y[i]
-		num.Set(yi)
-		den.One()
-		for j, xj := range x {
-			if i == j {
-				continue
-			}
-			num.Mul(num, xj)
-			den.Mul(den, tmp.Sub(xj, xi))
-		}
-		acc.Add(acc, num.Div(num, den))
+	for i, idx := range indices {
+		acc.Add(acc, tmp.Mul(coeffs[i], ys[idx]))
 	}
-
 	return acc, nil
 }
 
@@ -244,14 +264,6 @@ func xyScalar(g kyber.Group, shares []*PriShare, t, n uint32) (map[uint32]kyber.
 	return x, y
 }
 
-func minusConst(g kyber.Group, c kyber.Scalar) *PriPoly {
-	neg := g.Scalar().Neg(c)
-	return &PriPoly{
-		g:      g,
-		coeffs: []kyber.Scalar{neg, g.Scalar().One()},
-	}
-}
-
 // RecoverPriPoly takes a list of shares and the parameters t and n to
 // reconstruct the secret polynomial completely, i.e., all private
 // coefficients.  It is up to the caller to make sure that there are enough
@@ -263,28 +275,21 @@ func RecoverPriPoly(g kyber.Group, shares []*PriShare, t, n uint32) (*PriPoly, e
 		return nil, errors.New("share: not enough shares to recover private polynomial")
 	}
 
-	var accPoly *PriPoly
-	var err error
+	indices := sortedIndices(x)
+	coeffs := make([]kyber.Scalar, t)
+	for i := range coeffs {
+		coeffs[i] = g.Scalar().Zero()
+	}
+	tmp := g.Scalar()
 	// Notations follow the Wikipedia article on Lagrange interpolation
 	// https://en.wikipedia.org/wiki/Lagrange_polynomial
-	for j := range x {
-		basis := lagrangeBasis(g, j, x)
-		for i := range basis.coeffs {
-			basis.coeffs[i] = basis.coeffs[i].Mul(basis.coeffs[i], y[j])
-		}
-
-		if accPoly == nil {
-			accPoly = basis
-			continue
-		}
-
+	lagrangePolys(g, indices, func(j int, basis []kyber.Scalar) {
 		// add all L_j * y_j together
-		accPoly, err = accPoly.Add(basis)
-		if err != nil {
-			return nil, err
+		for k := range coeffs {
+			coeffs[k].Add(coeffs[k], tmp.Mul(basis[k], y[indices[j]]))
 		}
-	}
-	return accPoly, nil
+	})
+	return &PriPoly{g: g, coeffs: coeffs}, nil
 }
 
 func (p *PriPoly) String() string {
@@ -347,6 +352,17 @@ func (p *PubPoly) Eval(i uint32) *PubShare {
 	return &PubShare{i, v}
 }
 
+// EvalAt computes the commitment p(x) for an arbitrary x. Note that the share
+// of index i is p(i+1), see Eval.
+func (p *PubPoly) EvalAt(x kyber.Scalar) kyber.Point {
+	v := p.g.Point().Null()
+	for j := p.Threshold() - 1; j >= 0; j-- {
+		v.Mul(x, v)
+		v.Add(v, p.commits[j])
+	}
+	return v
+}
+
 // Shares creates a list of n public commitment shares p(1),...,p(n).
 func (p *PubPoly) Shares(n uint32) []*PubShare {
 	shares := make([]*PubShare, n)
@@ -447,32 +463,36 @@ func xyCommit(g kyber.Group, shares []*PubShare, t, n uint32) (map[uint32]kyber.
 // RecoverCommit reconstructs the secret commitment p(0) from a list of public
 // shares using Lagrange interpolation.
 func RecoverCommit(g kyber.Group, shares []*PubShare, t, n uint32) (kyber.Point, error) {
-	x, y := xyCommit(g, shares, t, n)
-	if uint32(len(x)) < t {
-		return nil, errors.New("share: not enough good public shares to reconstruct secret commitment")
+	return RecoverPointAt(g, shares, t, n, g.Scalar().Zero())
+}
+
+// RecoverCommitAt reconstructs the public share of index i, i.e. p(i+1), from
+// a list of public shares using Lagrange interpolation.
+func RecoverCommitAt(g kyber.Group, shares []*PubShare, t, n, i uint32) (*PubShare, error) {
+	v, err := RecoverPointAt(g, shares, t, n, g.Scalar().SetInt64(1+int64(i)))
+	if err != nil {
+		return nil, err
 	}
+	return &PubShare{I: i, V: v}, nil
+}
 
-	num := g.Scalar()
-	den := g.Scalar()
-	tmp := g.Scalar()
-	Acc := g.Point().Null()
-	Tmp := g.Point()
-
-	for i, xi := range x {
-		num.One()
-		den.One()
-		for j, xj := range x {
-			if i == j {
-				continue
-			}
-			num.Mul(num, xj)
-			den.Mul(den, tmp.Sub(xj, xi))
-		}
-		Tmp.Mul(num.Div(num, den), y[i])
-		Acc.Add(Acc, Tmp)
+// RecoverPointAt reconstructs the commitment p(x) for an arbitrary x from a
+// list of public shares using Lagrange interpolation. RecoverCommit is the
+// special case x = 0.
+func RecoverPointAt(g kyber.Group, shares []*PubShare, t, n uint32, x kyber.Scalar) (kyber.Point, error) {
+	xs, ys := xyCommit(g, shares, t, n)
+	if uint32(len(xs)) < t {
+		return nil, errors.New("share: not enough good public shares to reconstruct secret commitment")
 	}
 
-	return Acc, nil
+	indices := sortedIndices(xs)
+	coeffs := lagrangeCoefficients(g, indices, x)
+	acc := g.Point().Null()
+	tmp := g.Point()
+	for i, idx := range indices {
+		acc.Add(acc, tmp.Mul(coeffs[i], ys[idx]))
+	}
+	return acc, nil
 }
 
 // RecoverPubPoly reconstructs the full public polynomial from a set of public
@@ -483,54 +503,17 @@ func RecoverPubPoly(g kyber.Group, shares []*PubShare, t, n uint32) (*PubPoly, e
 		return nil, errors.New("share: not enough good public shares to reconstruct secret commitment")
 	}
 
-	var accPoly *PubPoly
-	var err error
-
-	for j := range x {
-		basis := lagrangeBasis(g, j, x)
-
-		// compute the L_j * y_j polynomial in point space
-		tmp := basis.Commit(y[j])
-		if accPoly == nil {
-			accPoly = tmp
-			continue
-		}
-
-		// add all L_j * y_j together
-		accPoly, err = accPoly.Add(tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return accPoly, nil
-
-}
-
-// lagrangeBasis returns a PriPoly containing the Lagrange coefficients for the
-// i-th position. xs is a mapping between the indices and the values that the
-// interpolation is using, computed with xyScalar().
-func lagrangeBasis(g kyber.Group, i uint32, xs map[uint32]kyber.Scalar) *PriPoly {
-	var basis = &PriPoly{
-		g:      g,
-		coeffs: []kyber.Scalar{g.Scalar().One()},
+	indices := sortedIndices(x)
+	commits := make([]kyber.Point, t)
+	for i := range commits {
+		commits[i] = g.Point().Null()
 	}
-	// compute lagrange basis l_j
-	den := g.Scalar().One()
-	var acc = g.Scalar().One()
-	for m, xm := range xs {
-		if i == m {
-			continue
+	tmp := g.Point()
+	lagrangePolys(g, indices, func(j int, basis []kyber.Scalar) {
+		// add all L_j * y_j together in point space
+		for k := range commits {
+			commits[k].Add(commits[k], tmp.Mul(basis[k], y[indices[j]]))
 		}
-		basis = basis.Mul(minusConst(g, xm))
-		den.Sub(xs[i], xm) // Compute den = xi - xm
-		den.Inv(den)       // Compute den = 1 / den
-		acc.Mul(acc, den)  // Compute acc = acc * den
-	}
-
-	// multiply all coefficients by the denominator
-	for i := range basis.coeffs {
-		basis.coeffs[i] = basis.coeffs[i].Mul(basis.coeffs[i], acc)
-	}
-	return basis
+	})
+	return &PubPoly{g: g, commits: commits}, nil
 }
@@ -545,3 +545,62 @@ func TestRefreshDKG(test *testing.T) {
 	// Check that the secret and the corresponding (old) public commit match
 	require.True(test, g.Point().Mul(refreshedPriPoly.Secret(), nil).Equal(dkgCommits[0]))
 }
+
+func TestRecoverShareAt(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := uint32(10)
+	t := uint32(6)
+	poly := NewPriPoly(g, t, nil, g.RandomStream())
+	pub := poly.Commit(nil)
+	shares := poly.Shares(n)
+	pubShares := pub.Shares(n)
+
+	// forget the share we want to recover
+	lost := uint32(3)
+	shares[lost] = nil
+	pubShares[lost] = nil
+
+	sh, err := RecoverShareAt(g, shares, t, n, lost)
+	require.NoError(test, err)
+	require.Equal(test, lost, sh.I)
+	require.True(test, poly.Eval(lost).V.Equal(sh.V))
+	require.True(test, pub.Check(sh))
+
+	// a share outside of the original range
+	sh, err = RecoverShareAt(g, shares, t, n, 2*n)
+	require.NoError(test, err)
+	require.True(test, poly.Eval(2*n).V.Equal(sh.V))
+
+	pubSh, err := RecoverCommitAt(g, pubShares, t, n, lost)
+	require.NoError(test, err)
+	require.Equal(test, lost, pubSh.I)
+	require.True(test, pub.Eval(lost).V.Equal(pubSh.V))
+
+	// at x = 0, it is the secret
+	zero := g.Scalar().Zero()
+	secret, err := RecoverScalarAt(g, shares, t, n, zero)
+	require.NoError(test, err)
+	require.True(test, poly.Secret().Equal(secret))
+	commit, err := RecoverPointAt(g, pubShares, t, n, zero)
+	require.NoError(test, err)
+	require.True(test, pub.Commit().Equal(commit))
+
+	// arbitrary scalar
+	x := g.Scalar().Pick(g.RandomStream())
+	v, err := RecoverScalarAt(g, shares, t, n, x)
+	require.NoError(test, err)
+	require.True(test, poly.EvalAt(x).Equal(v))
+	p, err := RecoverPointAt(g, pubShares, t, n, x)
+	require.NoError(test, err)
+	require.True(test, pub.EvalAt(x).Equal(p))
+	require.True(test, g.Point().Mul(v, nil).Equal(p))
+
+	// the x-coordinate of the share of index i is i+1
+	require.True(test, poly.EvalAt(g.Scalar().SetInt64(5)).Equal(poly.Eval(4).V))
+
+	// not enough shares
+	_, err = RecoverShareAt(g, shares[:t-1], t, n, lost)
+	require.Error(test, err)
+	_, err = RecoverCommitAt(g, pubShares[:t-1], t, n, lost)
+	require.Error(test, err)
+}
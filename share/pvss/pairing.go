@@ -0,0 +1,125 @@
+package pvss
+
+import (
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/pairing"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// On pairing suites, the encrypted shares can be verified without any
+// zero-knowledge proof, as in the pairing-based version of SCRAPE. The dealer
+// publishes the commitments s_i*G1 to the shares, which are checked with the
+// dual code as in VerifyEncShareScrape, and the encrypted shares s_i*X_i for
+// the public keys X_i = x_i*G2 of the trustees, which are checked with the
+// pairing equation e(s_i*G1, X_i) == e(G1, s_i*X_i). The secret recovered from
+// the decrypted shares is s*G2.
+
+// EncSharesPairing creates the encrypted shares of the secret for the public
+// keys X in G2, using the sharing threshold t. It returns the encrypted shares
+// and the commitments to the shares in G1.
+func EncSharesPairing(
+	suite pairing.Suite,
+	X []kyber.Point,
+	secret kyber.Scalar,
+	t uint32,
+) (encShares []*share.PubShare, commits []kyber.Point, err error) {
+	n := uint32(len(X))
+	if t == 0 || t > n {
+		return nil, nil, fmt.Errorf("invalid threshold %d for %d shares", t, n)
+	}
+	priPoly := share.NewPriPoly(suite.G1(), t, secret, suite.RandomStream())
+	priShares := priPoly.Shares(n)
+
+	encShares = make([]*share.PubShare, n)
+	commits = make([]kyber.Point, n)
+	for i, s := range priShares {
+		commits[i] = suite.G1().Point().Mul(s.V, nil)
+		encShares[i] = &share.PubShare{I: s.I, V: suite.G2().Point().Mul(s.V, X[i])}
+	}
+	return encShares, commits, nil
+}
+
+// VerifyEncSharePairing checks the encrypted share against the commitment to
+// the share and the public key X of the trustee.
+func VerifyEncSharePairing(suite pairing.Suite, X, commit kyber.Point, encShare *share.PubShare) error {
+	if !suite.ValidatePairing(commit, X, suite.G1().Point().Base(), encShare.V) {
+		return fmt.Errorf("didn't verify: %w", ErrEncVerification)
+	}
+	return nil
+}
+
+// VerifyEncShareBatchPairing verifies the encrypted shares of a dealer against
+// the commitments to the shares, with a threshold of t. The encrypted shares
+// must be given in the order of their indices, starting from 0. It returns an
+// error wrapping ErrScrapeVerification if the commitments are not shares of a
+// polynomial of degree less than t, and otherwise the valid encrypted shares
+// together with the corresponding public keys.
+func VerifyEncShareBatchPairing(
+	suite pairing.Suite,
+	X, commits []kyber.Point,
+	t uint32,
+	encShares []*share.PubShare,
+) ([]kyber.Point, []*share.PubShare, error) {
+	if len(X) != len(commits) || len(commits) != len(encShares) {
+		return nil, nil, fmt.Errorf("didn't verify: %w", ErrDifferentLengths)
+	}
+	for i, s := range encShares {
+		if s.I != uint32(i) {
+			return nil, nil, fmt.Errorf("didn't verify: encrypted share %d has index %d", i, s.I)
+		}
+	}
+	if !checkDualCode(suite.G1(), suite.RandomStream(), commits, t) {
+		return nil, nil, fmt.Errorf("didn't verify: %w", ErrScrapeVerification)
+	}
+
+	var K []kyber.Point     // good public keys
+	var E []*share.PubShare // good encrypted shares
+	for i := range X {
+		if err := VerifyEncSharePairing(suite, X[i], commits[i], encShares[i]); err == nil {
+			K = append(K, X[i])
+			E = append(E, encShares[i])
+		}
+	}
+	return K, E, nil
+}
+
+// DecSharePairing decrypts the encrypted share with the private key x of the
+// trustee, giving s_i*G2.
+func DecSharePairing(suite pairing.Suite, x kyber.Scalar, encShare *share.PubShare) *share.PubShare {
+	g2 := suite.G2()
+	return &share.PubShare{I: encShare.I, V: g2.Point().Mul(g2.Scalar().Inv(x), encShare.V)}
+}
+
+// VerifyDecSharePairing checks the decrypted share against the commitment to
+// the share.
+func VerifyDecSharePairing(suite pairing.Suite, commit kyber.Point, decShare *share.PubShare) error {
+	if !suite.ValidatePairing(commit, suite.G2().Point().Base(), suite.G1().Point().Base(), decShare.V) {
+		return fmt.Errorf("didn't verify: %w", ErrDecVerification)
+	}
+	return nil
+}
+
+// RecoverSecretPairing verifies the decrypted shares against the commitments
+// to the shares and recovers the secret s*G2 from the valid ones.
+func RecoverSecretPairing(
+	suite pairing.Suite,
+	commits []kyber.Point,
+	decShares []*share.PubShare,
+	t, n uint32,
+) (kyber.Point, error) {
+	var shares []*share.PubShare
+	for _, s := range decShares {
+		if s == nil || s.I >= uint32(len(commits)) {
+			continue
+		}
+		if err := VerifyDecSharePairing(suite, commits[s.I], s); err == nil {
+			shares = append(shares, s)
+		}
+	}
+	if uint32(len(shares)) < t {
+		return nil, fmt.Errorf("didn't verify: %w", ErrTooFewShares)
+	}
+	return share.RecoverCommit(suite.G2(), shares, t, n)
+}
@@ -0,0 +1,72 @@
+//go:build !constantTime
+
+package pvss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/msm"
+	"go.dedis.ch/kyber/v4/pairing/bn256"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+func TestVerifyShareBatchPrimeOrder(test *testing.T) {
+	// the DLEQ proofs are checked with multi-scalar multiplications
+	suite := bn256.NewSuiteG1()
+	require.True(test, msm.PrimeOrder(suite))
+	n := uint32(10)
+	testVerifyShareBatchInvalid(test, getSuiteConfig(suite, n, 2*n/3+1))
+}
+
+func TestPVSSPairing(test *testing.T) {
+	suite := bn256.NewSuite()
+	n := uint32(7)
+	t := uint32(4)
+	x := make([]kyber.Scalar, n)
+	X := make([]kyber.Point, n)
+	for i := range n {
+		x[i] = suite.G2().Scalar().Pick(suite.RandomStream())
+		X[i] = suite.G2().Point().Mul(x[i], nil)
+	}
+	secret := suite.G1().Scalar().Pick(suite.RandomStream())
+
+	encShares, commits, err := EncSharesPairing(suite, X, secret, t)
+	require.NoError(test, err)
+	K, E, err := VerifyEncShareBatchPairing(suite, X, commits, t, encShares)
+	require.NoError(test, err)
+	require.Len(test, K, int(n))
+	require.Len(test, E, int(n))
+
+	decShares := make([]*share.PubShare, n)
+	for i := range n {
+		decShares[i] = DecSharePairing(suite, x[i], encShares[i])
+		require.NoError(test, VerifyDecSharePairing(suite, commits[i], decShares[i]))
+	}
+
+	// corrupt some decrypted shares
+	decShares[0] = &share.PubShare{I: 0, V: suite.G2().Point().Base()}
+	decShares[5].V = suite.G2().Point().Null()
+	require.Error(test, VerifyDecSharePairing(suite, commits[0], decShares[0]))
+	recovered, err := RecoverSecretPairing(suite, commits, decShares, t, n)
+	require.NoError(test, err)
+	require.True(test, suite.G2().Point().Mul(secret, nil).Equal(recovered))
+
+	decShares[1].V = suite.G2().Point().Null()
+	decShares[2].V = suite.G2().Point().Null()
+	_, err = RecoverSecretPairing(suite, commits, decShares, t, n)
+	require.ErrorIs(test, err, ErrTooFewShares)
+
+	// a bad encrypted share is filtered out, bad commitments are rejected
+	encShares[3].V = suite.G2().Point().Base()
+	K, _, err = VerifyEncShareBatchPairing(suite, X, commits, t, encShares)
+	require.NoError(test, err)
+	require.Len(test, K, int(n-1))
+	commits[3] = suite.G1().Point().Base()
+	_, _, err = VerifyEncShareBatchPairing(suite, X, commits, t, encShares)
+	require.ErrorIs(test, err, ErrScrapeVerification)
+
+	_, _, err = EncSharesPairing(suite, X, secret, n+1)
+	require.Error(test, err)
+}
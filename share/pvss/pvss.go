@@ -10,11 +10,16 @@
 //  3. Once a threshold of decrypted shares has been released, anyone can
 //     verify them and, if enough shares are valid, recover the shared secret
 //     using RecoverSecret().
+//
+// With EncSharesScrape() and VerifyEncShareScrape(), the encrypted shares are
+// verified in linear time as in SCRAPE, and the functions with the Pairing
+// suffix implement the pairing-based variant, which needs no proofs.
 package pvss
 
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"go.dedis.ch/kyber/v4"
 	"go.dedis.ch/kyber/v4/proof/dleq"
@@ -55,6 +60,19 @@ func EncShares(
 	secret kyber.Scalar,
 	t uint32,
 ) (shares []*PubVerShare, commit *share.PubPoly, err error) {
+	shares, commit, _, err = encShares(suite, H, X, secret, t)
+	return shares, commit, err
+}
+
+// encShares implements EncShares and additionally returns the commitments sH
+// to the shares.
+func encShares(
+	suite Suite,
+	H kyber.Point,
+	X []kyber.Point,
+	secret kyber.Scalar,
+	t uint32,
+) ([]*PubVerShare, *share.PubPoly, []kyber.Point, error) {
 	n := uint32(len(X))
 	encShares := make([]*PubVerShare, n)
 
@@ -78,9 +96,9 @@ func EncShares(
 	}
 
 	// Create NIZK discrete-logarithm equality proofs
-	proofs, _, sX, err := dleq.NewDLEQProofBatch(suite, HS, X, values)
+	proofs, sH, sX, err := dleq.NewDLEQProofBatch(suite, HS, X, values)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	for i := range n {
@@ -88,7 +106,7 @@ func EncShares(
 		encShares[i] = &PubVerShare{*ps, *proofs[i]}
 	}
 
-	return encShares, pubPoly, nil
+	return encShares, pubPoly, sH, nil
 }
 
 func computeCommitments(suite Suite, n uint32, polyComs []kyber.Point) []kyber.Point {
@@ -117,10 +135,16 @@ func computeGlobalChallenge(suite Suite, n uint32, commit *share.PubPoly,
 	encShares []*PubVerShare) (kyber.Scalar, error) {
 	_, polyComs := commit.Info()
 	coms := computeCommitments(suite, n, polyComs)
+	return GlobalChallenge(suite, coms, encShares)
+}
 
+// GlobalChallenge returns the challenge of the encryption consistency proofs
+// of the encrypted shares, given the commitments sH to the shares. It is the
+// challenge expected by VerifyEncShare and DecShare.
+func GlobalChallenge(suite Suite, sH []kyber.Point, encShares []*PubVerShare) (kyber.Scalar, error) {
 	h := suite.Hash()
 	var err error
-	for _, com := range coms {
+	for _, com := range sH {
 		if _, err = com.MarshalTo(h); err != nil {
 			return nil, err
 		}
@@ -164,7 +188,10 @@ func VerifyEncShare(suite Suite, H, X, sH kyber.Point, expGlobalChallenge kyber.
 
 // VerifyEncShareBatch provides the same functionality as VerifyEncShare but for
 // slices of encrypted shares. The function returns the valid encrypted shares
-// together with the corresponding public keys.
+// together with the corresponding public keys. The encryption consistency
+// proofs are checked with dleq.VerifyBatch, which batches them in groups of
+// prime order, such as the groups of bn256, and checks them one by one in the
+// others, such as edwards25519, where it brings no speedup.
 func VerifyEncShareBatch(
 	suite Suite,
 	H kyber.Point,
@@ -184,8 +211,26 @@ func VerifyEncShareBatch(
 		return nil, nil, err
 	}
 
+	// Check the challenges, and the proofs in a batch
+	var idx []int
+	var Hs, Xs, sHs, sXs []kyber.Point
+	var proofs []*dleq.Proof
 	for i := range X {
-		if err := VerifyEncShare(suite, H, X[i], sH[i], expGlobalChallenge, encShares[i]); err == nil {
+		if encShares[i].P.C.Equal(expGlobalChallenge) {
+			idx = append(idx, i)
+			Hs = append(Hs, H)
+			Xs = append(Xs, X[i])
+			sHs = append(sHs, sH[i])
+			sXs = append(sXs, encShares[i].S.V)
+			proofs = append(proofs, &encShares[i].P)
+		}
+	}
+	failed, err := dleq.VerifyBatch(suite, Hs, Xs, sHs, sXs, proofs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for j, i := range idx {
+		if !slices.Contains(failed, j) {
 			K = append(K, X[i])
 			E = append(E, encShares[i])
 		}
@@ -246,28 +291,9 @@ func DecShareBatch(
 // VerifyDecShare checks that the decrypted share sG satisfies
 // log_{G}(X) == log_{sG}(sX). Note that X = xG and sX = s(xG) = x(sG).
 func VerifyDecShare(suite Suite, G, X kyber.Point, encShare *PubVerShare, decShare *PubVerShare) error {
-	// Compute challenge for the decShare
-	h := suite.Hash()
-	var err error
-	if _, err = X.MarshalTo(h); err != nil {
+	if err := verifyDecShareChallenge(suite, X, encShare, decShare); err != nil {
 		return err
 	}
-	if _, err = encShare.S.V.MarshalTo(h); err != nil {
-		return err
-	}
-	if _, err = decShare.P.VG.MarshalTo(h); err != nil {
-		return err
-	}
-	if _, err = decShare.P.VH.MarshalTo(h); err != nil {
-		return err
-	}
-
-	cb := h.Sum(nil)
-	expDecChallenge := suite.Scalar().Pick(suite.XOF(cb))
-
-	if !decShare.P.C.Equal(expDecChallenge) {
-		return fmt.Errorf("didn't verify: %w", ErrDecShareChallengeVerification)
-	}
 
 	if err := decShare.P.Verify(suite, G, decShare.S.V, X, encShare.S.V); err != nil {
 		return fmt.Errorf("didn't verify: %w", ErrDecVerification)
@@ -278,6 +304,10 @@ func VerifyDecShare(suite Suite, G, X kyber.Point, encShare *PubVerShare, decSha
 
 // VerifyDecShareBatch provides the same functionality as VerifyDecShare but for
 // slices of decrypted shares. The function returns the valid decrypted shares.
+// The decryption consistency proofs are checked with dleq.VerifyBatch, which
+// batches them in groups of prime order, such as the groups of bn256, and
+// checks them one by one in the others, such as edwards25519, where it brings
+// no speedup.
 func VerifyDecShareBatch(
 	suite Suite,
 	G kyber.Point,
@@ -289,15 +319,61 @@ func VerifyDecShareBatch(
 		return nil, fmt.Errorf("didn't verify: %w", ErrDifferentLengths)
 	}
 
-	var D []*PubVerShare // good decrypted shares
+	// Check the challenges, and the proofs in a batch
+	var idx []int
+	var Gs, sGs, Xs, sXs []kyber.Point
+	var proofs []*dleq.Proof
 	for i := range X {
-		if err := VerifyDecShare(suite, G, X[i], encShares[i], decShares[i]); err == nil {
+		if err := verifyDecShareChallenge(suite, X[i], encShares[i], decShares[i]); err == nil {
+			idx = append(idx, i)
+			Gs = append(Gs, G)
+			sGs = append(sGs, decShares[i].S.V)
+			Xs = append(Xs, X[i])
+			sXs = append(sXs, encShares[i].S.V)
+			proofs = append(proofs, &decShares[i].P)
+		}
+	}
+	failed, err := dleq.VerifyBatch(suite, Gs, sGs, Xs, sXs, proofs)
+	if err != nil {
+		return nil, err
+	}
+
+	var D []*PubVerShare // good decrypted shares
+	for j, i := range idx {
+		if !slices.Contains(failed, j) {
 			D = append(D, decShares[i])
 		}
 	}
 	return D, nil
 }
 
+// verifyDecShareChallenge checks the challenge of the decryption consistency
+// proof of the decrypted share.
+func verifyDecShareChallenge(suite Suite, X kyber.Point, encShare *PubVerShare, decShare *PubVerShare) error {
+	h := suite.Hash()
+	var err error
+	if _, err = X.MarshalTo(h); err != nil {
+		return err
+	}
+	if _, err = encShare.S.V.MarshalTo(h); err != nil {
+		return err
+	}
+	if _, err = decShare.P.VG.MarshalTo(h); err != nil {
+		return err
+	}
+	if _, err = decShare.P.VH.MarshalTo(h); err != nil {
+		return err
+	}
+
+	cb := h.Sum(nil)
+	expDecChallenge := suite.Scalar().Pick(suite.XOF(cb))
+
+	if !decShare.P.C.Equal(expDecChallenge) {
+		return fmt.Errorf("didn't verify: %w", ErrDecShareChallengeVerification)
+	}
+	return nil
+}
+
 // RecoverSecret first verifies the given decrypted shares against their
 // decryption consistency proofs and then tries to recover the shared secret.
 func RecoverSecret(
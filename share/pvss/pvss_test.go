@@ -20,7 +20,10 @@ type Config struct {
 }
 
 func getConfig(n, t uint32) Config {
-	suite := edwards25519.NewBlakeSHA256Ed25519()
+	return getSuiteConfig(edwards25519.NewBlakeSHA256Ed25519(), n, t)
+}
+
+func getSuiteConfig(suite Suite, n, t uint32) Config {
 	conf := Config{
 		suite: suite,
 		n:     n,
@@ -303,3 +306,44 @@ func TestPVSSBatch(test *testing.T) {
 	require.True(test, suite.Point().Mul(s1, nil).Equal(S1))
 	require.True(test, suite.Point().Mul(s2, nil).Equal(S2))
 }
+
+func TestVerifyShareBatchInvalid(test *testing.T) {
+	n := uint32(10)
+	testVerifyShareBatchInvalid(test, getConfig(n, 2*n/3+1))
+}
+
+// testVerifyShareBatchInvalid checks that the batch verifications find the
+// invalid shares.
+func testVerifyShareBatchInvalid(test *testing.T, conf Config) {
+	n := conf.n
+	suite := conf.suite
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	pubPoly, encShares, sH, err := EncryptAndShare(conf, secret)
+	require.NoError(test, err)
+	ked, err := ComputeKED(conf, n, pubPoly, encShares, sH)
+	require.NoError(test, err)
+	one := suite.Scalar().One()
+
+	// the batch verification finds the invalid decrypted shares
+	D := append([]*PubVerShare{}, ked.D...)
+	for _, i := range []int{1, 4, 6} {
+		ds := *ked.D[i]
+		ked.D[i] = &ds
+	}
+	ked.D[1].P.R = suite.Scalar().Add(ked.D[1].P.R, one)
+	ked.D[4].S.V = suite.Point().Null()
+	ked.D[6].P.VG = suite.Point().Base()
+	valid, err := VerifyDecShareBatch(suite, suite.Point().Base(), ked.K, ked.E, ked.D)
+	require.NoError(test, err)
+	require.Equal(test, []*PubVerShare{D[0], D[2], D[3], D[5], D[7], D[8], D[9]}, valid)
+
+	// and the invalid encrypted shares
+	encShares[2].P.R = suite.Scalar().Add(encShares[2].P.R, one)
+	encShares[8].P.R = suite.Scalar().Add(encShares[8].P.R, one)
+	K, E, err := VerifyEncShareBatch(suite, conf.H, conf.X, sH, pubPoly, encShares)
+	require.NoError(test, err)
+	require.Len(test, K, int(n)-2)
+	require.Len(test, E, int(n)-2)
+	require.NotContains(test, E, encShares[2])
+	require.NotContains(test, E, encShares[8])
+}
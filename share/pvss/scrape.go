@@ -0,0 +1,124 @@
+package pvss
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// The SCRAPE variant of the scheme, from "SCRAPE: Scalable Randomness Attested
+// by Public Entities" by Cascudo and David, lets the dealer publish the
+// commitments sH to the n shares instead of the commitment polynomial.
+// Verifying that the encrypted shares are consistent then takes O(n)
+// exponentiations instead of O(n*t) to evaluate the polynomial at every
+// index: the commitments must be a codeword of the Reed-Solomon code of the
+// shares, which is checked against a random codeword of its dual code.
+
+var ErrScrapeVerification = errors.New("commitments are not shares of a polynomial of the expected degree")
+
+// EncSharesScrape creates the encrypted shares like EncShares, but returns
+// the commitments sH to the shares instead of the commitment polynomial.
+func EncSharesScrape(
+	suite Suite,
+	H kyber.Point,
+	X []kyber.Point,
+	secret kyber.Scalar,
+	t uint32,
+) (shares []*PubVerShare, sH []kyber.Point, err error) {
+	shares, _, sH, err = encShares(suite, H, X, secret, t)
+	return shares, sH, err
+}
+
+// VerifyEncShareScrape verifies the encrypted shares of a dealer against the
+// commitments sH to the shares, with a threshold of t. The encrypted shares
+// must be given in the order of their indices, starting from 0. It returns an
+// error wrapping ErrScrapeVerification if the commitments are not shares of a
+// polynomial of degree less than t, and otherwise the valid encrypted shares
+// together with the corresponding public keys.
+func VerifyEncShareScrape(
+	suite Suite,
+	H kyber.Point,
+	X, sH []kyber.Point,
+	t uint32,
+	encShares []*PubVerShare,
+) ([]kyber.Point, []*PubVerShare, error) {
+	if len(X) != len(sH) || len(sH) != len(encShares) {
+		return nil, nil, fmt.Errorf("didn't verify: %w", ErrDifferentLengths)
+	}
+	for i, s := range encShares {
+		if s.S.I != uint32(i) {
+			return nil, nil, fmt.Errorf("didn't verify: encrypted share %d has index %d", i, s.S.I)
+		}
+	}
+	if !checkDualCode(suite, suite.RandomStream(), sH, t) {
+		return nil, nil, fmt.Errorf("didn't verify: %w", ErrScrapeVerification)
+	}
+
+	expGlobalChallenge, err := GlobalChallenge(suite, sH, encShares)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var K []kyber.Point  // good public keys
+	var E []*PubVerShare // good encrypted shares
+	for i := range X {
+		if err := VerifyEncShare(suite, H, X[i], sH[i], expGlobalChallenge, encShares[i]); err == nil {
+			K = append(K, X[i])
+			E = append(E, encShares[i])
+		}
+	}
+	return K, E, nil
+}
+
+// checkDualCode checks that the points are the evaluations at 1, ..., n of a
+// polynomial of degree less than t, in the exponent. It computes the inner
+// product of the points with a random codeword of the dual code, which is
+// zero for a valid sharing and zero with negligible probability otherwise.
+// The codewords of the dual code are c_i = v_i * f(i) for a polynomial f of
+// degree at most n-t-1, where v_i is the inverse of the product of (i - j)
+// for all j != i.
+func checkDualCode(g kyber.Group, rand cipher.Stream, points []kyber.Point, t uint32) bool {
+	n := len(points)
+	if t == 0 || int(t) > n {
+		return false
+	}
+	if int(t) == n {
+		// any n points are the shares of a polynomial of degree n-1
+		return true
+	}
+
+	// v_i = (-1)^(n-i) / ((i-1)! (n-i)!), from the factorials up to n-1
+	fact := make([]kyber.Scalar, n)
+	fact[0] = g.Scalar().One()
+	for k := 1; k < n; k++ {
+		fact[k] = g.Scalar().Mul(fact[k-1], g.Scalar().SetInt64(int64(k)))
+	}
+	invFact := make([]kyber.Scalar, n)
+	invFact[n-1] = g.Scalar().Inv(fact[n-1])
+	for k := n - 1; k > 0; k-- {
+		invFact[k-1] = g.Scalar().Mul(invFact[k], g.Scalar().SetInt64(int64(k)))
+	}
+
+	f := make([]kyber.Scalar, n-int(t))
+	for k := range f {
+		f[k] = g.Scalar().Pick(rand)
+	}
+
+	acc := g.Point().Null()
+	tmp := g.Point()
+	for i, p := range points {
+		x := g.Scalar().SetInt64(int64(i) + 1)
+		c := g.Scalar().Zero()
+		for k := len(f) - 1; k >= 0; k-- {
+			c.Mul(c, x).Add(c, f[k])
+		}
+		c.Mul(c, invFact[i]).Mul(c, invFact[n-1-i])
+		if (n-1-i)%2 == 1 {
+			c.Neg(c)
+		}
+		acc.Add(acc, tmp.Mul(c, p))
+	}
+	return acc.Equal(g.Point().Null())
+}
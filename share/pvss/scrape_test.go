@@ -0,0 +1,118 @@
+package pvss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+func TestPVSSScrape(test *testing.T) {
+	n := uint32(10)
+	t := 2*n/3 + 1
+	conf := getConfig(n, t)
+	suite := conf.suite
+	G := suite.Point().Base()
+	secret := suite.Scalar().Pick(suite.RandomStream())
+
+	encShares, sH, err := EncSharesScrape(suite, conf.H, conf.X, secret, t)
+	require.NoError(test, err)
+
+	K, E, err := VerifyEncShareScrape(suite, conf.H, conf.X, sH, t, encShares)
+	require.NoError(test, err)
+	require.Len(test, K, int(n))
+	require.Len(test, E, int(n))
+
+	// the trustees decrypt their shares with the same challenge
+	challenge, err := GlobalChallenge(suite, sH, encShares)
+	require.NoError(test, err)
+	var X []kyber.Point
+	var D []*PubVerShare
+	for i := range n {
+		ds, err := DecShare(suite, conf.H, conf.X[i], sH[i], conf.x[i], challenge, encShares[i])
+		require.NoError(test, err)
+		X = append(X, conf.X[i])
+		D = append(D, ds)
+	}
+	recovered, err := RecoverSecret(suite, G, X, encShares, D, t, n)
+	require.NoError(test, err)
+	require.True(test, suite.Point().Mul(secret, nil).Equal(recovered))
+}
+
+func TestPVSSScrapeFail(test *testing.T) {
+	n := uint32(8)
+	t := uint32(4)
+	conf := getConfig(n, t)
+	suite := conf.suite
+	secret := suite.Scalar().Pick(suite.RandomStream())
+
+	// the commitments of a polynomial of a too high degree are rejected
+	encShares, sH, err := EncSharesScrape(suite, conf.H, conf.X, secret, t+1)
+	require.NoError(test, err)
+	_, _, err = VerifyEncShareScrape(suite, conf.H, conf.X, sH, t, encShares)
+	require.ErrorIs(test, err, ErrScrapeVerification)
+	_, _, err = VerifyEncShareScrape(suite, conf.H, conf.X, sH, t+1, encShares)
+	require.NoError(test, err)
+
+	// as well as a tampered commitment
+	encShares, sH, err = EncSharesScrape(suite, conf.H, conf.X, secret, t)
+	require.NoError(test, err)
+	good := sH[2]
+	sH[2] = suite.Point().Add(good, conf.H)
+	_, _, err = VerifyEncShareScrape(suite, conf.H, conf.X, sH, t, encShares)
+	require.ErrorIs(test, err, ErrScrapeVerification)
+	sH[2] = good
+
+	// a bad encrypted share is filtered out
+	encShares[3].P.R = suite.Scalar().Pick(suite.RandomStream())
+	K, E, err := VerifyEncShareScrape(suite, conf.H, conf.X, sH, t, encShares)
+	require.NoError(test, err)
+	require.Len(test, K, int(n-1))
+	require.Len(test, E, int(n-1))
+
+	// the shares must be complete and ordered
+	_, _, err = VerifyEncShareScrape(suite, conf.H, conf.X[1:], sH[1:], t, encShares[1:])
+	require.Error(test, err)
+	_, _, err = VerifyEncShareScrape(suite, conf.H, conf.X, sH[1:], t, encShares)
+	require.ErrorIs(test, err, ErrDifferentLengths)
+}
+
+func TestCheckDualCode(test *testing.T) {
+	suite := getConfig(1, 1).suite
+	for _, tc := range []struct{ n, t uint32 }{{1, 1}, {5, 1}, {5, 5}, {20, 7}} {
+		pub := share.NewPriPoly(suite, tc.t, nil, suite.RandomStream()).Commit(nil)
+		points := make([]kyber.Point, tc.n)
+		for i, s := range pub.Shares(tc.n) {
+			points[i] = s.V
+		}
+		require.True(test, checkDualCode(suite, suite.RandomStream(), points, tc.t))
+		require.False(test, checkDualCode(suite, suite.RandomStream(), points, 0))
+		require.False(test, checkDualCode(suite, suite.RandomStream(), points, tc.n+1))
+		if tc.t < tc.n {
+			points[0] = suite.Point().Add(points[0], suite.Point().Base())
+			require.False(test, checkDualCode(suite, suite.RandomStream(), points, tc.t))
+		}
+	}
+}
+
+func BenchmarkVerifyEncShare(b *testing.B) {
+	n := uint32(100)
+	t := n/2 + 1
+	conf := getConfig(n, t)
+	suite := conf.suite
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	encShares, pubPoly, sH, err := encShares(suite, conf.H, conf.X, secret, t)
+	require.NoError(b, err)
+
+	b.Run("Batch", func(b *testing.B) {
+		for b.Loop() {
+			_, _, _ = VerifyEncShareBatch(suite, conf.H, conf.X, sH, pubPoly, encShares)
+		}
+	})
+	b.Run("Scrape", func(b *testing.B) {
+		for b.Loop() {
+			_, _, _ = VerifyEncShareScrape(suite, conf.H, conf.X, sH, t, encShares)
+		}
+	})
+}
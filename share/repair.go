@@ -0,0 +1,147 @@
+package share
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.dedis.ch/kyber/v4"
+)
+
+// The share repair protocol allows a set of helpers, holding at least t
+// shares of a polynomial p, to give to a participant the share p(target+1) it
+// lost, without revealing their own shares nor the secret. It follows the
+// enrollment protocol of Laing and Stinson:
+//
+//  1. Each helper i computes its contribution l_i(target) * s_i to the lost
+//     share, where l_i is its Lagrange coefficient over the set of helpers,
+//     splits it into random additive deltas, one per helper, and sends each
+//     delta privately to its recipient with RepairDeltas.
+//  2. Each helper j sums the deltas it received with RepairSum and sends the
+//     result privately to the target.
+//  3. The target adds the sums together with RepairShare. If the public
+//     polynomial is known, it should check the recovered share against it.
+//
+// A helper only ever sees random looking values, and the target only learns
+// its own share. The deltas and sums must be sent over private and
+// authenticated channels.
+
+// RepairDelta is a value exchanged during the share repair protocol.
+type RepairDelta struct {
+	From uint32       // Index of the helper sending the value
+	To   uint32       // Index of the recipient of the value
+	V    kyber.Scalar // Value of the delta
+}
+
+// RepairDeltas returns the deltas that the helper holding the share own must
+// send to each of the helpers, including itself, to repair the share of index
+// target. The list of helpers must contain at least t distinct indices,
+// including the index of own but not target.
+func RepairDeltas(g kyber.Group, own *PriShare, t uint32, helpers []uint32, target uint32,
+	rand cipher.Stream) ([]*RepairDelta, error) {
+	xs, err := repairHelpers(g, helpers, target)
+	if err != nil {
+		return nil, err
+	}
+	if uint32(len(xs)) < t {
+		return nil, errors.New("share: not enough helpers to repair a share")
+	}
+	if _, ok := xs[own.I]; !ok {
+		return nil, errors.New("share: helper not in the list of helpers")
+	}
+
+	x := g.Scalar().SetInt64(1 + int64(target))
+	indices := sortedIndices(xs)
+	coeffs := lagrangeCoefficients(g, indices, x)
+	i, _ := slices.BinarySearch(indices, own.I)
+	contrib := g.Scalar().Mul(coeffs[i], own.V)
+	deltas := make([]*RepairDelta, len(helpers))
+	rest := contrib
+	for i, h := range helpers {
+		v := g.Scalar()
+		if i == len(helpers)-1 {
+			v.Set(rest)
+		} else {
+			v.Pick(rand)
+			rest.Sub(rest, v)
+		}
+		deltas[i] = &RepairDelta{From: own.I, To: h, V: v}
+	}
+	return deltas, nil
+}
+
+// RepairSum adds the deltas received by the given helper, one from every
+// helper, and returns the value it must send to the target.
+func RepairSum(g kyber.Group, helper uint32, helpers []uint32, target uint32,
+	deltas []*RepairDelta) (*RepairDelta, error) {
+	xs, err := repairHelpers(g, helpers, target)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := xs[helper]; !ok {
+		return nil, errors.New("share: helper not in the list of helpers")
+	}
+	sum, err := sumRepairDeltas(g, helper, xs, deltas)
+	if err != nil {
+		return nil, err
+	}
+	return &RepairDelta{From: helper, To: target, V: sum}, nil
+}
+
+// RepairShare adds the sums received from every helper and returns the
+// repaired share of index target.
+func RepairShare(g kyber.Group, target uint32, helpers []uint32, sums []*RepairDelta) (*PriShare, error) {
+	xs, err := repairHelpers(g, helpers, target)
+	if err != nil {
+		return nil, err
+	}
+	v, err := sumRepairDeltas(g, target, xs, sums)
+	if err != nil {
+		return nil, err
+	}
+	return &PriShare{I: target, V: v}, nil
+}
+
+// repairHelpers checks the list of helpers and returns their x-coordinates.
+func repairHelpers(g kyber.Group, helpers []uint32, target uint32) (map[uint32]kyber.Scalar, error) {
+	xs := make(map[uint32]kyber.Scalar, len(helpers))
+	for _, h := range helpers {
+		if h == target {
+			return nil, errors.New("share: the target can't be a helper")
+		}
+		if _, ok := xs[h]; ok {
+			return nil, fmt.Errorf("share: duplicate helper %d", h)
+		}
+		xs[h] = g.Scalar().SetInt64(1 + int64(h))
+	}
+	return xs, nil
+}
+
+// sumRepairDeltas adds the deltas sent to the recipient, which must contain
+// exactly one delta from every helper.
+func sumRepairDeltas(g kyber.Group, recipient uint32, helpers map[uint32]kyber.Scalar,
+	deltas []*RepairDelta) (kyber.Scalar, error) {
+	seen := make(map[uint32]bool, len(deltas))
+	sum := g.Scalar().Zero()
+	for _, d := range deltas {
+		if d == nil || d.V == nil {
+			return nil, errors.New("share: nil repair value")
+		}
+		if d.To != recipient {
+			return nil, fmt.Errorf("share: repair value from %d is not for %d", d.From, recipient)
+		}
+		if _, ok := helpers[d.From]; !ok {
+			return nil, fmt.Errorf("share: repair value from unknown helper %d", d.From)
+		}
+		if seen[d.From] {
+			return nil, fmt.Errorf("share: duplicate repair value from %d", d.From)
+		}
+		seen[d.From] = true
+		sum.Add(sum, d.V)
+	}
+	if len(seen) != len(helpers) {
+		return nil, errors.New("share: missing repair values")
+	}
+	return sum, nil
+}
@@ -0,0 +1,108 @@
+package share
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+)
+
+// runRepair runs the repair protocol for the target with the given helpers.
+func runRepair(test *testing.T, g *edwards25519.SuiteEd25519, shares []*PriShare, t uint32,
+	helpers []uint32, target uint32) (*PriShare, [][]*RepairDelta) {
+	received := make([][]*RepairDelta, len(helpers))
+	for _, h := range helpers {
+		deltas, err := RepairDeltas(g, shares[h], t, helpers, target, g.RandomStream())
+		require.NoError(test, err)
+		require.Len(test, deltas, len(helpers))
+		for i, d := range deltas {
+			require.Equal(test, helpers[i], d.To)
+			received[i] = append(received[i], d)
+		}
+	}
+	sums := make([]*RepairDelta, len(helpers))
+	for i, h := range helpers {
+		sum, err := RepairSum(g, h, helpers, target, received[i])
+		require.NoError(test, err)
+		sums[i] = sum
+	}
+	sh, err := RepairShare(g, target, helpers, sums)
+	require.NoError(test, err)
+	return sh, received
+}
+
+func TestRepair(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := uint32(7)
+	t := uint32(4)
+	poly := NewPriPoly(g, t, nil, g.RandomStream())
+	pub := poly.Commit(nil)
+	shares := poly.Shares(n)
+
+	target := uint32(2)
+	helpers := []uint32{6, 0, 3, 5}
+	sh, received := runRepair(test, g, shares, t, helpers, target)
+	require.Equal(test, target, sh.I)
+	require.True(test, shares[target].V.Equal(sh.V))
+	require.True(test, pub.Check(sh))
+
+	// the deltas received by a helper don't reveal the share of the others
+	for _, deltas := range received {
+		for _, d := range deltas {
+			require.False(test, d.V.Equal(shares[d.From].V))
+		}
+	}
+
+	// more helpers than the threshold
+	sh, _ = runRepair(test, g, shares, t, []uint32{0, 1, 3, 4, 5, 6}, target)
+	require.True(test, shares[target].V.Equal(sh.V))
+}
+
+func TestRepairInvalid(test *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	n := uint32(7)
+	t := uint32(4)
+	poly := NewPriPoly(g, t, nil, g.RandomStream())
+	shares := poly.Shares(n)
+	target := uint32(2)
+	helpers := []uint32{0, 1, 3, 4}
+
+	// not enough helpers
+	_, err := RepairDeltas(g, shares[0], t, helpers[:3], target, g.RandomStream())
+	require.Error(test, err)
+	// the target is a helper
+	_, err = RepairDeltas(g, shares[0], t, []uint32{0, 1, 2, 3}, target, g.RandomStream())
+	require.Error(test, err)
+	// duplicate helper
+	_, err = RepairDeltas(g, shares[0], t, []uint32{0, 1, 3, 3}, target, g.RandomStream())
+	require.Error(test, err)
+	// not a helper
+	_, err = RepairDeltas(g, shares[5], t, helpers, target, g.RandomStream())
+	require.Error(test, err)
+
+	var deltas []*RepairDelta
+	for _, h := range helpers {
+		ds, err := RepairDeltas(g, shares[h], t, helpers, target, g.RandomStream())
+		require.NoError(test, err)
+		deltas = append(deltas, ds[0])
+	}
+	// missing delta
+	_, err = RepairSum(g, 0, helpers, target, deltas[1:])
+	require.Error(test, err)
+	// duplicate delta
+	_, err = RepairSum(g, 0, helpers, target, append(deltas[1:], deltas[1]))
+	require.Error(test, err)
+	// delta for another helper
+	_, err = RepairSum(g, 1, helpers, target, deltas)
+	require.Error(test, err)
+
+	sum, err := RepairSum(g, 0, helpers, target, deltas)
+	require.NoError(test, err)
+	// sums from a single helper
+	_, err = RepairShare(g, target, helpers, []*RepairDelta{sum})
+	require.Error(test, err)
+	// sums for another target
+	_, err = RepairShare(g, 5, helpers, []*RepairDelta{sum, sum, sum, sum})
+	require.Error(test, err)
+}
@@ -0,0 +1,551 @@
+package vss
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/wire"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/sign/schnorr"
+)
+
+// The Dealer above commits to the coefficients of the secret polynomial as
+// g^a_i, which reveals g^secret to everyone. The hiding variant below commits
+// to each coefficient as g^a_i h^b_i, where the b_i are the coefficients of a
+// second random polynomial and h is a generator whose discrete logarithm in
+// base g is unknown. The commitments are then perfectly hiding: they reveal
+// nothing about the secret, even to an unbounded adversary. Each verifier
+// receives one share of both polynomials and checks them against the
+// commitments.
+
+// hidingDomain separates the derivation of the second generator from any other
+// use of the hash.
+const hidingDomain = "kyber-vss-pedersen-h"
+
+// hashToCurve is implemented by the points supporting the hash-to-curve
+// construction of RFC 9380 with a domain separation tag.
+type hashToCurve interface {
+	Hash(msg []byte, dst string) kyber.Point
+}
+
+// HidingDeal is the deal sent by a HidingDealer to a verifier.
+type HidingDeal struct {
+	// Unique session identifier for this protocol run
+	SessionID []byte
+	// Private share of the secret polynomial
+	SecShare *share.PriShare
+	// Private share of the blinding polynomial
+	RndShare *share.PriShare
+	// Threshold used for this secret sharing run
+	T uint32
+	// Commitments are the hiding commitments g^a_i h^b_i of the coefficients
+	Commitments []kyber.Point
+}
+
+// Marshal returns the versioned binary encoding of the HidingDeal.
+func (d *HidingDeal) Marshal() ([]byte, error) {
+	if d.SecShare == nil || d.RndShare == nil {
+		return nil, errors.New("vss: nil share in deal")
+	}
+	enc := wire.NewEncoder(wire.TagVSSHidingDeal)
+	enc.Bytes(d.SessionID)
+	enc.Uint32(d.SecShare.I)
+	enc.Scalar(d.SecShare.V)
+	enc.Uint32(d.RndShare.I)
+	enc.Scalar(d.RndShare.V)
+	enc.Uint32(d.T)
+	enc.Points(d.Commitments)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a HidingDeal encoded with Marshal. It returns an error if
+// the shares have different indexes or if the number of commitments does not
+// match the threshold.
+func (d *HidingDeal) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagVSSHidingDeal)
+	sid := dec.Bytes()
+	secShare := &share.PriShare{I: dec.Uint32(), V: dec.Scalar(suite)}
+	rndShare := &share.PriShare{I: dec.Uint32(), V: dec.Scalar(suite)}
+	t := dec.Uint32()
+	commitments := dec.Points(suite)
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	if secShare.I != rndShare.I {
+		return errors.New("vss: shares with different indexes in deal")
+	}
+	if len(commitments) == 0 || uint32(len(commitments)) != t {
+		return errors.New("vss: invalid number of commitments in deal")
+	}
+	d.SessionID = sid
+	d.SecShare = secShare
+	d.RndShare = rndShare
+	d.T = t
+	d.Commitments = commitments
+	return nil
+}
+
+// HidingJustification is broadcasted by a HidingDealer in response to a
+// complaint. It reveals the deal given to the complaining verifier.
+type HidingJustification struct {
+	// SessionID related to the current run of the protocol
+	SessionID []byte
+	// Index of the verifier who issued the Complaint,i.e. index of this Deal
+	Index uint32
+	// Deal in cleartext
+	Deal *HidingDeal
+	// Signature over the whole packet
+	Signature []byte
+}
+
+// Hash returns the hash of a HidingJustification.
+func (j *HidingJustification) Hash(s Suite) []byte {
+	h := s.Hash()
+	_, _ = h.Write([]byte("hidingjustification"))
+	_, _ = h.Write(j.SessionID)
+	_ = binary.Write(h, binary.LittleEndian, j.Index)
+	buff, _ := j.Deal.Marshal()
+	_, _ = h.Write(buff)
+	return h.Sum(nil)
+}
+
+// Marshal returns the versioned binary encoding of the HidingJustification.
+func (j *HidingJustification) Marshal() ([]byte, error) {
+	if j.Deal == nil {
+		return nil, errors.New("vss: nil deal in justification")
+	}
+	dealBuff, err := j.Deal.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	enc := wire.NewEncoder(wire.TagVSSHidingJustification)
+	enc.Bytes(j.SessionID)
+	enc.Uint32(j.Index)
+	enc.Bytes(dealBuff)
+	enc.Bytes(j.Signature)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a HidingJustification encoded with Marshal. It returns an
+// error if the deal is not consistent with the justification.
+func (j *HidingJustification) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagVSSHidingJustification)
+	sid := dec.Bytes()
+	index := dec.Uint32()
+	dealBuff := dec.Bytes()
+	signature := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	deal := new(HidingDeal)
+	if err := deal.Unmarshal(dealBuff, suite); err != nil {
+		return err
+	}
+	if deal.SecShare.I != index {
+		return errors.New("vss: share index does not match justification index")
+	}
+	if !bytes.Equal(deal.SessionID, sid) {
+		return errors.New("vss: deal session ID does not match")
+	}
+	j.SessionID = sid
+	j.Index = index
+	j.Deal = deal
+	j.Signature = signature
+	return nil
+}
+
+// HidingDealer is the dealer of the hiding variant of the scheme. Responses
+// are the same as for the Dealer.
+type HidingDealer struct {
+	suite Suite
+	// long is the longterm key of the Dealer
+	long        kyber.Scalar
+	pub         kyber.Point
+	secretPoly  *share.PriPoly
+	commits     []kyber.Point
+	verifiers   []kyber.Point
+	hkdfContext []byte
+	// threshold of shares that is needed to reconstruct the secret
+	t uint32
+	// sessionID is a unique identifier for the whole session of the scheme
+	sessionID []byte
+	// list of deals this Dealer has generated
+	deals []*HidingDeal
+	*HidingAggregator
+}
+
+// NewHidingDealer returns a dealer sharing the secret with hiding commitments.
+// The arguments are the same as for NewDealer.
+func NewHidingDealer(suite Suite, longterm, secret kyber.Scalar, verifiers []kyber.Point,
+	t uint32) (*HidingDealer, error) {
+	if !validT(t, verifiers) {
+		return nil, fmt.Errorf("dealer: t %d invalid", t)
+	}
+	d := &HidingDealer{
+		suite:     suite,
+		long:      longterm,
+		pub:       suite.Point().Mul(longterm, nil),
+		verifiers: verifiers,
+		t:         t,
+	}
+	h := hidingGenerator(suite, verifiers)
+	f := share.NewPriPoly(suite, t, secret, suite.RandomStream())
+	g := share.NewPriPoly(suite, t, nil, suite.RandomStream())
+	// C = F + G
+	commitPoly, err := f.Commit(nil).Add(g.Commit(h))
+	if err != nil {
+		return nil, err
+	}
+	_, d.commits = commitPoly.Info()
+
+	d.sessionID, err = sessionID(suite, d.pub, verifiers, d.commits, t)
+	if err != nil {
+		return nil, err
+	}
+	d.HidingAggregator = newHidingAggregator(suite, d.pub, verifiers, d.commits, t, d.sessionID)
+	d.deals = make([]*HidingDeal, len(verifiers))
+	for i := range verifiers {
+		d.deals[i] = &HidingDeal{
+			SessionID:   d.sessionID,
+			SecShare:    f.Eval(uint32(i)),
+			RndShare:    g.Eval(uint32(i)),
+			Commitments: d.commits,
+			T:           t,
+		}
+	}
+	d.hkdfContext = context(suite, d.pub, verifiers)
+	d.secretPoly = f
+	return d, nil
+}
+
+// PlaintextDeal returns the plaintext version of the deal destined for peer i.
+// Use this only for testing.
+func (d *HidingDealer) PlaintextDeal(i int) (*HidingDeal, error) {
+	if i >= len(d.deals) {
+		return nil, errors.New("dealer: PlaintextDeal given wrong index")
+	}
+	return d.deals[i], nil
+}
+
+// EncryptedDeal returns the encryption of the deal that must be given to the
+// verifier at index i, in the same way as Dealer.EncryptedDeal.
+func (d *HidingDealer) EncryptedDeal(i int) (*EncryptedDeal, error) {
+	vPub, ok := findPub(d.verifiers, uint32(i))
+	if !ok {
+		return nil, errors.New("dealer: wrong index to generate encrypted deal")
+	}
+	dealBuff, err := d.deals[i].Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return encryptDeal(d.suite, d.long, vPub, d.hkdfContext, dealBuff)
+}
+
+// EncryptedDeals calls `EncryptedDeal` for each index of the verifier and
+// returns the list of encrypted deals. Each index in the returned slice
+// corresponds to the index in the list of verifiers.
+func (d *HidingDealer) EncryptedDeals() ([]*EncryptedDeal, error) {
+	deals := make([]*EncryptedDeal, len(d.verifiers))
+	var err error
+	for i := range d.verifiers {
+		deals[i], err = d.EncryptedDeal(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return deals, nil
+}
+
+// ProcessResponse analyzes the given Response. If it's a valid complaint, then
+// it returns a HidingJustification that must be broadcast to every
+// participant. If it's an invalid complaint, it returns an error.
+func (d *HidingDealer) ProcessResponse(r *Response) (*HidingJustification, error) {
+	if err := d.verifyResponse(r); err != nil {
+		return nil, err
+	}
+	if r.StatusApproved {
+		//nolint:nilnil // Expected behavior
+		return nil, nil
+	}
+
+	j := &HidingJustification{
+		SessionID: d.sessionID,
+		// index is guaranteed to be good because of d.verifyResponse before
+		Index: r.Index,
+		Deal:  d.deals[r.Index],
+	}
+	sig, err := schnorr.Sign(d.suite, d.long, j.Hash(d.suite))
+	if err != nil {
+		return nil, err
+	}
+	j.Signature = sig
+	return j, nil
+}
+
+// Commits returns the hiding commitments of the coefficients of the secret
+// polynomial.
+func (d *HidingDealer) Commits() []kyber.Point {
+	return d.commits
+}
+
+// Key returns the longterm key pair used by this Dealer.
+func (d *HidingDealer) Key() (secret kyber.Scalar, public kyber.Point) {
+	return d.long, d.pub
+}
+
+// SessionID returns the current sessionID generated by this dealer for this
+// protocol run.
+func (d *HidingDealer) SessionID() []byte {
+	return d.sessionID
+}
+
+// SetTimeout marks the end of a round, invalidating any missing (or future)
+// response.
+func (d *HidingDealer) SetTimeout() {
+	d.timeout = true
+}
+
+// PrivatePoly returns the private polynomial used to generate the shares of
+// the secret. This information SHOULD STAY PRIVATE and thus MUST never be
+// given to any third party.
+func (d *HidingDealer) PrivatePoly() *share.PriPoly {
+	return d.secretPoly
+}
+
+// HidingVerifier receives a HidingDeal from a HidingDealer and can reply with
+// a complaint.
+type HidingVerifier struct {
+	suite       Suite
+	longterm    kyber.Scalar
+	pub         kyber.Point
+	dealer      kyber.Point
+	index       uint32
+	verifiers   []kyber.Point
+	hkdfContext []byte
+	*HidingAggregator
+}
+
+// NewHidingVerifier returns a verifier for the hiding variant of the scheme.
+// The arguments are the same as for NewVerifier.
+func NewHidingVerifier(suite Suite, longterm kyber.Scalar, dealerKey kyber.Point,
+	verifiers []kyber.Point) (*HidingVerifier, error) {
+	v, err := NewVerifier(suite, longterm, dealerKey, verifiers)
+	if err != nil {
+		return nil, err
+	}
+	return &HidingVerifier{
+		suite:            suite,
+		longterm:         longterm,
+		pub:              v.pub,
+		dealer:           dealerKey,
+		index:            v.index,
+		verifiers:        verifiers,
+		hkdfContext:      v.hkdfContext,
+		HidingAggregator: NewEmptyHidingAggregator(suite, verifiers),
+	}, nil
+}
+
+// ProcessEncryptedDeal decrypts and verifies the deal received from the
+// dealer, and returns the response to broadcast, like
+// Verifier.ProcessEncryptedDeal.
+func (v *HidingVerifier) ProcessEncryptedDeal(e *EncryptedDeal) (*Response, error) {
+	buff, err := decryptDeal(v.suite, v.longterm, v.dealer, v.hkdfContext, e)
+	if err != nil {
+		return nil, err
+	}
+	d := new(HidingDeal)
+	if err := d.Unmarshal(buff, v.suite); err != nil {
+		return nil, err
+	}
+	if d.SecShare.I != v.index {
+		return nil, errors.New("vss: verifier got wrong index from deal")
+	}
+
+	sid, err := sessionID(v.suite, v.dealer, v.verifiers, d.Commitments, d.T)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Response{
+		SessionID:      sid,
+		Index:          v.index,
+		StatusApproved: StatusApproval,
+	}
+	if err = v.VerifyDeal(d, true); err != nil {
+		r.StatusApproved = StatusComplaint
+	}
+
+	if errors.Is(err, errDealAlreadyProcessed) {
+		return nil, err
+	}
+
+	if r.Signature, err = schnorr.Sign(v.suite, v.longterm, r.Hash(v.suite)); err != nil {
+		return nil, err
+	}
+
+	if err = v.addResponse(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ProcessResponse analyzes the given response. It returns an error if it's not
+// a valid response.
+func (v *HidingVerifier) ProcessResponse(resp *Response) error {
+	if v.deal == nil {
+		return ErrNoDealBeforeResponse
+	}
+	return v.verifyResponse(resp)
+}
+
+// ProcessJustification verifies the deal revealed by the dealer. If it fails,
+// the dealer is considered malicious.
+func (v *HidingVerifier) ProcessJustification(j *HidingJustification) error {
+	return v.verifyJustification(j)
+}
+
+// Commits returns the hiding commitments contained in the deal received.
+func (v *HidingVerifier) Commits() []kyber.Point {
+	return v.deal.Commitments
+}
+
+// Deal returns the deal that this verifier has received. It returns nil if
+// the deal is not certified.
+func (v *HidingVerifier) Deal() *HidingDeal {
+	if !v.DealCertified() {
+		return nil
+	}
+	return v.deal
+}
+
+// Key returns the longterm key pair this verifier is using during this protocol
+// run.
+func (v *HidingVerifier) Key() (kyber.Scalar, kyber.Point) {
+	return v.longterm, v.pub
+}
+
+// Index returns the index of the verifier in the list of participants used
+// during this run of the protocol.
+func (v *HidingVerifier) Index() uint32 {
+	return v.index
+}
+
+// SessionID returns the session id generated by the Dealer. It returns
+// an nil slice if the verifier has not received the Deal yet.
+func (v *HidingVerifier) SessionID() []byte {
+	return v.sid
+}
+
+// SetTimeout marks the end of the protocol, see Verifier.SetTimeout.
+func (v *HidingVerifier) SetTimeout() {
+	v.timeout = true
+}
+
+// HidingAggregator collects the responses about a HidingDeal. The bookkeeping
+// of the responses is shared with the Aggregator, only the verification of
+// the deals differs.
+type HidingAggregator struct {
+	*Aggregator
+	h    kyber.Point
+	deal *HidingDeal
+}
+
+func newHidingAggregator(suite Suite, dealer kyber.Point, verifiers, commitments []kyber.Point,
+	t uint32, sid []byte) *HidingAggregator {
+	return &HidingAggregator{
+		Aggregator: newAggregator(suite, dealer, verifiers, commitments, t, sid),
+		h:          hidingGenerator(suite, verifiers),
+	}
+}
+
+// NewEmptyHidingAggregator returns a structure capable of storing Responses
+// about a HidingDeal and check if the deal is certified or not.
+func NewEmptyHidingAggregator(suite Suite, verifiers []kyber.Point) *HidingAggregator {
+	return &HidingAggregator{
+		Aggregator: NewEmptyAggregator(suite, verifiers),
+		h:          hidingGenerator(suite, verifiers),
+	}
+}
+
+// VerifyDeal analyzes the deal and returns an error if it's incorrect. If
+// inclusion is true, it also returns an error if it is the second time this
+// struct analyzes a deal.
+func (a *HidingAggregator) VerifyDeal(d *HidingDeal, inclusion bool) error {
+	if a.deal != nil && inclusion {
+		return errDealAlreadyProcessed
+	}
+	if a.deal == nil {
+		a.commits = d.Commitments
+		a.sid = d.SessionID
+		a.deal = d
+		a.t = d.T
+	}
+
+	if !validT(d.T, a.verifiers) {
+		return errors.New("vss: invalid t received in Deal")
+	}
+
+	if d.T != a.t {
+		return errors.New("vss: incompatible threshold - potential attack")
+	}
+
+	if !bytes.Equal(a.sid, d.SessionID) {
+		return errors.New("vss: find different sessionIDs from Deal")
+	}
+
+	fi, gi := d.SecShare, d.RndShare
+	if fi.I != gi.I {
+		return errors.New("vss: not the same index for f and g share in Deal")
+	}
+	if fi.I >= uint32(len(a.verifiers)) {
+		return errors.New("vss: index out of bounds in Deal")
+	}
+	// g^fi * h^gi
+	figi := a.suite.Point().Add(a.suite.Point().Mul(fi.V, nil), a.suite.Point().Mul(gi.V, a.h))
+
+	commitPoly := share.NewPubPoly(a.suite, nil, d.Commitments)
+	if !figi.Equal(commitPoly.Eval(fi.I).V) {
+		return errors.New("vss: share does not verify against commitments in Deal")
+	}
+	return nil
+}
+
+func (a *HidingAggregator) verifyJustification(j *HidingJustification) error {
+	return a.justify(j.Index, func() error { return a.VerifyDeal(j.Deal, false) })
+}
+
+// RecoverHidingSecret recovers the secret shared by a HidingDealer by gathering
+// at least t deals from the verifiers. It returns an error if there is not
+// enough deals or if all deals don't have the same SessionID.
+func RecoverHidingSecret(suite Suite, deals []*HidingDeal, n, t uint32) (kyber.Scalar, error) {
+	shares := make([]*share.PriShare, len(deals))
+	for i, deal := range deals {
+		if !bytes.Equal(deal.SessionID, deals[0].SessionID) {
+			return nil, errors.New("vss: all deals need to have same session id")
+		}
+		shares[i] = deal.SecShare
+	}
+	return share.RecoverSecret(suite, shares, t, n)
+}
+
+// hidingGenerator derives the second generator h from the list of verifiers.
+// It uses the hash-to-curve of the group when it has one, otherwise it picks
+// a point from a XOF seeded with the same input, so that nobody knows the
+// discrete logarithm of h in base g.
+func hidingGenerator(suite Suite, verifiers []kyber.Point) kyber.Point {
+	var b bytes.Buffer
+	for _, v := range verifiers {
+		_, _ = v.MarshalTo(&b)
+	}
+	switch p := suite.Point().(type) {
+	case hashToCurve:
+		return p.Hash(b.Bytes(), hidingDomain)
+	case kyber.HashablePoint:
+		return p.Hash(append([]byte(hidingDomain), b.Bytes()...))
+	default:
+		return suite.Point().Pick(suite.XOF(append([]byte(hidingDomain), b.Bytes()...)))
+	}
+}
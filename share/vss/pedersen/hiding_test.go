@@ -0,0 +1,179 @@
+package vss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.dedis.ch/kyber/v4/share"
+)
+
+func genHidingAll(t *testing.T) (*HidingDealer, []*HidingVerifier) {
+	dealer, err := NewHidingDealer(suite, dealerSec, secret, verifiersPub, vssThreshold)
+	require.NoError(t, err)
+	verifiers := make([]*HidingVerifier, nbVerifiers)
+	for i := range nbVerifiers {
+		verifiers[i], err = NewHidingVerifier(suite, verifiersSec[i], dealerPub, verifiersPub)
+		require.NoError(t, err)
+	}
+	return dealer, verifiers
+}
+
+func TestHidingVSSWhole(t *testing.T) {
+	dealer, verifiers := genHidingAll(t)
+
+	// 1. dispatch deal
+	resps := make([]*Response, nbVerifiers)
+	encDeals, err := dealer.EncryptedDeals()
+	require.NoError(t, err)
+	for i, d := range encDeals {
+		require.Equal(t, ErrNoDealBeforeResponse, verifiers[i].ProcessResponse(nil))
+		resp, err := verifiers[i].ProcessEncryptedDeal(d)
+		require.NoError(t, err)
+		require.True(t, resp.StatusApproved)
+		resps[i] = resp
+	}
+
+	// 2. dispatch responses
+	for _, resp := range resps {
+		for i, v := range verifiers {
+			if resp.Index == uint32(i) {
+				continue
+			}
+			require.NoError(t, v.ProcessResponse(resp))
+		}
+		j, err := dealer.ProcessResponse(resp)
+		require.NoError(t, err)
+		require.Nil(t, j)
+	}
+
+	// 3. check certified
+	require.True(t, dealer.DealCertified())
+	deals := make([]*HidingDeal, nbVerifiers)
+	for i, v := range verifiers {
+		require.True(t, v.DealCertified())
+		require.Equal(t, dealer.SessionID(), v.SessionID())
+		deals[i] = v.Deal()
+	}
+
+	// 4. recover
+	sec, err := RecoverHidingSecret(suite, deals, nbVerifiers, vssThreshold)
+	require.NoError(t, err)
+	require.True(t, secret.Equal(sec))
+	require.True(t, secret.Equal(dealer.PrivatePoly().Secret()))
+}
+
+func TestHidingVSSCommitments(t *testing.T) {
+	dealer, _ := genHidingAll(t)
+	h := hidingGenerator(suite, verifiersPub)
+	require.False(t, h.Equal(suite.Point().Base()))
+	require.True(t, h.Equal(hidingGenerator(suite, verifiersPub)))
+
+	// the commitments don't reveal g^secret
+	commits := dealer.Commits()
+	require.Len(t, commits, int(vssThreshold))
+	require.False(t, commits[0].Equal(suite.Point().Mul(secret, nil)))
+
+	// two sharings of the same secret have unrelated commitments
+	dealer2, err := NewHidingDealer(suite, dealerSec, secret, verifiersPub, vssThreshold)
+	require.NoError(t, err)
+	require.False(t, commits[0].Equal(dealer2.Commits()[0]))
+
+	// but the shares do verify against them
+	deal, err := dealer.PlaintextDeal(2)
+	require.NoError(t, err)
+	pub := share.NewPubPoly(suite, nil, commits)
+	expected := suite.Point().Add(suite.Point().Mul(deal.SecShare.V, nil),
+		suite.Point().Mul(deal.RndShare.V, h))
+	require.True(t, expected.Equal(pub.Eval(2).V))
+}
+
+func TestHidingVSSComplaint(t *testing.T) {
+	dealer, verifiers := genHidingAll(t)
+	v := verifiers[0]
+	d := dealer.deals[0]
+
+	// the dealer gives a wrong blinding share
+	goodV := d.RndShare.V
+	d.RndShare.V = suite.Scalar().Pick(suite.RandomStream())
+	encD, err := dealer.EncryptedDeal(0)
+	require.NoError(t, err)
+	resp, err := v.ProcessEncryptedDeal(encD)
+	require.NoError(t, err)
+	require.Equal(t, StatusComplaint, resp.StatusApproved)
+	d.RndShare.V = goodV
+
+	// the deal has already been processed
+	_, err = v.ProcessEncryptedDeal(encD)
+	require.ErrorIs(t, err, errDealAlreadyProcessed)
+
+	j, err := dealer.ProcessResponse(resp)
+	require.NoError(t, err)
+	require.NotNil(t, j)
+	require.False(t, v.DealCertified())
+
+	// the justification goes through its binary encoding
+	buff, err := j.Marshal()
+	require.NoError(t, err)
+	decoded := new(HidingJustification)
+	require.NoError(t, decoded.Unmarshal(buff, suite))
+	require.Equal(t, j.Hash(suite), decoded.Hash(suite))
+
+	// invalid justification
+	bad := *decoded
+	badDeal := *decoded.Deal
+	badDeal.RndShare = &share.PriShare{I: 0, V: suite.Scalar().One()}
+	bad.Deal = &badDeal
+	require.Error(t, v.ProcessJustification(&bad))
+	require.True(t, v.badDealer)
+	v.badDealer = false
+
+	// valid justification
+	require.NoError(t, v.ProcessJustification(decoded))
+	require.True(t, v.responses[0].StatusApproved)
+
+	// no complaint for this one
+	decoded.Index = 1
+	require.Error(t, v.ProcessJustification(decoded))
+}
+
+func TestHidingVSSWrongDeal(t *testing.T) {
+	dealer, verifiers := genHidingAll(t)
+	encD, err := dealer.EncryptedDeal(1)
+	require.NoError(t, err)
+	_, err = verifiers[0].ProcessEncryptedDeal(encD)
+	require.Error(t, err)
+
+	// a Feldman deal is not accepted by a hiding verifier
+	feldman := genDealer()
+	encD, err = feldman.EncryptedDeal(0)
+	require.NoError(t, err)
+	_, err = verifiers[0].ProcessEncryptedDeal(encD)
+	require.Error(t, err)
+
+	_, err = NewHidingDealer(suite, dealerSec, secret, verifiersPub, nbVerifiers+1)
+	require.Error(t, err)
+}
+
+func TestHidingDealMarshal(t *testing.T) {
+	dealer, _ := genHidingAll(t)
+	deal, err := dealer.PlaintextDeal(0)
+	require.NoError(t, err)
+	buff, err := deal.Marshal()
+	require.NoError(t, err)
+	decoded := new(HidingDeal)
+	require.NoError(t, decoded.Unmarshal(buff, suite))
+	require.Equal(t, deal.SessionID, decoded.SessionID)
+	require.Equal(t, deal.T, decoded.T)
+	require.True(t, deal.SecShare.V.Equal(decoded.SecShare.V))
+	require.True(t, deal.RndShare.V.Equal(decoded.RndShare.V))
+	for i := range deal.Commitments {
+		require.True(t, deal.Commitments[i].Equal(decoded.Commitments[i]))
+	}
+
+	bad := *deal
+	bad.Commitments = bad.Commitments[1:]
+	buff, err = bad.Marshal()
+	require.NoError(t, err)
+	require.Error(t, decoded.Unmarshal(buff, suite))
+}
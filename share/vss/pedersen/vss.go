@@ -2,6 +2,11 @@
 // "Non-Interactive and Information-Theoretic Secure Verifiable Secret Sharing"
 // by Torben Pryds Pedersen.
 // https://link.springer.com/content/pdf/10.1007/3-540-46766-1_9.pdf
+//
+// The Dealer commits to the coefficients of the polynomial with a single
+// generator, which reveals g^secret. The HidingDealer and HidingVerifier
+// implement the two generators variant of the paper, whose commitments are
+// perfectly hiding.
 package vss
 
 import (
@@ -224,31 +229,36 @@ func (d *Dealer) EncryptedDeal(i int) (*EncryptedDeal, error) {
 	if !ok {
 		return nil, errors.New("dealer: wrong index to generate encrypted deal")
 	}
+	dealBuff, err := d.deals[i].Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return encryptDeal(d.suite, d.long, vPub, d.hkdfContext, dealBuff)
+}
+
+// encryptDeal encrypts the marshalled deal for the verifier vPub using a fresh
+// ephemeral Diffie Hellman key signed by the longterm key of the dealer.
+func encryptDeal(suite Suite, long kyber.Scalar, vPub kyber.Point, hkdfContext, dealBuff []byte) (*EncryptedDeal, error) {
 	// gen ephemeral key
-	dhSecret := d.suite.Scalar().Pick(d.suite.RandomStream())
-	dhPublic := d.suite.Point().Mul(dhSecret, nil)
+	dhSecret := suite.Scalar().Pick(suite.RandomStream())
+	dhPublic := suite.Point().Mul(dhSecret, nil)
 	// signs the public key
 	dhPublicBuff, _ := dhPublic.MarshalBinary()
-	signature, err := schnorr.Sign(d.suite, d.long, dhPublicBuff)
+	signature, err := schnorr.Sign(suite, long, dhPublicBuff)
 	if err != nil {
 		return nil, err
 	}
 	// AES128-GCM
-	pre := dhExchange(d.suite, dhSecret, vPub)
-	gcm, err := newAEAD(d.suite.Hash, pre, d.hkdfContext)
+	pre := dhExchange(suite, dhSecret, vPub)
+	gcm, err := newAEAD(suite.Hash, pre, hkdfContext)
 	if err != nil {
 		return nil, err
 	}
 
 	nonce := make([]byte, gcm.NonceSize())
-	dealBuff, err := d.deals[i].Marshal()
-	if err != nil {
-		return nil, err
-	}
-	encrypted := gcm.Seal(nil, nonce, dealBuff, d.hkdfContext)
-	dhBytes, _ := dhPublic.MarshalBinary()
+	encrypted := gcm.Seal(nil, nonce, dealBuff, hkdfContext)
 	return &EncryptedDeal{
-		DHKey:     dhBytes,
+		DHKey:     dhPublicBuff,
 		Signature: signature,
 		Cipher:    encrypted,
 	}, nil
@@ -435,29 +445,36 @@ func (v *Verifier) ProcessEncryptedDeal(e *EncryptedDeal) (*Response, error) {
 }
 
 func (v *Verifier) decryptDeal(e *EncryptedDeal) (*Deal, error) {
+	decrypted, err := decryptDeal(v.suite, v.longterm, v.dealer, v.hkdfContext, e)
+	if err != nil {
+		return nil, err
+	}
+	deal := &Deal{}
+	err = deal.Unmarshal(decrypted, v.suite)
+	return deal, err
+}
+
+// decryptDeal checks the signature of the ephemeral key of the dealer and
+// returns the decrypted deal.
+func decryptDeal(suite Suite, longterm kyber.Scalar, dealer kyber.Point, hkdfContext []byte,
+	e *EncryptedDeal) ([]byte, error) {
 	// verify signature
-	if err := schnorr.Verify(v.suite, v.dealer, e.DHKey, e.Signature); err != nil {
+	if err := schnorr.Verify(suite, dealer, e.DHKey, e.Signature); err != nil {
 		return nil, err
 	}
 
 	// compute shared key and AES526-GCM cipher
-	dhKey := v.suite.Point()
+	dhKey := suite.Point()
 	if err := dhKey.UnmarshalBinary(e.DHKey); err != nil {
 		return nil, err
 	}
-	pre := dhExchange(v.suite, v.longterm, dhKey)
-	gcm, err := newAEAD(v.suite.Hash, pre, v.hkdfContext)
+	pre := dhExchange(suite, longterm, dhKey)
+	gcm, err := newAEAD(suite.Hash, pre, hkdfContext)
 	if err != nil {
 		return nil, err
 	}
 	nonce := make([]byte, gcm.NonceSize())
-	decrypted, err := gcm.Open(nil, nonce, e.Cipher, v.hkdfContext)
-	if err != nil {
-		return nil, err
-	}
-	deal := &Deal{}
-	err = deal.Unmarshal(decrypted, v.suite)
-	return deal, err
+	return gcm.Open(nil, nonce, e.Cipher, hkdfContext)
 }
 
 // ErrNoDealBeforeResponse is an error returned if a verifier receives a
@@ -680,10 +697,16 @@ func (a *Aggregator) verifyResponse(r *Response) error {
 }
 
 func (a *Aggregator) verifyJustification(j *Justification) error {
-	if _, ok := findPub(a.verifiers, j.Index); !ok {
+	return a.justify(j.Index, func() error { return a.VerifyDeal(j.Deal, false) })
+}
+
+// justify turns the complaint of the verifier at the given index into an
+// approval if the revealed deal verifies.
+func (a *Aggregator) justify(index uint32, verifyDeal func() error) error {
+	if _, ok := findPub(a.verifiers, index); !ok {
 		return errors.New("vss: index out of bounds in justification")
 	}
-	r, ok := a.responses[j.Index]
+	r, ok := a.responses[index]
 	if !ok {
 		return errors.New("vss: no complaints received for this justification")
 	}
@@ -691,7 +714,7 @@ func (a *Aggregator) verifyJustification(j *Justification) error {
 		return errors.New("vss: justification received for an approval")
 	}
 
-	if err := a.VerifyDeal(j.Deal, false); err != nil {
+	if err := verifyDeal(); err != nil {
 		// if one justification is bad, then flag the dealer as malicious
 		a.badDealer = true
 		return err
@@ -0,0 +1,117 @@
+package vss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.dedis.ch/kyber/v4/internal/wire"
+)
+
+func wireMessages(t testing.TB) (*EncryptedDeal, *Response, *Justification) {
+	dealer, verifiers := genAll()
+	encD, err := dealer.EncryptedDeal(0)
+	require.NoError(t, err)
+	resp, err := verifiers[0].ProcessEncryptedDeal(encD)
+	require.NoError(t, err)
+	j := &Justification{
+		SessionID: dealer.sid,
+		Index:     0,
+		Deal:      dealer.deals[0],
+		Signature: randomBytes(64),
+	}
+	return encD, resp, j
+}
+
+func TestWireEncryptedDeal(t *testing.T) {
+	encD, _, _ := wireMessages(t)
+	buff, err := encD.Marshal()
+	require.NoError(t, err)
+	decoded := new(EncryptedDeal)
+	require.NoError(t, decoded.Unmarshal(buff, suite))
+	require.Equal(t, encD, decoded)
+
+	require.ErrorIs(t, decoded.Unmarshal(buff[:len(buff)-1], suite), wire.ErrShort)
+	require.ErrorIs(t, decoded.Unmarshal(append(buff, 0), suite), wire.ErrTrailing)
+
+	bad := *encD
+	bad.DHKey = randomBytes(3)
+	buff, err = bad.Marshal()
+	require.NoError(t, err)
+	require.Error(t, decoded.Unmarshal(buff, suite))
+}
+
+func TestWireResponse(t *testing.T) {
+	_, resp, _ := wireMessages(t)
+	buff, err := resp.Marshal()
+	require.NoError(t, err)
+	decoded := new(Response)
+	require.NoError(t, decoded.Unmarshal(buff))
+	require.Equal(t, resp, decoded)
+
+	// wrong message type
+	require.ErrorIs(t, new(Justification).Unmarshal(buff, suite), wire.ErrTag)
+	// unknown version
+	buff[0]++
+	require.ErrorIs(t, decoded.Unmarshal(buff), wire.ErrVersion)
+}
+
+func TestWireJustification(t *testing.T) {
+	_, _, j := wireMessages(t)
+	buff, err := j.Marshal()
+	require.NoError(t, err)
+	decoded := new(Justification)
+	require.NoError(t, decoded.Unmarshal(buff, suite))
+	require.Equal(t, j.Hash(suite), decoded.Hash(suite))
+	require.Equal(t, j.Signature, decoded.Signature)
+
+	// deal for another verifier
+	bad := *j
+	bad.Index = 1
+	buff, err = bad.Marshal()
+	require.NoError(t, err)
+	require.Error(t, decoded.Unmarshal(buff, suite))
+
+	// deal from another session
+	bad = *j
+	bad.SessionID = randomBytes(len(j.SessionID))
+	buff, err = bad.Marshal()
+	require.NoError(t, err)
+	require.Error(t, decoded.Unmarshal(buff, suite))
+
+	bad.Deal = nil
+	_, err = bad.Marshal()
+	require.Error(t, err)
+}
+
+func FuzzUnmarshal(f *testing.F) {
+	encD, resp, j := wireMessages(f)
+	for _, m := range []interface{ Marshal() ([]byte, error) }{encD, resp, j} {
+		buff, err := m.Marshal()
+		require.NoError(f, err)
+		f.Add(buff)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// anything that decodes must encode back to a message that decodes
+		// to the same encoding
+		if e := new(EncryptedDeal); e.Unmarshal(data, suite) == nil {
+			buff, err := e.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, data, buff)
+		}
+		if r := new(Response); r.Unmarshal(data) == nil {
+			buff, err := r.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, data, buff)
+		}
+		if j := new(Justification); j.Unmarshal(data, suite) == nil {
+			buff, err := j.Marshal()
+			require.NoError(t, err)
+			j2 := new(Justification)
+			require.NoError(t, j2.Unmarshal(buff, suite))
+			buff2, err := j2.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, buff, buff2)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package vss
+
+import (
+	"bytes"
+	"errors"
+
+	"go.dedis.ch/kyber/v4/internal/wire"
+)
+
+// Marshal returns the versioned binary encoding of the EncryptedDeal.
+func (e *EncryptedDeal) Marshal() ([]byte, error) {
+	enc := wire.NewEncoder(wire.TagRabinVSSEncryptedDeal)
+	enc.Point(e.DHKey)
+	enc.Bytes(e.Signature)
+	enc.Bytes(e.Cipher)
+	return enc.Finish()
+}
+
+// Unmarshal decodes an EncryptedDeal encoded with Marshal.
+func (e *EncryptedDeal) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagRabinVSSEncryptedDeal)
+	dhKey := dec.Point(suite)
+	signature := dec.Bytes()
+	cipher := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	if len(cipher) == 0 {
+		return errors.New("vss: empty cipher in encrypted deal")
+	}
+	e.DHKey = dhKey
+	e.Signature = signature
+	e.Cipher = cipher
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the Response.
+func (r *Response) Marshal() ([]byte, error) {
+	enc := wire.NewEncoder(wire.TagRabinVSSResponse)
+	enc.Bytes(r.SessionID)
+	enc.Uint32(r.Index)
+	enc.Bool(r.Approved)
+	enc.Bytes(r.Signature)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a Response encoded with Marshal.
+func (r *Response) Unmarshal(data []byte) error {
+	dec := wire.NewDecoder(data, wire.TagRabinVSSResponse)
+	sid := dec.Bytes()
+	index := dec.Uint32()
+	approved := dec.Bool()
+	signature := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	r.SessionID = sid
+	r.Index = index
+	r.Approved = approved
+	r.Signature = signature
+	return nil
+}
+
+// Marshal returns the versioned binary encoding of the Justification. The deal
+// is embedded using its own Marshal encoding.
+func (j *Justification) Marshal() ([]byte, error) {
+	if j.Deal == nil {
+		return nil, errors.New("vss: nil deal in justification")
+	}
+	dealBuff, err := j.Deal.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	enc := wire.NewEncoder(wire.TagRabinVSSJustification)
+	enc.Bytes(j.SessionID)
+	enc.Uint32(j.Index)
+	enc.Bytes(dealBuff)
+	enc.Bytes(j.Signature)
+	return enc.Finish()
+}
+
+// Unmarshal decodes a Justification encoded with Marshal. It returns an error
+// if the encoding is invalid or if the deal is not consistent with the
+// justification.
+func (j *Justification) Unmarshal(data []byte, suite Suite) error {
+	dec := wire.NewDecoder(data, wire.TagRabinVSSJustification)
+	sid := dec.Bytes()
+	index := dec.Uint32()
+	dealBuff := dec.Bytes()
+	signature := dec.Bytes()
+	if err := dec.Finish(); err != nil {
+		return err
+	}
+	deal := new(Deal)
+	if err := deal.Unmarshal(dealBuff, suite); err != nil {
+		return err
+	}
+	if err := checkDeal(deal, sid, index); err != nil {
+		return err
+	}
+	j.SessionID = sid
+	j.Index = index
+	j.Deal = deal
+	j.Signature = signature
+	return nil
+}
+
+// checkDeal performs the structural checks of a decoded deal that can be done
+// without knowing the verifiers.
+func checkDeal(d *Deal, sid []byte, index uint32) error {
+	switch {
+	case d.SecShare == nil || d.SecShare.V == nil:
+		return errors.New("vss: missing share in deal")
+	case d.RndShare == nil || d.RndShare.V == nil:
+		return errors.New("vss: missing random share in deal")
+	case d.SecShare.I != index || d.RndShare.I != index:
+		return errors.New("vss: share index does not match justification index")
+	case len(d.Commitments) == 0 || uint32(len(d.Commitments)) != d.T:
+		return errors.New("vss: invalid number of commitments in deal")
+	case !bytes.Equal(d.SessionID, sid):
+		return errors.New("vss: deal session ID does not match")
+	}
+	for _, c := range d.Commitments {
+		if c == nil {
+			return errors.New("vss: missing commitment in deal")
+		}
+	}
+	return nil
+}
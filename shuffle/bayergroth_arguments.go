@@ -0,0 +1,708 @@
+package shuffle
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/internal/msm"
+	"go.dedis.ch/kyber/v4/proof"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+)
+
+// The arguments of Bayer and Groth, "Efficient Zero-Knowledge Argument for
+// Correctness of a Shuffle", Eurocrypt 2012, on which the shuffle argument is
+// built. The notations follow the paper, with additive notations and
+// indices starting at 0 where possible.
+
+var errInvalidBayerGroth = errors.New("invalid BayerGrothProof")
+
+// commitKey is the key of the Pedersen commitments to vectors of n scalars,
+// com(a; r) = rH + sum(a_i*G_i).
+type commitKey struct {
+	g []kyber.Point
+	h kyber.Point
+}
+
+// newCommitKey returns a commitment key for vectors of n scalars, whose
+// points are derived from a fixed seed so that nobody knows their discrete
+// logarithms.
+func newCommitKey(grp kyber.Group, n int) *commitKey {
+	xof := blake2xb.New([]byte("kyber-shuffle-bayer-groth-commit-key"))
+	ck := &commitKey{h: grp.Point().Pick(xof), g: make([]kyber.Point, n)}
+	for i := range ck.g {
+		ck.g[i] = grp.Point().Pick(xof)
+	}
+	return ck
+}
+
+// commit returns the commitment to the vector a, which may be shorter than
+// the key, with the randomness r. It doesn't use multi-scalar multiplications
+// as a and r are secret.
+func (ck *commitKey) commit(grp kyber.Group, a []kyber.Scalar, r kyber.Scalar) kyber.Point {
+	c := grp.Point().Mul(r, ck.h)
+	P := grp.Point()
+	for i := range a {
+		c.Add(c, P.Mul(a[i], ck.g[i]))
+	}
+	return c
+}
+
+// check checks that sum(scalars[i]*points[i]) == com(a; r).
+func (ck *commitKey) check(grp kyber.Group, scalars []kyber.Scalar, points []kyber.Point,
+	a []kyber.Scalar, r kyber.Scalar) bool {
+	s := append([]kyber.Scalar{}, scalars...)
+	p := append([]kyber.Point{}, points...)
+	s = append(s, grp.Scalar().Neg(r))
+	p = append(p, ck.h)
+	for i := range a {
+		s = append(s, grp.Scalar().Neg(a[i]))
+		p = append(p, ck.g[i])
+	}
+	return msm.MultiMul(grp, s, p).Equal(grp.Point().Null())
+}
+
+// bgProver and bgVerifier run the arguments in the proof contexts.
+type bgProver struct {
+	grp kyber.Group
+	ck  *commitKey
+	ctx proof.ProverContext
+}
+
+type bgVerifier struct {
+	grp kyber.Group
+	ck  *commitKey
+	ctx proof.VerifierContext
+}
+
+// randomScalars returns n scalars of private randomness.
+func (p *bgProver) randomScalars(n int) ([]kyber.Scalar, error) {
+	s := make([]kyber.Scalar, n)
+	if n == 0 {
+		return s, nil
+	}
+	if err := p.ctx.PriRand(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// challenges returns n challenges.
+func challenges(grp kyber.Group, pubRand func(...any) error, n int) ([]kyber.Scalar, error) {
+	c := make([]kyber.Scalar, n)
+	data := make([]any, n)
+	for i := range c {
+		c[i] = grp.Scalar()
+		data[i] = c[i]
+	}
+	if err := pubRand(data...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// getPoints and getScalars receive n points or scalars from the prover.
+func (v *bgVerifier) getPoints(n int) ([]kyber.Point, error) {
+	p := make([]kyber.Point, n)
+	if n == 0 {
+		return p, nil
+	}
+	if err := v.ctx.Get(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (v *bgVerifier) getScalars(n int) ([]kyber.Scalar, error) {
+	s := make([]kyber.Scalar, n)
+	if n == 0 {
+		return s, nil
+	}
+	if err := v.ctx.Get(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// powers returns x^0, ..., x^(n-1).
+func powers(grp kyber.Group, x kyber.Scalar, n int) []kyber.Scalar {
+	p := make([]kyber.Scalar, n)
+	acc := grp.Scalar().One()
+	for i := range p {
+		p[i] = acc.Clone()
+		acc.Mul(acc, x)
+	}
+	return p
+}
+
+// linear returns sum(c[i]*a[i]) for vectors a[i].
+func linear(grp kyber.Group, c []kyber.Scalar, a [][]kyber.Scalar) []kyber.Scalar {
+	res := make([]kyber.Scalar, len(a[0]))
+	for j := range res {
+		res[j] = grp.Scalar().Zero()
+	}
+	tmp := grp.Scalar()
+	for i := range a {
+		for j := range res {
+			res[j].Add(res[j], tmp.Mul(c[i], a[i][j]))
+		}
+	}
+	return res
+}
+
+// dot returns sum(c[i]*s[i]).
+func dot(grp kyber.Group, c, s []kyber.Scalar) kyber.Scalar {
+	res := grp.Scalar().Zero()
+	tmp := grp.Scalar()
+	for i := range s {
+		res.Add(res, tmp.Mul(c[i], s[i]))
+	}
+	return res
+}
+
+// bilinear returns the map a * b = sum(a_j*b_j*y^(j+1)) of the zero argument.
+func bilinear(grp kyber.Group, a, b []kyber.Scalar, y kyber.Scalar) kyber.Scalar {
+	res := grp.Scalar().Zero()
+	yj := grp.Scalar().One()
+	tmp := grp.Scalar()
+	for j := range a {
+		yj.Mul(yj, y)
+		res.Add(res, tmp.Mul(a[j], b[j]).Mul(tmp, yj))
+	}
+	return res
+}
+
+func constVector(grp kyber.Group, c kyber.Scalar, n int) []kyber.Scalar {
+	v := make([]kyber.Scalar, n)
+	for i := range v {
+		v[i] = c.Clone()
+	}
+	return v
+}
+
+// commitConst returns com(c, ..., c; 0) for vectors of length n.
+func (ck *commitKey) commitConst(grp kyber.Group, c kyber.Scalar, n int) kyber.Point {
+	sum := grp.Point().Null()
+	for i := range n {
+		sum.Add(sum, ck.g[i])
+	}
+	return sum.Mul(c, sum)
+}
+
+// sumMul returns sum(s[i]*p[i]) for public scalars.
+func sumMul(grp kyber.Group, s []kyber.Scalar, p []kyber.Point) kyber.Point {
+	return msm.MultiMul(grp, s, p)
+}
+
+////////// Zero argument //////////
+
+// zeroProve proves that sum(a_i * b_i) == 0 for the committed vectors a_i
+// and b_i, i < m, with the bilinear map of y. The commitments are known to
+// the verifier.
+func (p *bgProver) zeroProve(a [][]kyber.Scalar, r []kyber.Scalar, b [][]kyber.Scalar,
+	s []kyber.Scalar, y kyber.Scalar) error {
+	grp := p.grp
+	m, n := len(a), len(a[0])
+
+	// a_0 and b_m blind the vectors a_1..a_m and b_0..b_(m-1) of the paper
+	a0, err := p.randomScalars(n + 1)
+	if err != nil {
+		return err
+	}
+	bm, err := p.randomScalars(n + 1)
+	if err != nil {
+		return err
+	}
+	aFull := append([][]kyber.Scalar{a0[:n]}, a...)
+	rFull := append([]kyber.Scalar{a0[n]}, r...)
+	bFull := append(append([][]kyber.Scalar{}, b...), bm[:n])
+	sFull := append(append([]kyber.Scalar{}, s...), bm[n])
+
+	// d_k = sum(a_i * b_j) for j = m-k+i, with d_(m+1) = 0 by hypothesis
+	t, err := p.randomScalars(2*m + 1)
+	if err != nil {
+		return err
+	}
+	t[m+1] = grp.Scalar().Zero()
+	cD := make([]kyber.Point, 0, 2*m)
+	for k := 0; k <= 2*m; k++ {
+		if k == m+1 {
+			continue
+		}
+		d := grp.Scalar().Zero()
+		for i := 0; i <= m; i++ {
+			if j := m - k + i; j >= 0 && j <= m {
+				d.Add(d, bilinear(grp, aFull[i], bFull[j], y))
+			}
+		}
+		cD = append(cD, p.ck.commit(grp, []kyber.Scalar{d}, t[k]))
+	}
+	commits := []kyber.Point{
+		p.ck.commit(grp, aFull[0], rFull[0]),
+		p.ck.commit(grp, bFull[m], sFull[m]),
+	}
+	if err := p.ctx.Put(append(commits, cD...)); err != nil {
+		return err
+	}
+
+	x, err := challenges(grp, p.ctx.PubRand, 1)
+	if err != nil {
+		return err
+	}
+	xPow := powers(grp, x[0], 2*m+1)
+	xRev := make([]kyber.Scalar, m+1)
+	for j := range xRev {
+		xRev[j] = xPow[m-j]
+	}
+	if err := p.ctx.Put(linear(grp, xPow[:m+1], aFull)); err != nil {
+		return err
+	}
+	if err := p.ctx.Put(linear(grp, xRev, bFull)); err != nil {
+		return err
+	}
+	return p.ctx.Put([]kyber.Scalar{
+		dot(grp, xPow[:m+1], rFull),
+		dot(grp, xRev, sFull),
+		dot(grp, xPow, t),
+	})
+}
+
+// zeroVerify verifies the zero argument for the commitments cA to a_i and cB
+// to b_i.
+func (v *bgVerifier) zeroVerify(cA, cB []kyber.Point, y kyber.Scalar, n int) error {
+	grp := v.grp
+	m := len(cA)
+	commits, err := v.getPoints(2 + 2*m)
+	if err != nil {
+		return err
+	}
+	cAFull := append([]kyber.Point{commits[0]}, cA...)
+	cBFull := append(append([]kyber.Point{}, cB...), commits[1])
+	cD := commits[2:]
+
+	x, err := challenges(grp, v.ctx.PubRand, 1)
+	if err != nil {
+		return err
+	}
+	a, err := v.getScalars(n)
+	if err != nil {
+		return err
+	}
+	b, err := v.getScalars(n)
+	if err != nil {
+		return err
+	}
+	rst, err := v.getScalars(3)
+	if err != nil {
+		return err
+	}
+
+	xPow := powers(grp, x[0], 2*m+1)
+	xRev := make([]kyber.Scalar, m+1)
+	for j := range xRev {
+		xRev[j] = xPow[m-j]
+	}
+	xD := append(append([]kyber.Scalar{}, xPow[:m+1]...), xPow[m+2:]...)
+	if !v.ck.check(grp, xPow[:m+1], cAFull, a, rst[0]) ||
+		!v.ck.check(grp, xRev, cBFull, b, rst[1]) ||
+		!v.ck.check(grp, xD, cD, []kyber.Scalar{bilinear(grp, a, b, y)}, rst[2]) {
+		return errInvalidBayerGroth
+	}
+	return nil
+}
+
+////////// Hadamard product argument //////////
+
+// hadamardProve proves that b is the entry-wise product of the m >= 2
+// committed vectors a_i, b being committed with the randomness s.
+func (p *bgProver) hadamardProve(a [][]kyber.Scalar, r []kyber.Scalar, b []kyber.Scalar, s kyber.Scalar) error {
+	grp := p.grp
+	m, n := len(a), len(a[0])
+
+	// b_i = a_0 o ... o a_i, committed with s_i
+	bs := make([][]kyber.Scalar, m)
+	bs[0] = a[0]
+	for i := 1; i < m; i++ {
+		bs[i] = make([]kyber.Scalar, n)
+		for j := range n {
+			bs[i][j] = grp.Scalar().Mul(bs[i-1][j], a[i][j])
+		}
+	}
+	ss, err := p.randomScalars(m)
+	if err != nil {
+		return err
+	}
+	ss[0], ss[m-1] = r[0], s
+	cB := make([]kyber.Point, m-2)
+	for i := 1; i < m-1; i++ {
+		cB[i-1] = p.ck.commit(grp, bs[i], ss[i])
+	}
+	if m > 2 {
+		if err := p.ctx.Put(cB); err != nil {
+			return err
+		}
+	}
+
+	xy, err := challenges(grp, p.ctx.PubRand, 2)
+	if err != nil {
+		return err
+	}
+	x, y := xy[0], xy[1]
+	xPow := powers(grp, x, m)
+
+	// sum(a_(i+1) * x^i b_(i-1)) - (-1) * sum(x^i b_i) == 0
+	za := append(append([][]kyber.Scalar{}, a[1:]...), constVector(grp, grp.Scalar().SetInt64(-1), n))
+	zr := append(append([]kyber.Scalar{}, r[1:]...), grp.Scalar().Zero())
+	zb := make([][]kyber.Scalar, m)
+	zs := make([]kyber.Scalar, m)
+	for i := 1; i < m; i++ {
+		zb[i-1] = linear(grp, xPow[i:i+1], bs[i-1:i])
+		zs[i-1] = grp.Scalar().Mul(xPow[i], ss[i-1])
+	}
+	zb[m-1] = linear(grp, xPow[1:], bs[1:])
+	zs[m-1] = dot(grp, xPow[1:], ss[1:])
+	return p.zeroProve(za, zr, zb, zs, y)
+}
+
+// hadamardVerify verifies the Hadamard product argument for the commitments
+// cA to the vectors and cb to their product.
+func (v *bgVerifier) hadamardVerify(cA []kyber.Point, cb kyber.Point, n int) error {
+	grp := v.grp
+	m := len(cA)
+	cB := make([]kyber.Point, m)
+	cB[0], cB[m-1] = cA[0], cb
+	inner, err := v.getPoints(m - 2)
+	if err != nil {
+		return err
+	}
+	copy(cB[1:], inner)
+
+	xy, err := challenges(grp, v.ctx.PubRand, 2)
+	if err != nil {
+		return err
+	}
+	x, y := xy[0], xy[1]
+	xPow := powers(grp, x, m)
+
+	zA := append(append([]kyber.Point{}, cA[1:]...),
+		v.ck.commitConst(grp, grp.Scalar().SetInt64(-1), n))
+	zB := make([]kyber.Point, m)
+	for i := 1; i < m; i++ {
+		zB[i-1] = grp.Point().Mul(xPow[i], cB[i-1])
+	}
+	zB[m-1] = sumMul(grp, xPow[1:], cB[1:])
+	return v.zeroVerify(zA, zB, y, n)
+}
+
+////////// Single value product argument //////////
+
+// singleValueProve proves that the product of the n >= 2 entries of the
+// vector a, committed with the randomness r, is b.
+func (p *bgProver) singleValueProve(a []kyber.Scalar, r kyber.Scalar) error {
+	grp := p.grp
+	n := len(a)
+
+	// b_i = a_0 ... a_i
+	bs := make([]kyber.Scalar, n)
+	bs[0] = a[0]
+	for i := 1; i < n; i++ {
+		bs[i] = grp.Scalar().Mul(bs[i-1], a[i])
+	}
+	rnd, err := p.randomScalars(2*n + 1)
+	if err != nil {
+		return err
+	}
+	d, delta := rnd[:n], rnd[n:2*n]
+	rd, s1, sx := rnd[2*n], grp.Scalar(), grp.Scalar()
+	if err := p.ctx.PriRand(s1, sx); err != nil {
+		return err
+	}
+	delta[0] = d[0]
+	delta[n-1] = grp.Scalar().Zero()
+
+	small := make([]kyber.Scalar, n-1)
+	big := make([]kyber.Scalar, n-1)
+	tmp := grp.Scalar()
+	for i := range n - 1 {
+		small[i] = grp.Scalar().Mul(delta[i], d[i+1])
+		small[i].Neg(small[i])
+		big[i] = grp.Scalar().Sub(delta[i+1], tmp.Mul(a[i+1], delta[i]))
+		big[i].Sub(big[i], tmp.Mul(bs[i], d[i+1]))
+	}
+	err = p.ctx.Put([]kyber.Point{
+		p.ck.commit(grp, d, rd),
+		p.ck.commit(grp, small, s1),
+		p.ck.commit(grp, big, sx),
+	})
+	if err != nil {
+		return err
+	}
+
+	x, err := challenges(grp, p.ctx.PubRand, 1)
+	if err != nil {
+		return err
+	}
+	aT := make([]kyber.Scalar, n)
+	bT := make([]kyber.Scalar, n)
+	for i := range n {
+		aT[i] = grp.Scalar().Mul(x[0], a[i])
+		aT[i].Add(aT[i], d[i])
+		bT[i] = grp.Scalar().Mul(x[0], bs[i])
+		bT[i].Add(bT[i], delta[i])
+	}
+	if err := p.ctx.Put(aT); err != nil {
+		return err
+	}
+	if err := p.ctx.Put(bT); err != nil {
+		return err
+	}
+	rT := grp.Scalar().Mul(x[0], r)
+	sT := grp.Scalar().Mul(x[0], sx)
+	return p.ctx.Put([]kyber.Scalar{rT.Add(rT, rd), sT.Add(sT, s1)})
+}
+
+// singleValueVerify verifies the single value product argument for the
+// commitment ca to a vector of n entries whose product is b.
+func (v *bgVerifier) singleValueVerify(ca kyber.Point, b kyber.Scalar, n int) error {
+	grp := v.grp
+	commits, err := v.getPoints(3)
+	if err != nil {
+		return err
+	}
+	x, err := challenges(grp, v.ctx.PubRand, 1)
+	if err != nil {
+		return err
+	}
+	aT, err := v.getScalars(n)
+	if err != nil {
+		return err
+	}
+	bT, err := v.getScalars(n)
+	if err != nil {
+		return err
+	}
+	rs, err := v.getScalars(2)
+	if err != nil {
+		return err
+	}
+
+	e := make([]kyber.Scalar, n-1)
+	tmp := grp.Scalar()
+	for i := range n - 1 {
+		e[i] = grp.Scalar().Mul(x[0], bT[i+1])
+		e[i].Sub(e[i], tmp.Mul(bT[i], aT[i+1]))
+	}
+	one := grp.Scalar().One()
+	if !v.ck.check(grp, []kyber.Scalar{x[0], one}, []kyber.Point{ca, commits[0]}, aT, rs[0]) ||
+		!v.ck.check(grp, []kyber.Scalar{x[0], one}, []kyber.Point{commits[2], commits[1]}, e, rs[1]) ||
+		!bT[0].Equal(aT[0]) ||
+		!bT[n-1].Equal(tmp.Mul(x[0], b)) {
+		return errInvalidBayerGroth
+	}
+	return nil
+}
+
+////////// Product argument //////////
+
+// productProve proves that the product of all the entries of the committed
+// rows a_i is b.
+func (p *bgProver) productProve(a [][]kyber.Scalar, r []kyber.Scalar) error {
+	if len(a) == 1 {
+		return p.singleValueProve(a[0], r[0])
+	}
+	grp := p.grp
+	b := make([]kyber.Scalar, len(a[0]))
+	for j := range b {
+		b[j] = a[0][j].Clone()
+		for i := 1; i < len(a); i++ {
+			b[j].Mul(b[j], a[i][j])
+		}
+	}
+	s, err := p.randomScalars(1)
+	if err != nil {
+		return err
+	}
+	if err := p.ctx.Put(p.ck.commit(grp, b, s[0])); err != nil {
+		return err
+	}
+	if err := p.hadamardProve(a, r, b, s[0]); err != nil {
+		return err
+	}
+	return p.singleValueProve(b, s[0])
+}
+
+// productVerify verifies the product argument for the commitments cA to
+// rows of n entries whose product is b.
+func (v *bgVerifier) productVerify(cA []kyber.Point, b kyber.Scalar, n int) error {
+	if len(cA) == 1 {
+		return v.singleValueVerify(cA[0], b, n)
+	}
+	cb := v.grp.Point()
+	if err := v.ctx.Get(cb); err != nil {
+		return err
+	}
+	if err := v.hadamardVerify(cA, cb, n); err != nil {
+		return err
+	}
+	return v.singleValueVerify(cb, b, n)
+}
+
+////////// Multi-exponentiation argument //////////
+
+// elgamal is the encryption E(M; r) = (rG, M + rH) of the shuffled pairs.
+type elgamal struct {
+	G, H kyber.Point
+}
+
+// multiExpProve proves that sum(C'_i^a_i) + E(0; rho) is the ciphertext C,
+// for the rows C'_i of ciphertexts (X, Y) and the committed rows a_i, with
+// a_i^C'_i = sum(a_ij*C'_ij).
+func (p *bgProver) multiExpProve(eg elgamal, X, Y [][]kyber.Point, a [][]kyber.Scalar,
+	r []kyber.Scalar, rho kyber.Scalar) error {
+	grp := p.grp
+	m, n := len(a), len(a[0])
+
+	// a_0 is random, and the rows are a_1..a_m as in the paper
+	a0, err := p.randomScalars(n + 1)
+	if err != nil {
+		return err
+	}
+	aFull := append([][]kyber.Scalar{a0[:n]}, a...)
+	rFull := append([]kyber.Scalar{a0[n]}, r...)
+	rnd, err := p.randomScalars(3 * 2 * m)
+	if err != nil {
+		return err
+	}
+	b, s, tau := rnd[:2*m], rnd[2*m:4*m], rnd[4*m:]
+	b[m], s[m], tau[m] = grp.Scalar().Zero(), grp.Scalar().Zero(), rho
+
+	// E_k = E(b_k*B; tau_k) + sum(C'_i^a_j) for j = k-m+i, where the
+	// ciphertext C'_i is the row i-1 of X and Y
+	cB := make([]kyber.Point, 0, 2*m-1)
+	EX := make([]kyber.Point, 0, 2*m-1)
+	EY := make([]kyber.Point, 0, 2*m-1)
+	P := grp.Point()
+	for k := range 2 * m {
+		if k == m {
+			continue
+		}
+		cB = append(cB, p.ck.commit(grp, b[k:k+1], s[k]))
+		ex := grp.Point().Mul(tau[k], eg.G)
+		ey := grp.Point().Mul(tau[k], eg.H)
+		ey.Add(ey, P.Mul(b[k], nil))
+		for i := 1; i <= m; i++ {
+			j := k - m + i
+			if j < 0 || j > m {
+				continue
+			}
+			for l := range n {
+				ex.Add(ex, P.Mul(aFull[j][l], X[i-1][l]))
+				ey.Add(ey, P.Mul(aFull[j][l], Y[i-1][l]))
+			}
+		}
+		EX = append(EX, ex)
+		EY = append(EY, ey)
+	}
+	if err := p.ctx.Put(p.ck.commit(grp, aFull[0], rFull[0])); err != nil {
+		return err
+	}
+	if err := p.ctx.Put(cB); err != nil {
+		return err
+	}
+	if err := p.ctx.Put(EX); err != nil {
+		return err
+	}
+	if err := p.ctx.Put(EY); err != nil {
+		return err
+	}
+
+	x, err := challenges(grp, p.ctx.PubRand, 1)
+	if err != nil {
+		return err
+	}
+	xPow := powers(grp, x[0], 2*m)
+	if err := p.ctx.Put(linear(grp, xPow[:m+1], aFull)); err != nil {
+		return err
+	}
+	return p.ctx.Put([]kyber.Scalar{
+		dot(grp, xPow[:m+1], rFull),
+		dot(grp, xPow, b),
+		dot(grp, xPow, s),
+		dot(grp, xPow, tau),
+	})
+}
+
+// multiExpVerify verifies the multi-exponentiation argument for the rows of
+// ciphertexts X and Y, the ciphertext (CX, CY) and the commitments cA.
+func (v *bgVerifier) multiExpVerify(eg elgamal, X, Y [][]kyber.Point, CX, CY kyber.Point,
+	cA []kyber.Point) error {
+	grp := v.grp
+	m, n := len(X), len(X[0])
+
+	cA0 := grp.Point()
+	if err := v.ctx.Get(cA0); err != nil {
+		return err
+	}
+	cB, err := v.getPoints(2*m - 1)
+	if err != nil {
+		return err
+	}
+	EX, err := v.getPoints(2*m - 1)
+	if err != nil {
+		return err
+	}
+	EY, err := v.getPoints(2*m - 1)
+	if err != nil {
+		return err
+	}
+	x, err := challenges(grp, v.ctx.PubRand, 1)
+	if err != nil {
+		return err
+	}
+	a, err := v.getScalars(n)
+	if err != nil {
+		return err
+	}
+	rbst, err := v.getScalars(4)
+	if err != nil {
+		return err
+	}
+	r, b, s, tau := rbst[0], rbst[1], rbst[2], rbst[3]
+
+	xPow := powers(grp, x[0], 2*m)
+	xE := append(append([]kyber.Scalar{}, xPow[:m]...), xPow[m+1:]...)
+
+	// cA_0 + sum(x^i cA_i) == com(a; r)
+	if !v.ck.check(grp, xPow[:m+1], append([]kyber.Point{cA0}, cA...), a, r) {
+		return errInvalidBayerGroth
+	}
+	// sum(x^k cB_k) == com(b; s)
+	if !v.ck.check(grp, xE, cB, []kyber.Scalar{b}, s) {
+		return errInvalidBayerGroth
+	}
+
+	// sum(x^k E_k) + x^m C == E(bB; tau) + sum(x^(m-i) C'_i^a)
+	scalars := make([]kyber.Scalar, 0, 2*m+1+m*n)
+	for _, c := range xE {
+		scalars = append(scalars, c)
+	}
+	scalars = append(scalars, xPow[m], grp.Scalar().Neg(tau))
+	for i := 1; i <= m; i++ {
+		for l := range n {
+			c := grp.Scalar().Mul(xPow[m-i], a[l])
+			scalars = append(scalars, c.Neg(c))
+		}
+	}
+	pointsX := append(append([]kyber.Point{}, EX...), CX, eg.G)
+	pointsY := append(append([]kyber.Point{}, EY...), CY, eg.H)
+	for i := range m {
+		pointsX = append(pointsX, X[i]...)
+		pointsY = append(pointsY, Y[i]...)
+	}
+	null := grp.Point().Null()
+	base := grp.Point().Base()
+	if !msm.MultiMul(grp, scalars, pointsX).Equal(null) ||
+		!msm.MultiMul(grp, scalars, pointsY).Equal(grp.Point().Mul(b, base)) {
+		return errInvalidBayerGroth
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package shuffle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/proof"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+)
+
+func TestBayerGroth(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+
+	// 2 gives a single row, the others the Hadamard argument, and the sizes
+	// which aren't m*n with n = ceil(sqrt(k)) are padded
+	for _, k := range []int{2, 3, 4, 7, 12, 30, 31} {
+		h, c := setShuffleKeyPairs(rand, suite, k)
+		x, y := elGamalEncryptPair(rand, suite, c, h, k)
+
+		Xbar, Ybar, prover := BayerGrothShuffle(suite, nil, h, x, y, rand)
+		prf, err := proof.HashProve(suite, "BayerGroth", prover)
+		require.NoError(t, err)
+
+		verifier := BayerGrothVerifier(suite, nil, h, x, y, Xbar, Ybar)
+		require.NoError(t, proof.HashVerify(suite, "BayerGroth", verifier, prf), "k=%d", k)
+	}
+}
+
+func TestBayerGrothDims(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+	G := suite.Point().Pick(rand)
+	h, c := setShuffleKeyPairs(rand, suite, 12)
+	x, y := elGamalEncryptPair(rand, suite, c, h, 12)
+
+	for _, dims := range [][2]int{{1, 12}, {2, 6}, {3, 4}, {6, 2}} {
+		bg := new(BayerGroth).InitDims(suite, dims[0], dims[1])
+		pi := []int{3, 7, 0, 11, 5, 1, 9, 2, 10, 4, 8, 6}
+		beta := make([]kyber.Scalar, 12)
+		Xbar := make([]kyber.Point, 12)
+		Ybar := make([]kyber.Point, 12)
+		for i := range beta {
+			beta[i] = suite.Scalar().Pick(rand)
+		}
+		for i := range pi {
+			Xbar[i] = suite.Point().Mul(beta[pi[i]], G)
+			Xbar[i].Add(Xbar[i], x[pi[i]])
+			Ybar[i] = suite.Point().Mul(beta[pi[i]], h)
+			Ybar[i].Add(Ybar[i], y[pi[i]])
+		}
+
+		prf, err := proof.HashProve(suite, "BayerGroth", func(ctx proof.ProverContext) error {
+			return bg.Prove(pi, G, h, beta, x, y, ctx)
+		})
+		require.NoError(t, err)
+		err = proof.HashVerify(suite, "BayerGroth", func(ctx proof.VerifierContext) error {
+			return bg.Verify(G, h, x, y, Xbar, Ybar, ctx)
+		}, prf)
+		require.NoError(t, err, "dims %v", dims)
+	}
+}
+
+func TestBayerGrothInvalid(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+	k := 12
+	h, c := setShuffleKeyPairs(rand, suite, k)
+	x, y := elGamalEncryptPair(rand, suite, c, h, k)
+
+	Xbar, Ybar, prover := BayerGrothShuffle(suite, nil, h, x, y, rand)
+	prf, err := proof.HashProve(suite, "BayerGroth", prover)
+	require.NoError(t, err)
+
+	verify := func(x, y, Xbar, Ybar []kyber.Point, prf []byte) error {
+		verifier := BayerGrothVerifier(suite, nil, h, x, y, Xbar, Ybar)
+		return proof.HashVerify(suite, "BayerGroth", verifier, prf)
+	}
+	require.NoError(t, verify(x, y, Xbar, Ybar, prf))
+
+	// Swapping half of two pairs
+	bad := append([]kyber.Point{}, Xbar...)
+	bad[0], bad[1] = bad[1], bad[0]
+	require.Error(t, verify(x, y, bad, Ybar, prf))
+
+	// Changing the plaintext of a pair
+	bad = append([]kyber.Point{}, Ybar...)
+	bad[5] = suite.Point().Add(bad[5], suite.Point().Base())
+	require.Error(t, verify(x, y, Xbar, bad, prf))
+
+	// Changing an input
+	bad = append([]kyber.Point{}, y...)
+	bad[3] = suite.Point().Add(bad[3], suite.Point().Base())
+	require.Error(t, verify(x, bad, Xbar, Ybar, prf))
+
+	// Tampering with the proof
+	for _, i := range []int{0, len(prf) / 2, len(prf) - 1} {
+		badPrf := append([]byte{}, prf...)
+		badPrf[i] ^= 1
+		require.Error(t, verify(x, y, Xbar, Ybar, badPrf))
+	}
+	require.Error(t, verify(x, y, Xbar, Ybar, prf[:len(prf)-1]))
+}
+
+// A prover that doesn't apply a permutation can't produce a proof.
+func TestBayerGrothNotPermutation(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+	k := 6
+	h, c := setShuffleKeyPairs(rand, suite, k)
+	x, y := elGamalEncryptPair(rand, suite, c, h, k)
+
+	bg := new(BayerGroth).Init(suite, k)
+	pi := []int{1, 1, 2, 3, 4, 5}
+	beta := make([]kyber.Scalar, k)
+	Xbar := make([]kyber.Point, k)
+	Ybar := make([]kyber.Point, k)
+	for i := range beta {
+		beta[i] = suite.Scalar().Pick(rand)
+	}
+	for i := range pi {
+		Xbar[i] = suite.Point().Mul(beta[pi[i]], nil)
+		Xbar[i].Add(Xbar[i], x[pi[i]])
+		Ybar[i] = suite.Point().Mul(beta[pi[i]], h)
+		Ybar[i].Add(Ybar[i], y[pi[i]])
+	}
+	prf, err := proof.HashProve(suite, "BayerGroth", func(ctx proof.ProverContext) error {
+		return bg.Prove(pi, nil, h, beta, x, y, ctx)
+	})
+	require.NoError(t, err)
+	err = proof.HashVerify(suite, "BayerGroth", func(ctx proof.VerifierContext) error {
+		return bg.Verify(nil, h, x, y, Xbar, Ybar, ctx)
+	}, prf)
+	require.Error(t, err)
+}
+
+func TestBayerGrothTranscript(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+	k := 9
+	h, c := setShuffleKeyPairs(rand, suite, k)
+	x, y := elGamalEncryptPair(rand, suite, c, h, k)
+
+	Xbar, Ybar, prover := BayerGrothShuffle(suite, nil, h, x, y, rand)
+	newTranscript := func() *transcript.Transcript {
+		tr := transcript.New(suite, "mix")
+		for i := range k {
+			require.NoError(t, tr.AppendPoint("x", x[i]))
+			require.NoError(t, tr.AppendPoint("y", y[i]))
+			require.NoError(t, tr.AppendPoint("xbar", Xbar[i]))
+			require.NoError(t, tr.AppendPoint("ybar", Ybar[i]))
+		}
+		return tr
+	}
+	prf, err := proof.TranscriptProve(suite, newTranscript(), prover)
+	require.NoError(t, err)
+
+	verifier := BayerGrothVerifier(suite, nil, h, x, y, Xbar, Ybar)
+	require.NoError(t, proof.TranscriptVerify(suite, newTranscript(), verifier, prf))
+	require.Error(t, proof.TranscriptVerify(suite, transcript.New(suite, "mix"), verifier, prf))
+}
+
+func TestBayerGrothSize(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+	k := 144
+	h, c := setShuffleKeyPairs(rand, suite, k)
+	x, y := elGamalEncryptPair(rand, suite, c, h, k)
+
+	_, _, prover := BayerGrothShuffle(suite, nil, h, x, y, rand)
+	bgPrf, err := proof.HashProve(suite, "BayerGroth", prover)
+	require.NoError(t, err)
+	_, _, prover = Shuffle(suite, nil, h, x, y, rand)
+	neffPrf, err := proof.HashProve(suite, "PairShuffle", prover)
+	require.NoError(t, err)
+
+	// with m = n = 12, the proof holds 11m+2 points and 5n+9 scalars
+	require.Len(t, bgPrf, (11*12+2)*suite.PointLen()+(5*12+9)*suite.ScalarLen())
+	require.Less(t, 5*len(bgPrf), len(neffPrf))
+}
+
+// The proof stays sublinear for sizes without a divisor close to their square
+// root, which are padded.
+func TestBayerGrothSizePadded(t *testing.T) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+	for _, k := range []int{101, 2 * 53} {
+		h, c := setShuffleKeyPairs(rand, suite, k)
+		x, y := elGamalEncryptPair(rand, suite, c, h, k)
+
+		Xbar, Ybar, prover := BayerGrothShuffle(suite, nil, h, x, y, rand)
+		prf, err := proof.HashProve(suite, "BayerGroth", prover)
+		require.NoError(t, err)
+		verifier := BayerGrothVerifier(suite, nil, h, x, y, Xbar, Ybar)
+		require.NoError(t, proof.HashVerify(suite, "BayerGroth", verifier, prf), "k=%d", k)
+		Xbar[0], Xbar[k-1] = Xbar[k-1], Xbar[0]
+		verifier = BayerGrothVerifier(suite, nil, h, x, y, Xbar, Ybar)
+		require.Error(t, proof.HashVerify(suite, "BayerGroth", verifier, prf), "k=%d", k)
+
+		// with n = 11 columns and m = 10 rows
+		require.Len(t, prf, (11*10+2)*suite.PointLen()+(5*11+9)*suite.ScalarLen(), "k=%d", k)
+	}
+}
+
+func benchmarkBayerGroth(b *testing.B, k int, verify bool) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+	h, c := setShuffleKeyPairs(rand, suite, k)
+	x, y := elGamalEncryptPair(rand, suite, c, h, k)
+	Xbar, Ybar, prover := BayerGrothShuffle(suite, nil, h, x, y, rand)
+	prf, err := proof.HashProve(suite, "BayerGroth", prover)
+	require.NoError(b, err)
+	verifier := BayerGrothVerifier(suite, nil, h, x, y, Xbar, Ybar)
+
+	for b.Loop() {
+		if verify {
+			err = proof.HashVerify(suite, "BayerGroth", verifier, prf)
+		} else {
+			_, err = proof.HashProve(suite, "BayerGroth", prover)
+		}
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkBayerGrothProve100(b *testing.B)   { benchmarkBayerGroth(b, 100, false) }
+func BenchmarkBayerGrothVerify100(b *testing.B)  { benchmarkBayerGroth(b, 100, true) }
+func BenchmarkBayerGrothProve1024(b *testing.B)  { benchmarkBayerGroth(b, 1024, false) }
+func BenchmarkBayerGrothVerify1024(b *testing.B) { benchmarkBayerGroth(b, 1024, true) }
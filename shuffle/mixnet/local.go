@@ -0,0 +1,88 @@
+package mixnet
+
+import (
+	"errors"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/elgamal"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/shuffle"
+)
+
+// Local runs a mixnet whose servers are all in the same process, passing the
+// ciphertexts from one server to the next in memory. It is meant for tests
+// and examples.
+type Local struct {
+	Params  *Params
+	Servers []*Server
+}
+
+// NewLocal returns a mixnet of n servers. If t is positive, the private key
+// is shared among the servers with a threshold t by a trusted dealer, which
+// the servers of a real deployment would replace with a distributed key
+// generation; otherwise the mixnet doesn't decrypt its output and the
+// private key is returned, or nil.
+func NewLocal(suite Suite, session []byte, n, t int, opts ...shuffle.Option) (*Local, kyber.Scalar, error) {
+	if n < 1 || t > n {
+		return nil, nil, errors.New("mixnet: invalid number of servers or threshold")
+	}
+	rand := suite.RandomStream()
+	private := suite.Scalar().Pick(rand)
+	params := &Params{
+		Session: session,
+		Public:  suite.Point().Mul(private, nil),
+		Servers: n,
+	}
+	var shares []*share.PriShare
+	if t > 0 {
+		poly := share.NewPriPoly(suite, uint32(t), private, rand)
+		params.Shares = poly.Commit(nil)
+		shares = poly.Shares(uint32(n))
+		private = nil
+	}
+
+	l := &Local{Params: params, Servers: make([]*Server, n)}
+	for i := range l.Servers {
+		var priShare *share.PriShare
+		if shares != nil {
+			priShare = shares[i]
+		}
+		server, err := NewServer(suite, params, i, priShare, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		l.Servers[i] = server
+	}
+	return l, private, nil
+}
+
+// Run mixes the input through all the servers and, if the mixnet decrypts
+// its output, has every server decrypt the output of the last hop and keeps
+// the decryption shares that pass VerifyDecryptionShare. It returns the
+// transcript of the mix.
+func (l *Local) Run(input []*elgamal.Ciphertext) (*Transcript, error) {
+	tr := &Transcript{Input: input}
+	cts := input
+	for _, server := range l.Servers {
+		hop, err := server.Mix(cts)
+		if err != nil {
+			return nil, err
+		}
+		tr.Hops = append(tr.Hops, hop)
+		cts = hop.Output
+	}
+	if l.Params.Shares == nil {
+		return tr, nil
+	}
+	for _, server := range l.Servers {
+		ds, err := server.Decrypt(cts)
+		if err != nil {
+			return nil, err
+		}
+		if VerifyDecryptionShare(server.suite, l.Params, cts, ds) != nil {
+			continue
+		}
+		tr.Decryptions = append(tr.Decryptions, ds)
+	}
+	return tr, nil
+}
@@ -0,0 +1,345 @@
+// Package mixnet implements a verifiable re-encryption mixnet on top of the
+// shuffles of package shuffle. A list of ElGamal ciphertexts, encrypted to the
+// public key of the mixnet, goes through a chain of servers: each of them
+// shuffles and re-randomizes the list it receives with shuffle.Shuffle and
+// publishes the result, its hop, with a proof that it is a shuffle of its
+// input. As long as one of the servers keeps its permutation secret, nobody
+// can link the ciphertexts of the output to those of the input.
+//
+// When the private key of the mixnet is shared among the servers, typically
+// with a distributed key generation (see kyber/share/dkg), at least a
+// threshold of them decrypt the output of the last hop with the partial
+// decryptions and DLEQ proofs of package encrypt/threshold.
+//
+// The hops and the decryptions make a Transcript, which Verify checks from
+// the input to the decrypted messages. The proofs are bound to the session
+// identifier of the mixnet, to the index of the hop and to its input and
+// output, so that they can't be replayed in another context.
+package mixnet
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/elgamal"
+	"go.dedis.ch/kyber/v4/encrypt/threshold"
+	"go.dedis.ch/kyber/v4/proof"
+	"go.dedis.ch/kyber/v4/proof/transcript"
+	"go.dedis.ch/kyber/v4/share"
+	"go.dedis.ch/kyber/v4/shuffle"
+)
+
+// Suite describes the functionalities needed by this package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.Encoding
+	kyber.XOFFactory
+	kyber.Random
+}
+
+const transcriptLabel = "kyber-mixnet-v1"
+
+var (
+	// ErrInvalidHop is returned when the proof of a hop doesn't verify.
+	ErrInvalidHop = errors.New("mixnet: invalid hop")
+	// ErrInvalidDecryption is returned when a decryption share is invalid
+	// or when there are not enough of them.
+	ErrInvalidDecryption = errors.New("mixnet: invalid decryption")
+)
+
+// Params are the public parameters of a mixnet, known to the servers and to
+// the verifiers.
+type Params struct {
+	// Session identifies the mix, and is bound to all the proofs.
+	Session []byte
+	// Public is the key the ciphertexts are encrypted to.
+	Public kyber.Point
+	// Servers is the number of servers, each of them doing one hop in the
+	// order of their indices.
+	Servers int
+	// Shares is the public sharing polynomial of the private key among the
+	// servers, whose shares have the indices of the servers, or nil if the
+	// mixnet doesn't decrypt its output.
+	Shares *share.PubPoly
+}
+
+// Hop is the output of a server with the proof that it is a shuffle of the
+// output of the previous hop, or of the input of the mixnet for the first
+// one.
+type Hop struct {
+	Server int
+	Output []*elgamal.Ciphertext
+	Proof  []byte
+}
+
+// DecryptionShare holds the partial decryptions of the output of the last
+// hop by a server.
+type DecryptionShare struct {
+	Server   int
+	Partials []*threshold.Partial
+}
+
+// Transcript is the public record of a mix.
+type Transcript struct {
+	Input       []*elgamal.Ciphertext
+	Hops        []*Hop
+	Decryptions []*DecryptionShare
+}
+
+// Server is a server of the mixnet.
+type Server struct {
+	suite  Suite
+	params *Params
+	index  int
+	share  *share.PriShare
+	opts   []shuffle.Option
+}
+
+// NewServer returns the server of the given index, with its share of the
+// private key if the mixnet decrypts its output. The options are given to
+// the shuffles.
+func NewServer(suite Suite, params *Params, index int, private *share.PriShare,
+	opts ...shuffle.Option) (*Server, error) {
+	if index < 0 || index >= params.Servers {
+		return nil, fmt.Errorf("mixnet: server index %d out of range", index)
+	}
+	if private != nil && int(private.I) != index {
+		return nil, errors.New("mixnet: share index differs from the server index")
+	}
+	return &Server{suite: suite, params: params, index: index, share: private, opts: opts}, nil
+}
+
+// Index returns the index of the server.
+func (s *Server) Index() int {
+	return s.index
+}
+
+// Mix shuffles and re-randomizes the ciphertexts, which are the input of the
+// mixnet for the first server and the output of the previous hop for the
+// others, and proves it.
+func (s *Server) Mix(input []*elgamal.Ciphertext) (*Hop, error) {
+	if len(input) < 2 {
+		return nil, errors.New("mixnet: can't mix less than two ciphertexts")
+	}
+	if !complete(input) {
+		return nil, errors.New("mixnet: incomplete ciphertext")
+	}
+	X, Y := split(input)
+	rand := s.suite.RandomStream()
+	Xbar, Ybar, prover := shuffle.Shuffle(s.suite, nil, s.params.Public, X, Y, rand, s.opts...)
+	hop := &Hop{Server: s.index, Output: join(Xbar, Ybar)}
+	t, err := hopTranscript(s.suite, s.params, s.index, input, hop.Output)
+	if err != nil {
+		return nil, err
+	}
+	hop.Proof, err = proof.TranscriptProve(s.suite, t, prover)
+	if err != nil {
+		return nil, err
+	}
+	return hop, nil
+}
+
+// Decrypt returns the partial decryptions of the ciphertexts, the output of
+// the last hop, with the share of the server.
+func (s *Server) Decrypt(output []*elgamal.Ciphertext) (*DecryptionShare, error) {
+	if s.share == nil {
+		return nil, errors.New("mixnet: server without a key share")
+	}
+	if !complete(output) {
+		return nil, errors.New("mixnet: incomplete ciphertext")
+	}
+	ds := &DecryptionShare{Server: s.index, Partials: make([]*threshold.Partial, len(output))}
+	for i, c := range output {
+		p, err := threshold.PartialDecrypt(s.suite, s.share, c.K)
+		if err != nil {
+			return nil, err
+		}
+		ds.Partials[i] = p
+	}
+	return ds, nil
+}
+
+// VerifyHop checks the proof that the output of the hop is a shuffle of the
+// input.
+func VerifyHop(suite Suite, params *Params, input []*elgamal.Ciphertext, hop *Hop,
+	opts ...shuffle.Option) error {
+	if hop == nil || len(hop.Output) != len(input) || len(input) < 2 {
+		return fmt.Errorf("%w: wrong number of ciphertexts", ErrInvalidHop)
+	}
+	if !complete(input) || !complete(hop.Output) {
+		return fmt.Errorf("%w: incomplete ciphertext", ErrInvalidHop)
+	}
+	t, err := hopTranscript(suite, params, hop.Server, input, hop.Output)
+	if err != nil {
+		return err
+	}
+	X, Y := split(input)
+	Xbar, Ybar := split(hop.Output)
+	verifier := shuffle.Verifier(suite, nil, params.Public, X, Y, Xbar, Ybar, opts...)
+	if err := proof.TranscriptVerify(suite, t, verifier, hop.Proof); err != nil {
+		return fmt.Errorf("%w %d: %w", ErrInvalidHop, hop.Server, err)
+	}
+	return nil
+}
+
+// Verify checks that every server did its hop, in order, and returns the
+// output of the last hop. When the mixnet decrypts its output, it also checks
+// the decryption shares and returns the decrypted messages, in which case
+// all the published partial decryptions must be valid and at least a
+// threshold of servers must have published theirs. A single invalid
+// decryption share makes it fail, which is why the transcript should only
+// hold the ones that pass VerifyDecryptionShare.
+func Verify(suite Suite, params *Params, tr *Transcript,
+	opts ...shuffle.Option) ([]*elgamal.Ciphertext, []kyber.Point, error) {
+	if params.Shares != nil && !params.Shares.Commit().Equal(params.Public) {
+		return nil, nil, errors.New("mixnet: public key differs from the shared key")
+	}
+	if len(tr.Hops) != params.Servers {
+		return nil, nil, fmt.Errorf("%w: %d hops for %d servers", ErrInvalidHop, len(tr.Hops), params.Servers)
+	}
+	cts := tr.Input
+	for i, hop := range tr.Hops {
+		if hop == nil || hop.Server != i {
+			return nil, nil, fmt.Errorf("%w: hop %d out of order", ErrInvalidHop, i)
+		}
+		if err := VerifyHop(suite, params, cts, hop, opts...); err != nil {
+			return nil, nil, err
+		}
+		cts = hop.Output
+	}
+	if params.Shares == nil {
+		return cts, nil, nil
+	}
+	msgs, err := verifyDecryptions(suite, params, cts, tr.Decryptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cts, msgs, nil
+}
+
+// VerifyDecryptionShare checks that the decryption share of a server holds a
+// valid partial decryption of each ciphertext of the output of the last hop.
+// Whoever assembles the transcript must drop the decryption shares that
+// don't pass it, see Verify.
+func VerifyDecryptionShare(suite Suite, params *Params, cts []*elgamal.Ciphertext,
+	ds *DecryptionShare) error {
+	if params.Shares == nil {
+		return errors.New("mixnet: mixnet without a shared key")
+	}
+	if !complete(cts) {
+		return errors.New("mixnet: incomplete ciphertext")
+	}
+	if err := checkDecryptionShare(params, cts, ds); err != nil {
+		return err
+	}
+	for i, p := range ds.Partials {
+		if err := threshold.VerifyPartial(suite, params.Shares, cts[i].K, p); err != nil {
+			return fmt.Errorf("%w: server %d: %w", ErrInvalidDecryption, ds.Server, err)
+		}
+	}
+	return nil
+}
+
+// checkDecryptionShare checks that the decryption share is well-formed.
+func checkDecryptionShare(params *Params, cts []*elgamal.Ciphertext, ds *DecryptionShare) error {
+	if ds == nil || ds.Server < 0 || ds.Server >= params.Servers {
+		return fmt.Errorf("%w: unexpected decryption share", ErrInvalidDecryption)
+	}
+	if len(ds.Partials) != len(cts) {
+		return fmt.Errorf("%w: server %d: wrong number of partial decryptions",
+			ErrInvalidDecryption, ds.Server)
+	}
+	for _, p := range ds.Partials {
+		if p == nil || int(p.I) != ds.Server {
+			return fmt.Errorf("%w: server %d: wrong share index", ErrInvalidDecryption, ds.Server)
+		}
+	}
+	return nil
+}
+
+func verifyDecryptions(suite Suite, params *Params, cts []*elgamal.Ciphertext,
+	decryptions []*DecryptionShare) ([]kyber.Point, error) {
+	seen := make(map[int]bool, len(decryptions))
+	partials := make([][]*threshold.Partial, len(cts))
+	for _, ds := range decryptions {
+		if err := checkDecryptionShare(params, cts, ds); err != nil {
+			return nil, err
+		}
+		if seen[ds.Server] {
+			return nil, fmt.Errorf("%w: server %d: decryption share given twice",
+				ErrInvalidDecryption, ds.Server)
+		}
+		seen[ds.Server] = true
+		for i, p := range ds.Partials {
+			partials[i] = append(partials[i], p)
+		}
+	}
+	t := params.Shares.Threshold()
+	if int64(len(seen)) < t {
+		return nil, fmt.Errorf("%w: %d decryption shares for a threshold of %d",
+			ErrInvalidDecryption, len(seen), t)
+	}
+
+	msgs := make([]kyber.Point, len(cts))
+	for i, c := range cts {
+		secret, err := threshold.CombineStrict(suite, params.Shares, c.K, partials[i],
+			uint32(t), uint32(params.Servers))
+		if err != nil {
+			return nil, fmt.Errorf("%w: ciphertext %d: %w", ErrInvalidDecryption, i, err)
+		}
+		msgs[i] = suite.Point().Sub(c.C, secret)
+	}
+	return msgs, nil
+}
+
+// hopTranscript returns the transcript binding the proof of a hop to the
+// parameters, to the index of the hop and to its input and output.
+func hopTranscript(suite Suite, params *Params, hop int, input, output []*elgamal.Ciphertext) (
+	*transcript.Transcript, error) {
+	t := transcript.New(suite, transcriptLabel)
+	t.AppendMessage("session", params.Session)
+	if err := t.AppendPoint("public", params.Public); err != nil {
+		return nil, err
+	}
+	t.AppendUint64("hop", uint64(hop))
+	t.AppendUint64("size", uint64(len(input)))
+	for _, cts := range [][]*elgamal.Ciphertext{input, output} {
+		for _, c := range cts {
+			if err := t.AppendPoint("K", c.K); err != nil {
+				return nil, err
+			}
+			if err := t.AppendPoint("C", c.C); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return t, nil
+}
+
+func complete(cts []*elgamal.Ciphertext) bool {
+	return !slices.ContainsFunc(cts, func(c *elgamal.Ciphertext) bool {
+		return c == nil || c.K == nil || c.C == nil
+	})
+}
+
+// split returns the ephemeral keys and the encrypted messages of the
+// ciphertexts, which are the pairs of the shuffles.
+func split(cts []*elgamal.Ciphertext) (X, Y []kyber.Point) {
+	X = make([]kyber.Point, len(cts))
+	Y = make([]kyber.Point, len(cts))
+	for i, c := range cts {
+		X[i], Y[i] = c.K, c.C
+	}
+	return X, Y
+}
+
+func join(X, Y []kyber.Point) []*elgamal.Ciphertext {
+	cts := make([]*elgamal.Ciphertext, len(X))
+	for i := range cts {
+		cts[i] = &elgamal.Ciphertext{K: X[i], C: Y[i]}
+	}
+	return cts
+}
@@ -0,0 +1,226 @@
+package mixnet
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/elgamal"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/shuffle"
+)
+
+var testSuite = edwards25519.NewBlakeSHA256Ed25519()
+
+func encryptMessages(t *testing.T, public kyber.Point, n int) ([]*elgamal.Ciphertext, []kyber.Point) {
+	t.Helper()
+	cts := make([]*elgamal.Ciphertext, n)
+	msgs := make([]kyber.Point, n)
+	for i := range cts {
+		msgs[i] = testSuite.Point().Pick(testSuite.RandomStream())
+		cts[i] = elgamal.Encrypt(testSuite, public, msgs[i])
+	}
+	return cts, msgs
+}
+
+// requireSameSet checks that a is a permutation of b.
+func requireSameSet(t *testing.T, a, b []kyber.Point) {
+	t.Helper()
+	str := func(ps []kyber.Point) []string {
+		s := make([]string, len(ps))
+		for i, p := range ps {
+			s[i] = p.String()
+		}
+		slices.Sort(s)
+		return s
+	}
+	require.Equal(t, str(a), str(b))
+}
+
+func TestMixnetThreshold(t *testing.T) {
+	l, private, err := NewLocal(testSuite, []byte("election"), 4, 3)
+	require.NoError(t, err)
+	require.Nil(t, private)
+	input, msgs := encryptMessages(t, l.Params.Public, 7)
+
+	tr, err := l.Run(input)
+	require.NoError(t, err)
+	require.Len(t, tr.Hops, 4)
+	require.Len(t, tr.Decryptions, 4)
+
+	out, res, err := Verify(testSuite, l.Params, tr)
+	require.NoError(t, err)
+	require.Equal(t, tr.Hops[3].Output, out)
+	requireSameSet(t, msgs, res)
+
+	// a threshold of decryption shares is enough
+	tr.Decryptions = tr.Decryptions[1:]
+	_, res2, err := Verify(testSuite, l.Params, tr)
+	require.NoError(t, err)
+	for i := range res {
+		require.True(t, res[i].Equal(res2[i]))
+	}
+
+	tr.Decryptions = tr.Decryptions[1:]
+	_, _, err = Verify(testSuite, l.Params, tr)
+	require.ErrorIs(t, err, ErrInvalidDecryption)
+}
+
+func TestMixnetWithoutDecryption(t *testing.T) {
+	l, private, err := NewLocal(testSuite, []byte("mix"), 3, 0, shuffle.WithWorkers(2))
+	require.NoError(t, err)
+	input, msgs := encryptMessages(t, l.Params.Public, 5)
+
+	tr, err := l.Run(input)
+	require.NoError(t, err)
+	require.Empty(t, tr.Decryptions)
+
+	out, res, err := Verify(testSuite, l.Params, tr, shuffle.WithWorkers(3))
+	require.NoError(t, err)
+	require.Nil(t, res)
+	dec := make([]kyber.Point, len(out))
+	for i, c := range out {
+		dec[i] = c.Decrypt(testSuite, private)
+	}
+	requireSameSet(t, msgs, dec)
+}
+
+func TestMixnetInvalid(t *testing.T) {
+	l, _, err := NewLocal(testSuite, []byte("election"), 3, 2)
+	require.NoError(t, err)
+	input, _ := encryptMessages(t, l.Params.Public, 4)
+	tr, err := l.Run(input)
+	require.NoError(t, err)
+	_, _, err = Verify(testSuite, l.Params, tr)
+	require.NoError(t, err)
+
+	tamper := func(f func(tr *Transcript)) error {
+		bad := &Transcript{
+			Input:       slices.Clone(tr.Input),
+			Hops:        make([]*Hop, len(tr.Hops)),
+			Decryptions: make([]*DecryptionShare, len(tr.Decryptions)),
+		}
+		for i, hop := range tr.Hops {
+			bad.Hops[i] = &Hop{Server: hop.Server, Output: slices.Clone(hop.Output), Proof: slices.Clone(hop.Proof)}
+		}
+		for i, ds := range tr.Decryptions {
+			bad.Decryptions[i] = &DecryptionShare{Server: ds.Server, Partials: slices.Clone(ds.Partials)}
+		}
+		f(bad)
+		_, _, err := Verify(testSuite, l.Params, bad)
+		return err
+	}
+
+	// Replacing a ciphertext of the input
+	err = tamper(func(tr *Transcript) {
+		tr.Input[0] = elgamal.Encrypt(testSuite, l.Params.Public, testSuite.Point().Base())
+	})
+	require.ErrorIs(t, err, ErrInvalidHop)
+
+	// Reordering the output of a hop
+	err = tamper(func(tr *Transcript) {
+		out := tr.Hops[1].Output
+		out[0], out[1] = out[1], out[0]
+	})
+	require.ErrorIs(t, err, ErrInvalidHop)
+
+	// Tampering with a proof
+	err = tamper(func(tr *Transcript) { tr.Hops[2].Proof[10] ^= 1 })
+	require.ErrorIs(t, err, ErrInvalidHop)
+
+	// Skipping or reordering hops
+	err = tamper(func(tr *Transcript) { tr.Hops = tr.Hops[:2] })
+	require.ErrorIs(t, err, ErrInvalidHop)
+	err = tamper(func(tr *Transcript) { tr.Hops[0], tr.Hops[1] = tr.Hops[1], tr.Hops[0] })
+	require.ErrorIs(t, err, ErrInvalidHop)
+
+	// A wrong partial decryption
+	err = tamper(func(tr *Transcript) {
+		p := *tr.Decryptions[1].Partials[2]
+		p.V = testSuite.Point().Add(p.V, testSuite.Point().Base())
+		tr.Decryptions[1].Partials[2] = &p
+	})
+	require.ErrorIs(t, err, ErrInvalidDecryption)
+
+	// Decryption shares published twice
+	err = tamper(func(tr *Transcript) { tr.Decryptions[0] = tr.Decryptions[1] })
+	require.ErrorIs(t, err, ErrInvalidDecryption)
+
+	// The proofs don't verify in another session
+	params := *l.Params
+	params.Session = []byte("other election")
+	_, _, err = Verify(testSuite, &params, tr)
+	require.ErrorIs(t, err, ErrInvalidHop)
+}
+
+func TestVerifyDecryptionShare(t *testing.T) {
+	l, _, err := NewLocal(testSuite, []byte("election"), 4, 3)
+	require.NoError(t, err)
+	input, msgs := encryptMessages(t, l.Params.Public, 3)
+	tr, err := l.Run(input)
+	require.NoError(t, err)
+	out := tr.Hops[len(tr.Hops)-1].Output
+	for _, ds := range tr.Decryptions {
+		require.NoError(t, VerifyDecryptionShare(testSuite, l.Params, out, ds))
+	}
+
+	// a server publishing a wrong partial decryption is caught, and its
+	// decryption share can be left out as long as a threshold remains
+	bad := &DecryptionShare{Server: tr.Decryptions[0].Server, Partials: slices.Clone(tr.Decryptions[0].Partials)}
+	p := *bad.Partials[1]
+	p.V = testSuite.Point().Add(p.V, testSuite.Point().Base())
+	bad.Partials[1] = &p
+	require.ErrorIs(t, VerifyDecryptionShare(testSuite, l.Params, out, bad), ErrInvalidDecryption)
+	tr.Decryptions[0] = bad
+	_, _, err = Verify(testSuite, l.Params, tr)
+	require.ErrorIs(t, err, ErrInvalidDecryption)
+	tr.Decryptions = tr.Decryptions[1:]
+	_, res, err := Verify(testSuite, l.Params, tr)
+	require.NoError(t, err)
+	requireSameSet(t, msgs, res)
+
+	// malformed decryption shares
+	ds := tr.Decryptions[0]
+	require.ErrorIs(t, VerifyDecryptionShare(testSuite, l.Params, out, nil), ErrInvalidDecryption)
+	require.ErrorIs(t, VerifyDecryptionShare(testSuite, l.Params, out,
+		&DecryptionShare{Server: 4, Partials: ds.Partials}), ErrInvalidDecryption)
+	require.ErrorIs(t, VerifyDecryptionShare(testSuite, l.Params, out,
+		&DecryptionShare{Server: ds.Server, Partials: ds.Partials[1:]}), ErrInvalidDecryption)
+	require.ErrorIs(t, VerifyDecryptionShare(testSuite, l.Params, out,
+		&DecryptionShare{Server: ds.Server + 1, Partials: ds.Partials}), ErrInvalidDecryption)
+	require.Error(t, VerifyDecryptionShare(testSuite, l.Params, []*elgamal.Ciphertext{nil, out[1], out[2]}, ds))
+
+	// without a shared key there is nothing to verify
+	params := *l.Params
+	params.Shares = nil
+	require.Error(t, VerifyDecryptionShare(testSuite, &params, out, ds))
+}
+
+func TestNewServer(t *testing.T) {
+	l, _, err := NewLocal(testSuite, nil, 3, 2)
+	require.NoError(t, err)
+	_, err = NewServer(testSuite, l.Params, 3, nil)
+	require.Error(t, err)
+	_, err = NewServer(testSuite, l.Params, 0, l.Servers[1].share)
+	require.Error(t, err)
+
+	_, err = l.Servers[0].Mix(nil)
+	require.Error(t, err)
+	server, err := NewServer(testSuite, l.Params, 0, nil)
+	require.NoError(t, err)
+	_, err = server.Decrypt(nil)
+	require.Error(t, err)
+
+	// incomplete ciphertexts are rejected instead of panicking
+	input, _ := encryptMessages(t, l.Params.Public, 2)
+	_, err = l.Servers[0].Mix([]*elgamal.Ciphertext{input[0], nil})
+	require.Error(t, err)
+	_, err = l.Servers[0].Mix([]*elgamal.Ciphertext{input[0], {K: input[1].K}})
+	require.Error(t, err)
+	_, err = l.Servers[0].Decrypt([]*elgamal.Ciphertext{nil, input[1]})
+	require.Error(t, err)
+
+	_, _, err = NewLocal(testSuite, nil, 2, 3)
+	require.Error(t, err)
+}
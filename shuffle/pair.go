@@ -17,6 +17,15 @@
 // The general PairShuffle builds on this SimpleShuffle scheme,
 // but SimpleShuffle may also be used by itself in situations
 // that satisfy its assumptions, and is more efficient.
+//
+// The BayerGroth type proves the same statement as PairShuffle
+// with the shuffle argument of Bayer and Groth,
+// whose proofs grow with the square root of the number of pairs
+// instead of linearly, at the cost of more work for the prover.
+//
+// The point multiplications of the Neff shuffles, which make most of their
+// cost, can be spread over several goroutines with the WithWorkers option or
+// the SetWorkers methods, without changing the proofs.
 package shuffle
 
 import (
@@ -89,14 +98,15 @@ type ega6 struct {
 // to pick a random permutation, compute the shuffle,
 // and compute the correctness proof.
 type PairShuffle struct {
-	grp kyber.Group
-	k   int
-	p1  ega1
-	v2  ega2
-	p3  ega3
-	v4  ega4
-	p5  ega5
-	pv6 SimpleShuffle
+	grp     kyber.Group
+	k       int
+	workers int
+	p1      ega1
+	v2      ega2
+	p3      ega3
+	v4      ega4
+	p5      ega5
+	pv6     SimpleShuffle
 }
 
 // Init creates a new PairShuffleProof instance for a k-element ElGamal pair shuffle.
@@ -123,6 +133,14 @@ func (ps *PairShuffle) Init(grp kyber.Group, k int) *PairShuffle {
 	return ps
 }
 
+// SetWorkers sets the number of goroutines computing the point
+// multiplications in Prove and Verify, see WithWorkers.
+func (ps *PairShuffle) SetWorkers(workers int) *PairShuffle {
+	ps.workers = workers
+	ps.pv6.workers = workers
+	return ps
+}
+
 // Prove returns an error if the shuffle is not correct.
 //
 //nolint:funlen
@@ -161,19 +179,28 @@ func (ps *PairShuffle) Prove(
 	p1.Gamma = grp.Point().Mul(gamma, G)
 	wbeta := grp.Scalar() // scratch
 	wbetasum := grp.Scalar().Set(tau0)
+	L1 := make([]kyber.Point, k)
+	L2 := make([]kyber.Point, k)
+	parallel(ps.workers, k, func(lo, hi int) {
+		gw := grp.Scalar() // scratch
+		wu := grp.Scalar() // scratch
+		for i := lo; i < hi; i++ {
+			p1.A[i] = grp.Point().Mul(a[i], G)
+			p1.C[i] = grp.Point().Mul(gw.Mul(gamma, a[pi[i]]), G)
+			p1.U[i] = grp.Point().Mul(u[i], G)
+			p1.W[i] = grp.Point().Mul(gw.Mul(gamma, w[i]), G)
+			L1[i] = grp.Point().Mul(wu.Sub(w[piinv[i]], u[i]), X[i])
+			L2[i] = grp.Point().Mul(wu, Y[i])
+		}
+	})
 	p1.Lambda1 = grp.Point().Null()
 	p1.Lambda2 = grp.Point().Null()
-	XY := grp.Point()  // scratch
-	wu := grp.Scalar() // scratch
 	for i := range k {
-		p1.A[i] = grp.Point().Mul(a[i], G)
-		p1.C[i] = grp.Point().Mul(z.Mul(gamma, a[pi[i]]), G)
-		p1.U[i] = grp.Point().Mul(u[i], G)
-		p1.W[i] = grp.Point().Mul(z.Mul(gamma, w[i]), G)
 		wbetasum.Add(wbetasum, wbeta.Mul(w[i], beta[pi[i]]))
-		p1.Lambda1.Add(p1.Lambda1, XY.Mul(wu.Sub(w[piinv[i]], u[i]), X[i]))
-		p1.Lambda2.Add(p1.Lambda2, XY.Mul(wu.Sub(w[piinv[i]], u[i]), Y[i]))
+		p1.Lambda1.Add(p1.Lambda1, L1[i])
+		p1.Lambda2.Add(p1.Lambda2, L2[i])
 	}
+	XY := grp.Point() // scratch
 	p1.Lambda1.Add(p1.Lambda1, XY.Mul(wbetasum, G))
 	p1.Lambda2.Add(p1.Lambda2, XY.Mul(wbetasum, H))
 	if err := ctx.Put(p1); err != nil {
@@ -186,10 +213,12 @@ func (ps *PairShuffle) Prove(
 		return err
 	}
 	B := make([]kyber.Point, k)
-	for i := range k {
-		P := grp.Point().Mul(v2.Zrho[i], G)
-		B[i] = P.Sub(P, p1.U[i])
-	}
+	parallel(ps.workers, k, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			P := grp.Point().Mul(v2.Zrho[i], G)
+			B[i] = P.Sub(P, p1.U[i])
+		}
+	})
 
 	// P step 3
 	p3 := &ps.p3
@@ -200,8 +229,12 @@ func (ps *PairShuffle) Prove(
 	d := make([]kyber.Scalar, k)
 	for i := range k {
 		d[i] = grp.Scalar().Mul(gamma, b[pi[i]])
-		p3.D[i] = grp.Point().Mul(d[i], G)
 	}
+	parallel(ps.workers, k, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			p3.D[i] = grp.Point().Mul(d[i], G)
+		}
+	})
 	if err := ctx.Put(p3); err != nil {
 		return err
 	}
@@ -259,10 +292,12 @@ func (ps *PairShuffle) Verify(
 		return err
 	}
 	B := make([]kyber.Point, k)
-	for i := range k {
-		P := grp.Point().Mul(v2.Zrho[i], G)
-		B[i] = P.Sub(P, p1.U[i])
-	}
+	parallel(ps.workers, k, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			P := grp.Point().Mul(v2.Zrho[i], G)
+			B[i] = P.Sub(P, p1.U[i])
+		}
+	})
 
 	// P step 3
 	p3 := &ps.p3
@@ -288,21 +323,33 @@ func (ps *PairShuffle) Verify(
 	}
 
 	// V step 7
+	Phi1s := make([]kyber.Point, k)
+	Phi2s := make([]kyber.Point, k)
+	valid := make([]bool, k)
+	parallel(ps.workers, k, func(lo, hi int) {
+		P := grp.Point() // scratch
+		Q := grp.Point() // scratch
+		for i := lo; i < hi; i++ {
+			Phi1s[i] = grp.Point().Mul(p5.Zsigma[i], Xbar[i]) // (31)
+			Phi1s[i].Sub(Phi1s[i], P.Mul(v2.Zrho[i], X[i]))
+			Phi2s[i] = grp.Point().Mul(p5.Zsigma[i], Ybar[i]) // (32)
+			Phi2s[i].Sub(Phi2s[i], P.Mul(v2.Zrho[i], Y[i]))
+			valid[i] = P.Mul(p5.Zsigma[i], p1.Gamma).Equal( // (33)
+				Q.Add(p1.W[i], p3.D[i]))
+		}
+	})
 	Phi1 := grp.Point().Null()
 	Phi2 := grp.Point().Null()
-	P := grp.Point() // scratch
-	Q := grp.Point() // scratch
 	for i := range k {
-		Phi1 = Phi1.Add(Phi1, P.Mul(p5.Zsigma[i], Xbar[i])) // (31)
-		Phi1 = Phi1.Sub(Phi1, P.Mul(v2.Zrho[i], X[i]))
-		Phi2 = Phi2.Add(Phi2, P.Mul(p5.Zsigma[i], Ybar[i])) // (32)
-		Phi2 = Phi2.Sub(Phi2, P.Mul(v2.Zrho[i], Y[i]))
-		if !P.Mul(p5.Zsigma[i], p1.Gamma).Equal( // (33)
-			Q.Add(p1.W[i], p3.D[i])) {
+		if !valid[i] {
 			return errors.New("invalid PairShuffleProof")
 		}
+		Phi1.Add(Phi1, Phi1s[i])
+		Phi2.Add(Phi2, Phi2s[i])
 	}
 
+	P := grp.Point()                                        // scratch
+	Q := grp.Point()                                        // scratch
 	if !P.Add(p1.Lambda1, Q.Mul(p5.Ztau, G)).Equal(Phi1) || // (34)
 		!P.Add(p1.Lambda2, Q.Mul(p5.Ztau, H)).Equal(Phi2) { // (35)
 		return errors.New("invalid PairShuffleProof")
@@ -316,15 +363,16 @@ func (ps *PairShuffle) Verify(
 // Returns (Xbar,Ybar), the shuffled and randomized pairs.
 // If g or h is nil, the standard base point is used.
 func Shuffle(group kyber.Group, G, H kyber.Point, X, Y []kyber.Point,
-	rand cipher.Stream) (xx, yy []kyber.Point, p proof.Prover) {
+	rand cipher.Stream, opts ...Option) (xx, yy []kyber.Point, p proof.Prover) {
 
 	k := len(X)
 	if k != len(Y) {
 		panic("X,Y vectors have inconsistent length")
 	}
 
+	o := getOptions(opts)
 	ps := PairShuffle{}
-	ps.Init(group, k)
+	ps.Init(group, k).SetWorkers(o.workers)
 
 	// Pick a random permutation
 	pi := make([]int, k)
@@ -347,12 +395,14 @@ func Shuffle(group kyber.Group, G, H kyber.Point, X, Y []kyber.Point,
 	// Create the output pair vectors
 	Xbar := make([]kyber.Point, k)
 	Ybar := make([]kyber.Point, k)
-	for i := range k {
-		Xbar[i] = ps.grp.Point().Mul(beta[pi[i]], G)
-		Xbar[i].Add(Xbar[i], X[pi[i]])
-		Ybar[i] = ps.grp.Point().Mul(beta[pi[i]], H)
-		Ybar[i].Add(Ybar[i], Y[pi[i]])
-	}
+	parallel(o.workers, k, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			Xbar[i] = ps.grp.Point().Mul(beta[pi[i]], G)
+			Xbar[i].Add(Xbar[i], X[pi[i]])
+			Ybar[i] = ps.grp.Point().Mul(beta[pi[i]], H)
+			Ybar[i].Add(Ybar[i], Y[pi[i]])
+		}
+	})
 
 	prover := func(ctx proof.ProverContext) error {
 		return ps.Prove(pi, G, H, beta, X, Y, rand, ctx)
@@ -367,9 +417,10 @@ func randUint64(rand cipher.Stream) uint64 {
 }
 
 // Verifier produces a Sigma-protocol verifier to check the correctness of a shuffle.
-func Verifier(group kyber.Group, G, H kyber.Point, X, Y, Xbar, Ybar []kyber.Point) proof.Verifier {
+func Verifier(group kyber.Group, G, H kyber.Point, X, Y, Xbar, Ybar []kyber.Point,
+	opts ...Option) proof.Verifier {
 	ps := PairShuffle{}
-	ps.Init(group, len(X))
+	ps.Init(group, len(X)).SetWorkers(getOptions(opts).workers)
 	verifier := func(ctx proof.VerifierContext) error {
 		return ps.Verify(G, H, X, Y, Xbar, Ybar, ctx)
 	}
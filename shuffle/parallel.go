@@ -0,0 +1,54 @@
+package shuffle
+
+import "sync"
+
+// Option configures the functions shuffling and verifying pairs and
+// sequences.
+type Option func(*options)
+
+type options struct {
+	workers int
+}
+
+// WithWorkers makes the proofs and their verification compute the point
+// multiplications on the given number of goroutines. The proofs don't depend
+// on the number of workers: with the same randomness, they are identical to
+// those computed by a single goroutine. Values smaller than 2 disable the
+// parallel execution, which is the default.
+func WithWorkers(workers int) Option {
+	return func(o *options) {
+		o.workers = workers
+	}
+}
+
+func getOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// parallel calls f on consecutive ranges [lo, hi) covering [0, n), on up to
+// workers goroutines, and returns once all the calls returned. Every index is
+// in a single range, so f can write the elements of its range without
+// synchronization.
+func parallel(workers, n int, f func(lo, hi int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		f(0, n)
+		return
+	}
+	var wg sync.WaitGroup
+	for w := range workers {
+		lo, hi := w*n/workers, (w+1)*n/workers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f(lo, hi)
+		}()
+	}
+	wg.Wait()
+}
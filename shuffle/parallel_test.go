@@ -0,0 +1,148 @@
+package shuffle
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/proof"
+	"go.dedis.ch/kyber/v4/xof/blake2xb"
+)
+
+func TestParallel(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 100} {
+		for _, workers := range []int{-1, 0, 1, 3, 8, 200} {
+			seen := make([]int32, n)
+			var calls atomic.Int32
+			parallel(workers, n, func(lo, hi int) {
+				calls.Add(1)
+				for i := lo; i < hi; i++ {
+					atomic.AddInt32(&seen[i], 1)
+				}
+			})
+			for i := range seen {
+				require.Equal(t, int32(1), seen[i], "n=%d workers=%d", n, workers)
+			}
+			require.LessOrEqual(t, int(calls.Load()), max(workers, 1))
+		}
+	}
+}
+
+func TestShuffleWorkers(t *testing.T) {
+	k := 17
+	shuffle := func(workers int) ([]kyber.Point, []kyber.Point, []byte) {
+		// Same randomness for every number of workers
+		suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+		rand := suite.RandomStream()
+		h, c := setShuffleKeyPairs(rand, suite, k)
+		x, y := elGamalEncryptPair(rand, suite, c, h, k)
+		Xbar, Ybar, prover := Shuffle(suite, nil, h, x, y, rand, WithWorkers(workers))
+		prf, err := proof.HashProve(suite, "PairShuffle", prover)
+		require.NoError(t, err)
+
+		for _, w := range []int{1, 4} {
+			verifier := Verifier(suite, nil, h, x, y, Xbar, Ybar, WithWorkers(w))
+			require.NoError(t, proof.HashVerify(suite, "PairShuffle", verifier, prf))
+		}
+		Xbar[0], Xbar[1] = Xbar[1], Xbar[0]
+		verifier := Verifier(suite, nil, h, x, y, Xbar, Ybar, WithWorkers(workers))
+		require.Error(t, proof.HashVerify(suite, "PairShuffle", verifier, prf))
+		Xbar[0], Xbar[1] = Xbar[1], Xbar[0]
+		return Xbar, Ybar, prf
+	}
+
+	Xbar, Ybar, prf := shuffle(1)
+	for _, workers := range []int{2, 3, 8, 64} {
+		Xbar2, Ybar2, prf2 := shuffle(workers)
+		require.Equal(t, Xbar, Xbar2)
+		require.Equal(t, Ybar, Ybar2)
+		require.Equal(t, prf, prf2, "workers=%d", workers)
+	}
+}
+
+func TestSequencesShuffleWorkers(t *testing.T) {
+	k, nq := 9, 3
+	shuffle := func(workers int) []byte {
+		suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+		rand := suite.RandomStream()
+		h, c := setShuffleKeyPairs(rand, suite, k)
+		X, Y := generateAndEncryptRandomSequences(rand, suite, h, c, k)
+		XX, YY, getProver := SequencesShuffle(suite, nil, h, X[:nq], Y[:nq], rand, WithWorkers(workers))
+
+		e := make([]kyber.Scalar, nq)
+		for j := range e {
+			e[j] = suite.Scalar().Pick(rand)
+		}
+		prover, err := getProver(e)
+		require.NoError(t, err)
+		prf, err := proof.HashProve(suite, "SequencesShuffle", prover)
+		require.NoError(t, err)
+
+		XXUp, YYUp, XXDown, YYDown := GetSequenceVerifiable(suite, X[:nq], Y[:nq], XX, YY, e, WithWorkers(workers))
+		verifier := Verifier(suite, nil, h, XXUp, YYUp, XXDown, YYDown, WithWorkers(workers))
+		require.NoError(t, proof.HashVerify(suite, "SequencesShuffle", verifier, prf))
+		return prf
+	}
+
+	prf := shuffle(1)
+	require.Equal(t, prf, shuffle(4))
+}
+
+func benchmarkPairShuffleWorkers(b *testing.B, k int, verify bool) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+	h, c := setShuffleKeyPairs(rand, suite, k)
+	x, y := elGamalEncryptPair(rand, suite, c, h, k)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			Xbar, Ybar, prover := Shuffle(suite, nil, h, x, y, rand, WithWorkers(workers))
+			prf, err := proof.HashProve(suite, "PairShuffle", prover)
+			require.NoError(b, err)
+			verifier := Verifier(suite, nil, h, x, y, Xbar, Ybar, WithWorkers(workers))
+
+			for b.Loop() {
+				if verify {
+					err = proof.HashVerify(suite, "PairShuffle", verifier, prf)
+				} else {
+					_, err = proof.HashProve(suite, "PairShuffle", prover)
+				}
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func BenchmarkPairShuffleProveWorkers(b *testing.B)  { benchmarkPairShuffleWorkers(b, 256, false) }
+func BenchmarkPairShuffleVerifyWorkers(b *testing.B) { benchmarkPairShuffleWorkers(b, 256, true) }
+
+func BenchmarkSequencesShuffleWorkers(b *testing.B) {
+	suite := edwards25519.NewBlakeSHA256Ed25519WithRand(blake2xb.New(nil))
+	rand := suite.RandomStream()
+	k := 128
+	h, c := setShuffleKeyPairs(rand, suite, k)
+	X, Y := generateAndEncryptRandomSequences(rand, suite, h, c, k)
+	e := make([]kyber.Scalar, len(X))
+	for j := range e {
+		e[j] = suite.Scalar().Pick(rand)
+	}
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for b.Loop() {
+				XX, YY, getProver := SequencesShuffle(suite, nil, h, X, Y, rand, WithWorkers(workers))
+				prover, err := getProver(e)
+				require.NoError(b, err)
+				prf, err := proof.HashProve(suite, "SequencesShuffle", prover)
+				require.NoError(b, err)
+
+				XXUp, YYUp, XXDown, YYDown := GetSequenceVerifiable(suite, X, Y, XX, YY, e, WithWorkers(workers))
+				verifier := Verifier(suite, nil, h, XXUp, YYUp, XXDown, YYDown, WithWorkers(workers))
+				require.NoError(b, proof.HashVerify(suite, "SequencesShuffle", verifier, prf))
+			}
+		})
+	}
+}
@@ -37,7 +37,8 @@ func SequencesShuffle(
 	group kyber.Group,
 	G, H kyber.Point,
 	X, Y [][]kyber.Point,
-	rand cipher.Stream) (xBar, yBar [][]kyber.Point, getProver func(e []kyber.Scalar) (proof.Prover, error)) {
+	rand cipher.Stream,
+	opts ...Option) (xBar, yBar [][]kyber.Point, getProver func(e []kyber.Scalar) (proof.Prover, error)) {
 
 	err := assertXY(X, Y)
 	if err != nil {
@@ -46,6 +47,7 @@ func SequencesShuffle(
 
 	NQ := len(X)
 	k := len(X[0])
+	o := getOptions(opts)
 
 	// Pick a random permutation used in ALL k ElGamal sequences. The permutation
 	// (π) of an ElGamal pair at index i always outputs to the same index
@@ -80,22 +82,25 @@ func SequencesShuffle(
 	for j := range NQ {
 		xBar[j] = make([]kyber.Point, k)
 		yBar[j] = make([]kyber.Point, k)
-
-		for i := range k {
-			xBar[j][i] = group.Point().Mul(beta[j][pi[i]], G)
-			xBar[j][i].Add(xBar[j][i], X[j][pi[i]])
-
-			yBar[j][i] = group.Point().Mul(beta[j][pi[i]], H)
-			yBar[j][i].Add(yBar[j][i], Y[j][pi[i]])
-		}
 	}
+	parallel(o.workers, k, func(lo, hi int) {
+		for j := range NQ {
+			for i := lo; i < hi; i++ {
+				xBar[j][i] = group.Point().Mul(beta[j][pi[i]], G)
+				xBar[j][i].Add(xBar[j][i], X[j][pi[i]])
+
+				yBar[j][i] = group.Point().Mul(beta[j][pi[i]], H)
+				yBar[j][i].Add(yBar[j][i], Y[j][pi[i]])
+			}
+		}
+	})
 
 	getProver = func(e []kyber.Scalar) (proof.Prover, error) {
 		// EGAR 2 (Prover) - Standard ElGamal k-shuffle proof: Knowledge of
 		// (xUp, yUp), (xDown, yDown) and e[j]
 
 		ps := PairShuffle{}
-		ps.Init(group, k)
+		ps.Init(group, k).SetWorkers(o.workers)
 
 		if len(e) != NQ {
 			return nil, fmt.Errorf("len(e) must be equal to NQ: %d != %d", len(e), NQ)
@@ -114,7 +119,7 @@ func SequencesShuffle(
 				}
 			}
 
-			XUp, YUp, _, _ := GetSequenceVerifiable(group, X, Y, xBar, yBar, e)
+			XUp, YUp, _, _ := GetSequenceVerifiable(group, X, Y, xBar, yBar, e, opts...)
 
 			return ps.Prove(pi, G, H, beta2, XUp, YUp, rand, ctx)
 		}, nil
@@ -152,8 +157,8 @@ func assertXY(X, Y [][]kyber.Point) error {
 
 // GetSequenceVerifiable returns the consolidated input and output of sequence
 // shuffling elements. Needed by the prover and verifier.
-func GetSequenceVerifiable(group kyber.Group, X, Y, Xbar, Ybar [][]kyber.Point, e []kyber.Scalar) (
-	xUp, yUp, xDown, yDown []kyber.Point) {
+func GetSequenceVerifiable(group kyber.Group, X, Y, Xbar, Ybar [][]kyber.Point, e []kyber.Scalar,
+	opts ...Option) (xUp, yUp, xDown, yDown []kyber.Point) {
 
 	// EGAR1 (Verifier) - Consolidate input and output
 	NQ := len(X)
@@ -164,27 +169,29 @@ func GetSequenceVerifiable(group kyber.Group, X, Y, Xbar, Ybar [][]kyber.Point,
 	xDown = make([]kyber.Point, k)
 	yDown = make([]kyber.Point, k)
 
-	for i := range k {
-		// No modification could be made for e[0] -> e[0] = 1 if one wanted -
-		// Remark 7 in the paper
-		xUp[i] = group.Point().Mul(e[0], X[0][i])
-		yUp[i] = group.Point().Mul(e[0], Y[0][i])
-
-		xDown[i] = group.Point().Mul(e[0], Xbar[0][i])
-		yDown[i] = group.Point().Mul(e[0], Ybar[0][i])
-
-		for j := 1; j < NQ; j++ {
-			xUp[i] = group.Point().Add(xUp[i],
-				group.Point().Mul(e[j], X[j][i]))
-			yUp[i] = group.Point().Add(yUp[i],
-				group.Point().Mul(e[j], Y[j][i]))
-
-			xDown[i] = group.Point().Add(xDown[i],
-				group.Point().Mul(e[j], Xbar[j][i]))
-			yDown[i] = group.Point().Add(yDown[i],
-				group.Point().Mul(e[j], Ybar[j][i]))
+	parallel(getOptions(opts).workers, k, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			// No modification could be made for e[0] -> e[0] = 1 if one wanted -
+			// Remark 7 in the paper
+			xUp[i] = group.Point().Mul(e[0], X[0][i])
+			yUp[i] = group.Point().Mul(e[0], Y[0][i])
+
+			xDown[i] = group.Point().Mul(e[0], Xbar[0][i])
+			yDown[i] = group.Point().Mul(e[0], Ybar[0][i])
+
+			for j := 1; j < NQ; j++ {
+				xUp[i] = group.Point().Add(xUp[i],
+					group.Point().Mul(e[j], X[j][i]))
+				yUp[i] = group.Point().Add(yUp[i],
+					group.Point().Mul(e[j], Y[j][i]))
+
+				xDown[i] = group.Point().Add(xDown[i],
+					group.Point().Mul(e[j], Xbar[j][i]))
+				yDown[i] = group.Point().Add(yDown[i],
+					group.Point().Mul(e[j], Ybar[j][i]))
+			}
 		}
-	}
+	})
 
 	return xUp, yUp, xDown, yDown
 }
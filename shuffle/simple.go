@@ -41,12 +41,13 @@ type ssa4 struct {
 // SimpleShuffle is the "Simple k-shuffle" defined in section 3 of
 // Neff, "Verifiable Mixing (Shuffling) of ElGamal Pairs", 2004.
 type SimpleShuffle struct {
-	grp kyber.Group
-	p0  ssa0
-	v1  ssa1
-	p2  ssa2
-	v3  ssa3
-	p4  ssa4
+	grp     kyber.Group
+	workers int
+	p0      ssa0
+	v1      ssa1
+	p2      ssa2
+	v3      ssa3
+	p4      ssa4
 }
 
 // Simple helper to compute G^{ab-cd} for Theta vector computation.
@@ -82,6 +83,13 @@ func (ss *SimpleShuffle) Init(grp kyber.Group, k int) *SimpleShuffle {
 	return ss
 }
 
+// SetWorkers sets the number of goroutines computing the point
+// multiplications in Prove and Verify, see WithWorkers.
+func (ss *SimpleShuffle) SetWorkers(workers int) *SimpleShuffle {
+	ss.workers = workers
+	return ss
+}
+
 // Prove the  "Simple k-shuffle" defined in section 3 of
 // Neff, "Verifiable Mixing (Shuffling) of ElGamal Pairs", 2004.
 // The Scalar vector y must be a permutation of Scalar vector x
@@ -103,10 +111,12 @@ func (ss *SimpleShuffle) Prove(g kyber.Point, gamma kyber.Scalar,
 	}
 
 	// Step 0: inputs
-	for i := range k { // (4)
-		ss.p0.X[i] = grp.Point().Mul(x[i], g)
-		ss.p0.Y[i] = grp.Point().Mul(y[i], g)
-	}
+	parallel(ss.workers, k, func(lo, hi int) {
+		for i := lo; i < hi; i++ { // (4)
+			ss.p0.X[i] = grp.Point().Mul(x[i], g)
+			ss.p0.Y[i] = grp.Point().Mul(y[i], g)
+		}
+	})
 	if err := ctx.Put(ss.p0); err != nil {
 		return err
 	}
@@ -133,16 +143,22 @@ func (ss *SimpleShuffle) Prove(g kyber.Point, gamma kyber.Scalar,
 	}
 
 	Theta := make([]kyber.Point, thlen+1)
-	Theta[0] = thenc(grp, g, nil, nil, theta[0], yhat[0])
-	for i := 1; i < k; i++ {
-		Theta[i] = thenc(grp, g, theta[i-1], xhat[i],
-			theta[i], yhat[i])
-	}
-	for i := k; i < thlen; i++ {
-		Theta[i] = thenc(grp, g, theta[i-1], gamma,
-			theta[i], nil)
-	}
-	Theta[thlen] = thenc(grp, g, theta[thlen-1], gamma, nil, nil)
+	parallel(ss.workers, thlen+1, func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			switch {
+			case i == 0:
+				Theta[i] = thenc(grp, g, nil, nil, theta[0], yhat[0])
+			case i < k:
+				Theta[i] = thenc(grp, g, theta[i-1], xhat[i],
+					theta[i], yhat[i])
+			case i < thlen:
+				Theta[i] = thenc(grp, g, theta[i-1], gamma,
+					theta[i], nil)
+			default:
+				Theta[i] = thenc(grp, g, theta[thlen-1], gamma, nil, nil)
+			}
+		}
+	})
 	ss.p2.Theta = Theta
 	if err := ctx.Put(ss.p2); err != nil {
 		return err
@@ -231,23 +247,31 @@ func (ss *SimpleShuffle) Verify(G, Gamma kyber.Point,
 		Xhat[i] = grp.Point().Add(X[i], U)
 		Yhat[i] = grp.Point().Add(Y[i], W)
 	}
-	P := grp.Point() // scratch variables
-	Q := grp.Point()
-	s := grp.Scalar()
-	good := true
-	good = good && thver(Xhat[0], Yhat[0], Theta[0], P, Q, c, alpha[0], s)
-	for i := 1; i < k; i++ {
-		good = good && thver(Xhat[i], Yhat[i], Theta[i], P, Q,
-			alpha[i-1], alpha[i], s)
-	}
-	for i := k; i < thlen; i++ {
-		good = good && thver(Gamma, G, Theta[i], P, Q,
-			alpha[i-1], alpha[i], s)
-	}
-	good = good && thver(Gamma, G, Theta[thlen], P, Q,
-		alpha[thlen-1], c, s)
-	if !good {
-		return errors.New("incorrect SimpleShuffleProof")
+	valid := make([]bool, thlen+1)
+	parallel(ss.workers, thlen+1, func(lo, hi int) {
+		P := grp.Point() // scratch variables
+		Q := grp.Point()
+		s := grp.Scalar()
+		for i := lo; i < hi; i++ {
+			switch {
+			case i == 0:
+				valid[i] = thver(Xhat[0], Yhat[0], Theta[0], P, Q, c, alpha[0], s)
+			case i < k:
+				valid[i] = thver(Xhat[i], Yhat[i], Theta[i], P, Q,
+					alpha[i-1], alpha[i], s)
+			case i < thlen:
+				valid[i] = thver(Gamma, G, Theta[i], P, Q,
+					alpha[i-1], alpha[i], s)
+			default:
+				valid[i] = thver(Gamma, G, Theta[thlen], P, Q,
+					alpha[thlen-1], c, s)
+			}
+		}
+	})
+	for _, good := range valid {
+		if !good {
+			return errors.New("incorrect SimpleShuffleProof")
+		}
 	}
 
 	return nil
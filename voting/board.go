@@ -0,0 +1,77 @@
+package voting
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.dedis.ch/kyber/v4/encrypt/elgamal"
+)
+
+// ErrInvalidResult is returned when the published result of an election
+// differs from the decrypted tally.
+var ErrInvalidResult = errors.New("voting: invalid result")
+
+// Board is the bulletin board of an election: the public record of the
+// ballots cast, of the tally shares of the trustees and of the result. A board
+// must only accept the ballots that pass VerifyBallot and the tally shares
+// that pass VerifyTallyShare, or Verify fails.
+type Board struct {
+	Election    *Election
+	Ballots     []*Ballot
+	TallyShares []*TallyShare
+	// Result is the number of votes of each choice.
+	Result []uint64
+}
+
+// Verify checks the whole board: the parameters of the election, that each
+// voter cast at most one ballot and that all the ballots are valid, that the
+// tally shares decrypt the tally of the ballots and that the result is the
+// decrypted tally. Anyone can run it, as it only needs public data.
+func Verify(suite Suite, b *Board) error {
+	e := b.Election
+	if err := checkElection(e); err != nil {
+		return err
+	}
+	voters := make(map[string]bool, len(b.Ballots))
+	for i, ballot := range b.Ballots {
+		if ballot == nil {
+			return fmt.Errorf("%w %d: missing", ErrInvalidBallot, i)
+		}
+		if voters[ballot.Voter] {
+			return fmt.Errorf("%w %d: voter %q already voted", ErrInvalidBallot, i, ballot.Voter)
+		}
+		voters[ballot.Voter] = true
+		if err := VerifyBallot(suite, e, ballot); err != nil {
+			return fmt.Errorf("ballot %d: %w", i, err)
+		}
+	}
+
+	table, err := elgamal.NewDLog(suite, uint64(len(b.Ballots)))
+	if err != nil {
+		return err
+	}
+	counts, err := CombineTally(suite, e, Tally(suite, e, b.Ballots), b.TallyShares, table)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(counts, b.Result) {
+		return fmt.Errorf("%w: %v instead of %v", ErrInvalidResult, b.Result, counts)
+	}
+	return nil
+}
+
+// checkElection checks that the parameters of the election are consistent.
+func checkElection(e *Election) error {
+	switch {
+	case e == nil:
+		return errors.New("voting: missing election")
+	case e.Choices < 1 || e.Selections < 0 || e.Selections > e.Choices:
+		return errors.New("voting: invalid number of choices or selections")
+	case e.Shares == nil || int64(e.Trustees) < e.Shares.Threshold():
+		return errors.New("voting: invalid number of trustees")
+	case !e.Shares.Commit().Equal(e.Public):
+		return errors.New("voting: public key differs from the shared key")
+	}
+	return nil
+}
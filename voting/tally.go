@@ -0,0 +1,143 @@
+package voting
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4/encrypt/elgamal"
+	"go.dedis.ch/kyber/v4/encrypt/threshold"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// ErrInvalidTally is returned when a tally share is invalid or when there
+// are not enough of them.
+var ErrInvalidTally = errors.New("voting: invalid tally")
+
+// TallyShare holds the partial decryptions of the tally by a trustee.
+type TallyShare struct {
+	Trustee  int
+	Partials []*threshold.Partial
+}
+
+// Tally returns, for each choice, the sum of the ciphertexts of the ballots,
+// which encrypts the number of votes of the choice. The ballots must have
+// been checked with VerifyBallot.
+func Tally(suite Suite, e *Election, ballots []*Ballot) []*elgamal.Ciphertext {
+	tally := make([]*elgamal.Ciphertext, e.Choices)
+	for i := range tally {
+		tally[i] = &elgamal.Ciphertext{K: suite.Point().Null(), C: suite.Point().Null()}
+		for _, b := range ballots {
+			tally[i].Add(tally[i], b.Choices[i])
+		}
+	}
+	return tally
+}
+
+// DecryptTally returns the partial decryptions of the tally with the share
+// of the private key of a trustee.
+func DecryptTally(suite Suite, e *Election, private *share.PriShare, tally []*elgamal.Ciphertext) (
+	*TallyShare, error) {
+	if err := checkElection(e); err != nil {
+		return nil, err
+	}
+	if int(private.I) >= e.Trustees {
+		return nil, fmt.Errorf("voting: trustee index %d out of range", private.I)
+	}
+	if len(tally) != e.Choices {
+		return nil, fmt.Errorf("voting: tally of %d choices instead of %d", len(tally), e.Choices)
+	}
+	ts := &TallyShare{Trustee: int(private.I), Partials: make([]*threshold.Partial, len(tally))}
+	for i, c := range tally {
+		p, err := threshold.PartialDecrypt(suite, private, c.K)
+		if err != nil {
+			return nil, err
+		}
+		ts.Partials[i] = p
+	}
+	return ts, nil
+}
+
+// VerifyTallyShare checks that the tally share of a trustee holds a valid
+// partial decryption of each choice of the tally. A board must only publish
+// the tally shares that pass it, since a single invalid one makes
+// CombineTally, and thus Verify, fail.
+func VerifyTallyShare(suite Suite, e *Election, tally []*elgamal.Ciphertext, ts *TallyShare) error {
+	if err := checkElection(e); err != nil {
+		return err
+	}
+	if len(tally) != e.Choices {
+		return fmt.Errorf("voting: tally of %d choices instead of %d", len(tally), e.Choices)
+	}
+	if err := checkTallyShare(e, tally, ts); err != nil {
+		return err
+	}
+	for i, p := range ts.Partials {
+		if err := threshold.VerifyPartial(suite, e.Shares, tally[i].K, p); err != nil {
+			return fmt.Errorf("%w: trustee %d: %w", ErrInvalidTally, ts.Trustee, err)
+		}
+	}
+	return nil
+}
+
+// checkTallyShare checks that the tally share is well-formed.
+func checkTallyShare(e *Election, tally []*elgamal.Ciphertext, ts *TallyShare) error {
+	if ts == nil || ts.Trustee < 0 || ts.Trustee >= e.Trustees {
+		return fmt.Errorf("%w: unexpected tally share", ErrInvalidTally)
+	}
+	if len(ts.Partials) != len(tally) {
+		return fmt.Errorf("%w: trustee %d: wrong number of partial decryptions",
+			ErrInvalidTally, ts.Trustee)
+	}
+	for _, p := range ts.Partials {
+		if p == nil || int(p.I) != ts.Trustee {
+			return fmt.Errorf("%w: trustee %d: wrong share index", ErrInvalidTally, ts.Trustee)
+		}
+	}
+	return nil
+}
+
+// CombineTally checks the tally shares and returns the number of votes of
+// each choice, which the table must cover. All the tally shares must be
+// valid, so that a trustee publishing an invalid one is caught, and at least
+// a threshold of trustees must have published theirs. The tally shares should
+// thus be filtered with VerifyTallyShare before being published.
+func CombineTally(suite Suite, e *Election, tally []*elgamal.Ciphertext, shares []*TallyShare,
+	table *elgamal.DLog) ([]uint64, error) {
+	if err := checkElection(e); err != nil {
+		return nil, err
+	}
+	if len(tally) != e.Choices {
+		return nil, fmt.Errorf("voting: tally of %d choices instead of %d", len(tally), e.Choices)
+	}
+	seen := make(map[int]bool, len(shares))
+	partials := make([][]*threshold.Partial, len(tally))
+	for _, ts := range shares {
+		if err := checkTallyShare(e, tally, ts); err != nil {
+			return nil, err
+		}
+		if seen[ts.Trustee] {
+			return nil, fmt.Errorf("%w: trustee %d: tally share given twice", ErrInvalidTally, ts.Trustee)
+		}
+		seen[ts.Trustee] = true
+		for i, p := range ts.Partials {
+			partials[i] = append(partials[i], p)
+		}
+	}
+	t := e.Shares.Threshold()
+	if int64(len(seen)) < t {
+		return nil, fmt.Errorf("%w: %d tally shares for a threshold of %d", ErrInvalidTally, len(seen), t)
+	}
+
+	counts := make([]uint64, len(tally))
+	for i, c := range tally {
+		secret, err := threshold.CombineStrict(suite, e.Shares, c.K, partials[i], uint32(t), uint32(e.Trustees))
+		if err != nil {
+			return nil, fmt.Errorf("%w: choice %d: %w", ErrInvalidTally, i, err)
+		}
+		counts[i], err = table.Solve(suite.Point().Sub(c.C, secret))
+		if err != nil {
+			return nil, fmt.Errorf("voting: choice %d: %w", i, err)
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,202 @@
+// Package voting implements the cryptography of elections with homomorphic
+// tallying. A ballot holds, for each of the choices of the election, an
+// exponential ElGamal encryption of 1 if the voter selects it and of 0
+// otherwise, under the public key of the election, whose private key is
+// shared among trustees, typically with a distributed key generation (see
+// kyber/share/dkg).
+//
+// Every ciphertext of a ballot comes with a disjunctive proof that it
+// encrypts 0 or 1, and the ballot with a proof that the sum of its
+// ciphertexts encrypts the number of selections of the election, so that a
+// ballot can't weigh more than one vote. The proofs are the compressed
+// NIZKs of package proof, bound to the election and to the voter.
+//
+// Summing the ciphertexts of the valid ballots gives an encryption of the
+// number of votes of each choice. At least a threshold of trustees decrypt
+// these sums with the partial decryptions and DLEQ proofs of package
+// encrypt/threshold, and the counts are recovered with a table of discrete
+// logarithms.
+//
+// A Board holds the public record of an election, and Verify lets anyone
+// check it, from the ballots to the published result.
+package voting
+
+import (
+	"errors"
+	"fmt"
+
+	"go.dedis.ch/kyber/v4"
+	"go.dedis.ch/kyber/v4/encrypt/elgamal"
+	"go.dedis.ch/kyber/v4/proof"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+// Suite describes the functionalities needed by this package.
+type Suite interface {
+	kyber.Group
+	kyber.HashFactory
+	kyber.Encoding
+	kyber.XOFFactory
+	kyber.Random
+}
+
+// ErrInvalidBallot is returned when a ballot is malformed or its proofs
+// don't verify.
+var ErrInvalidBallot = errors.New("voting: invalid ballot")
+
+// Election are the public parameters of an election. The functions of the
+// package check that they are consistent and return an error otherwise.
+type Election struct {
+	// ID identifies the election, and is bound to all the proofs.
+	ID []byte
+	// Public is the key the ballots are encrypted to.
+	Public kyber.Point
+	// Shares is the public sharing polynomial of the private key among the
+	// trustees, whose shares have indices 0 to Trustees-1.
+	Shares *share.PubPoly
+	// Trustees is the number of trustees.
+	Trustees int
+	// Choices is the number of choices of a ballot.
+	Choices int
+	// Selections is the number of choices each ballot selects.
+	Selections int
+}
+
+// Ballot is an encrypted vote.
+type Ballot struct {
+	// Voter identifies the voter, and is bound to the proofs so that a
+	// ballot can't be cast again by another voter.
+	Voter string
+	// Choices holds the encryption of 0 or 1 of each choice.
+	Choices []*elgamal.Ciphertext
+	// Proofs holds the proof that each choice encrypts 0 or 1.
+	Proofs []*proof.NIZK
+	// Sum is the proof that the sum of the choices encrypts the number of
+	// selections of the election.
+	Sum *proof.NIZK
+}
+
+// The ciphertext (K, C) encrypts 0 if K = rG and C = rX, and 1 if K = rG
+// and C - G = rX, for the public key X.
+func bitPredicate() proof.Predicate {
+	return proof.Or(
+		proof.And(proof.Rep("K", "r", "G"), proof.Rep("C", "r", "X")),
+		proof.And(proof.Rep("K", "r", "G"), proof.Rep("C1", "r", "X")),
+	)
+}
+
+// The ciphertext (K, C) encrypts k if K = rG and C - kG = rX.
+func sumPredicate() proof.Predicate {
+	return proof.And(proof.Rep("K", "r", "G"), proof.Rep("Ck", "r", "X"))
+}
+
+func bitPoints(suite Suite, e *Election, c *elgamal.Ciphertext) map[string]kyber.Point {
+	return map[string]kyber.Point{
+		"G":  suite.Point().Base(),
+		"X":  e.Public,
+		"K":  c.K,
+		"C":  c.C,
+		"C1": suite.Point().Sub(c.C, suite.Point().Base()),
+	}
+}
+
+func sumPoints(suite Suite, e *Election, c *elgamal.Ciphertext) map[string]kyber.Point {
+	k := suite.Scalar().SetInt64(int64(e.Selections))
+	return map[string]kyber.Point{
+		"G":  suite.Point().Base(),
+		"X":  e.Public,
+		"K":  c.K,
+		"Ck": suite.Point().Sub(c.C, suite.Point().Mul(k, nil)),
+	}
+}
+
+// proofContext returns the context binding a proof to the election, the
+// voter and the choice, or -1 for the proof of the sum.
+func proofContext(e *Election, voter string, choice int) string {
+	return fmt.Sprintf("kyber-voting-v1 %x %x %d", e.ID, voter, choice)
+}
+
+// NewBallot encrypts the vote of the voter, which tells for each choice
+// whether the voter selects it, and proves that it is valid.
+func NewBallot(suite Suite, e *Election, voter string, vote []bool) (*Ballot, error) {
+	if err := checkElection(e); err != nil {
+		return nil, err
+	}
+	if len(vote) != e.Choices {
+		return nil, fmt.Errorf("voting: %d choices instead of %d", len(vote), e.Choices)
+	}
+	selected := 0
+	for _, v := range vote {
+		if v {
+			selected++
+		}
+	}
+	if selected != e.Selections {
+		return nil, fmt.Errorf("voting: %d selections instead of %d", selected, e.Selections)
+	}
+
+	b := &Ballot{
+		Voter:   voter,
+		Choices: make([]*elgamal.Ciphertext, e.Choices),
+		Proofs:  make([]*proof.NIZK, e.Choices),
+	}
+	rand := suite.RandomStream()
+	sum := &elgamal.Ciphertext{K: suite.Point().Null(), C: suite.Point().Null()}
+	rSum := suite.Scalar().Zero()
+	for i, v := range vote {
+		r := suite.Scalar().Pick(rand)
+		m := suite.Scalar().Zero()
+		if v {
+			m.One()
+		}
+		c := elgamal.EncryptWith(suite, e.Public, suite.Point().Mul(m, nil), r)
+
+		pred := bitPredicate()
+		choice := map[proof.Predicate]int{pred: 0}
+		if v {
+			choice[pred] = 1
+		}
+		nizk, err := proof.NewNIZK(suite, proofContext(e, voter, i), pred,
+			map[string]kyber.Scalar{"r": r}, bitPoints(suite, e, c), choice)
+		if err != nil {
+			return nil, err
+		}
+		b.Choices[i], b.Proofs[i] = c, nizk
+		sum.Add(sum, c)
+		rSum.Add(rSum, r)
+	}
+
+	nizk, err := proof.NewNIZK(suite, proofContext(e, voter, -1), sumPredicate(),
+		map[string]kyber.Scalar{"r": rSum}, sumPoints(suite, e, sum), nil)
+	if err != nil {
+		return nil, err
+	}
+	b.Sum = nizk
+	return b, nil
+}
+
+// VerifyBallot checks that the ballot is well-formed and that its proofs
+// verify.
+func VerifyBallot(suite Suite, e *Election, b *Ballot) error {
+	if err := checkElection(e); err != nil {
+		return err
+	}
+	if b == nil || len(b.Choices) != e.Choices || len(b.Proofs) != e.Choices || b.Sum == nil {
+		return fmt.Errorf("%w: malformed", ErrInvalidBallot)
+	}
+	sum := &elgamal.Ciphertext{K: suite.Point().Null(), C: suite.Point().Null()}
+	for i, c := range b.Choices {
+		if c == nil || c.K == nil || c.C == nil || b.Proofs[i] == nil {
+			return fmt.Errorf("%w: malformed choice %d", ErrInvalidBallot, i)
+		}
+		err := b.Proofs[i].Verify(suite, proofContext(e, b.Voter, i), bitPredicate(), bitPoints(suite, e, c))
+		if err != nil {
+			return fmt.Errorf("%w: choice %d: %w", ErrInvalidBallot, i, err)
+		}
+		sum.Add(sum, c)
+	}
+	if err := b.Sum.Verify(suite, proofContext(e, b.Voter, -1), sumPredicate(), sumPoints(suite, e, sum)); err != nil {
+		return fmt.Errorf("%w: sum: %w", ErrInvalidBallot, err)
+	}
+	return nil
+}
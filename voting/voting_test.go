@@ -0,0 +1,289 @@
+package voting
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v4/encrypt/elgamal"
+	"go.dedis.ch/kyber/v4/group/edwards25519"
+	"go.dedis.ch/kyber/v4/share"
+)
+
+var testSuite = edwards25519.NewBlakeSHA256Ed25519()
+
+// newElection returns an election whose private key is shared among n
+// trustees with a threshold t by a trusted dealer.
+func newElection(t testing.TB, n, threshold, choices, selections int) (*Election, []*share.PriShare) {
+	t.Helper()
+	rand := testSuite.RandomStream()
+	poly := share.NewPriPoly(testSuite, uint32(threshold), nil, rand)
+	pub := poly.Commit(nil)
+	e := &Election{
+		ID:         []byte("election"),
+		Public:     pub.Commit(),
+		Shares:     pub,
+		Trustees:   n,
+		Choices:    choices,
+		Selections: selections,
+	}
+	require.NoError(t, checkElection(e))
+	return e, poly.Shares(uint32(n))
+}
+
+// newBoard casts the votes and has the given trustees decrypt the tally.
+func newBoard(t *testing.T, e *Election, shares []*share.PriShare, votes [][]bool) *Board {
+	t.Helper()
+	b := &Board{Election: e}
+	for i, vote := range votes {
+		ballot, err := NewBallot(testSuite, e, fmt.Sprintf("voter %d", i), vote)
+		require.NoError(t, err)
+		require.NoError(t, VerifyBallot(testSuite, e, ballot))
+		b.Ballots = append(b.Ballots, ballot)
+	}
+	tally := Tally(testSuite, e, b.Ballots)
+	for _, s := range shares {
+		ts, err := DecryptTally(testSuite, e, s, tally)
+		require.NoError(t, err)
+		b.TallyShares = append(b.TallyShares, ts)
+	}
+	table, err := elgamal.NewDLog(testSuite, uint64(len(votes)))
+	require.NoError(t, err)
+	b.Result, err = CombineTally(testSuite, e, tally, b.TallyShares, table)
+	require.NoError(t, err)
+	return b
+}
+
+func TestVoting(t *testing.T) {
+	e, shares := newElection(t, 5, 3, 4, 2)
+	votes := [][]bool{
+		{true, true, false, false},
+		{false, true, true, false},
+		{false, true, false, true},
+		{true, false, false, true},
+		{false, true, true, false},
+	}
+	b := newBoard(t, e, shares, votes)
+	require.Equal(t, []uint64{2, 4, 2, 2}, b.Result)
+	require.NoError(t, Verify(testSuite, b))
+
+	// A threshold of trustees is enough
+	b.TallyShares = b.TallyShares[2:]
+	require.NoError(t, Verify(testSuite, b))
+	b.TallyShares = b.TallyShares[1:]
+	require.ErrorIs(t, Verify(testSuite, b), ErrInvalidTally)
+}
+
+func TestVotingSingleChoice(t *testing.T) {
+	e, shares := newElection(t, 3, 2, 2, 1)
+	b := newBoard(t, e, shares[1:], [][]bool{{true, false}, {false, true}, {true, false}})
+	require.Equal(t, []uint64{2, 1}, b.Result)
+	require.NoError(t, Verify(testSuite, b))
+}
+
+func TestNewBallot(t *testing.T) {
+	e, _ := newElection(t, 3, 2, 3, 1)
+	_, err := NewBallot(testSuite, e, "voter", []bool{true, false})
+	require.Error(t, err)
+	_, err = NewBallot(testSuite, e, "voter", []bool{true, true, false})
+	require.Error(t, err)
+	_, err = NewBallot(testSuite, e, "voter", []bool{false, false, false})
+	require.Error(t, err)
+}
+
+func TestInvalidBallot(t *testing.T) {
+	e, _ := newElection(t, 3, 2, 3, 1)
+	ballot, err := NewBallot(testSuite, e, "alice", []bool{false, true, false})
+	require.NoError(t, err)
+
+	tamper := func(f func(b *Ballot)) error {
+		bad := &Ballot{
+			Voter:   ballot.Voter,
+			Choices: slices.Clone(ballot.Choices),
+			Proofs:  slices.Clone(ballot.Proofs),
+			Sum:     ballot.Sum,
+		}
+		f(bad)
+		return VerifyBallot(testSuite, e, bad)
+	}
+	require.NoError(t, tamper(func(*Ballot) {}))
+
+	// Another voter can't cast the ballot
+	err = tamper(func(b *Ballot) { b.Voter = "bob" })
+	require.ErrorIs(t, err, ErrInvalidBallot)
+
+	// The proofs are bound to their choice
+	err = tamper(func(b *Ballot) {
+		b.Choices[0], b.Choices[1] = b.Choices[1], b.Choices[0]
+		b.Proofs[0], b.Proofs[1] = b.Proofs[1], b.Proofs[0]
+	})
+	require.ErrorIs(t, err, ErrInvalidBallot)
+
+	// A choice encrypting 2
+	err = tamper(func(b *Ballot) {
+		one := elgamal.EncryptExp(testSuite, e.Public, testSuite.Scalar().One())
+		b.Choices[1] = new(elgamal.Ciphertext).Add(b.Choices[1], one)
+	})
+	require.ErrorIs(t, err, ErrInvalidBallot)
+
+	// A ballot selecting too many choices
+	e2 := *e
+	e2.Selections = 2
+	twice, err := NewBallot(testSuite, &e2, "alice", []bool{true, true, false})
+	require.NoError(t, err)
+	require.NoError(t, VerifyBallot(testSuite, &e2, twice))
+	require.ErrorIs(t, VerifyBallot(testSuite, e, twice), ErrInvalidBallot)
+
+	// The proofs are bound to the election
+	e2 = *e
+	e2.ID = []byte("other election")
+	require.ErrorIs(t, VerifyBallot(testSuite, &e2, ballot), ErrInvalidBallot)
+
+	// Malformed ballots
+	err = tamper(func(b *Ballot) { b.Proofs = b.Proofs[1:] })
+	require.ErrorIs(t, err, ErrInvalidBallot)
+	err = tamper(func(b *Ballot) { b.Choices[2] = nil })
+	require.ErrorIs(t, err, ErrInvalidBallot)
+	require.ErrorIs(t, VerifyBallot(testSuite, e, nil), ErrInvalidBallot)
+}
+
+func TestVerifyBoard(t *testing.T) {
+	e, shares := newElection(t, 4, 3, 3, 1)
+	votes := [][]bool{{true, false, false}, {false, false, true}, {true, false, false}}
+	b := newBoard(t, e, shares, votes)
+	require.NoError(t, Verify(testSuite, b))
+
+	tamper := func(f func(b *Board)) error {
+		bad := &Board{
+			Election:    b.Election,
+			Ballots:     slices.Clone(b.Ballots),
+			TallyShares: make([]*TallyShare, len(b.TallyShares)),
+			Result:      slices.Clone(b.Result),
+		}
+		for i, ts := range b.TallyShares {
+			bad.TallyShares[i] = &TallyShare{Trustee: ts.Trustee, Partials: slices.Clone(ts.Partials)}
+		}
+		f(bad)
+		return Verify(testSuite, bad)
+	}
+
+	// A wrong result
+	err := tamper(func(b *Board) { b.Result[0], b.Result[1] = b.Result[1], b.Result[0] })
+	require.ErrorIs(t, err, ErrInvalidResult)
+
+	// A voter casting two ballots
+	err = tamper(func(b *Board) { b.Ballots = append(b.Ballots, b.Ballots[0]) })
+	require.ErrorIs(t, err, ErrInvalidBallot)
+
+	// A ballot removed after the tally
+	err = tamper(func(b *Board) { b.Ballots = b.Ballots[1:] })
+	require.ErrorIs(t, err, ErrInvalidTally)
+
+	// A wrong partial decryption
+	err = tamper(func(b *Board) {
+		p := *b.TallyShares[1].Partials[2]
+		p.V = testSuite.Point().Add(p.V, testSuite.Point().Base())
+		b.TallyShares[1].Partials[2] = &p
+	})
+	require.ErrorIs(t, err, ErrInvalidTally)
+
+	// Tally shares published twice
+	err = tamper(func(b *Board) { b.TallyShares[0] = b.TallyShares[1] })
+	require.ErrorIs(t, err, ErrInvalidTally)
+
+	// A public key that isn't shared among the trustees
+	e2 := *e
+	e2.Public = testSuite.Point().Pick(testSuite.RandomStream())
+	err = tamper(func(b *Board) { b.Election = &e2 })
+	require.Error(t, err)
+}
+
+func TestVerifyTallyShare(t *testing.T) {
+	e, shares := newElection(t, 4, 3, 3, 1)
+	votes := [][]bool{{true, false, false}, {false, false, true}}
+	b := newBoard(t, e, shares, votes)
+	tally := Tally(testSuite, e, b.Ballots)
+	for _, ts := range b.TallyShares {
+		require.NoError(t, VerifyTallyShare(testSuite, e, tally, ts))
+	}
+
+	// a trustee publishing a wrong partial decryption is caught, and the
+	// board can leave its tally share out as long as a threshold remains
+	bad := &TallyShare{Trustee: b.TallyShares[0].Trustee, Partials: slices.Clone(b.TallyShares[0].Partials)}
+	p := *bad.Partials[1]
+	p.V = testSuite.Point().Add(p.V, testSuite.Point().Base())
+	bad.Partials[1] = &p
+	require.ErrorIs(t, VerifyTallyShare(testSuite, e, tally, bad), ErrInvalidTally)
+	b.TallyShares[0] = bad
+	require.ErrorIs(t, Verify(testSuite, b), ErrInvalidTally)
+	b.TallyShares = b.TallyShares[1:]
+	require.NoError(t, Verify(testSuite, b))
+
+	// malformed tally shares
+	ts := b.TallyShares[0]
+	require.ErrorIs(t, VerifyTallyShare(testSuite, e, tally, nil), ErrInvalidTally)
+	require.ErrorIs(t, VerifyTallyShare(testSuite, e, tally,
+		&TallyShare{Trustee: 4, Partials: ts.Partials}), ErrInvalidTally)
+	require.ErrorIs(t, VerifyTallyShare(testSuite, e, tally,
+		&TallyShare{Trustee: ts.Trustee, Partials: ts.Partials[1:]}), ErrInvalidTally)
+	require.ErrorIs(t, VerifyTallyShare(testSuite, e, tally,
+		&TallyShare{Trustee: ts.Trustee + 1, Partials: ts.Partials}), ErrInvalidTally)
+	require.Error(t, VerifyTallyShare(testSuite, e, tally[1:], ts))
+}
+
+func TestInvalidElection(t *testing.T) {
+	e, shares := newElection(t, 3, 2, 3, 1)
+	vote := []bool{false, true, false}
+	ballot, err := NewBallot(testSuite, e, "voter", vote)
+	require.NoError(t, err)
+	tally := Tally(testSuite, e, []*Ballot{ballot})
+	ts, err := DecryptTally(testSuite, e, shares[0], tally)
+	require.NoError(t, err)
+	table, err := elgamal.NewDLog(testSuite, 1)
+	require.NoError(t, err)
+
+	for _, f := range []func(e *Election){
+		func(e *Election) { e.Shares = nil },
+		func(e *Election) { e.Choices = 0 },
+		func(e *Election) { e.Selections = -1 },
+		func(e *Election) { e.Selections = 4 },
+		func(e *Election) { e.Trustees = 1 },
+		func(e *Election) { e.Public = testSuite.Point().Base() },
+	} {
+		bad := *e
+		f(&bad)
+		_, err := NewBallot(testSuite, &bad, "voter", vote)
+		require.Error(t, err)
+		require.Error(t, VerifyBallot(testSuite, &bad, ballot))
+		_, err = DecryptTally(testSuite, &bad, shares[0], tally)
+		require.Error(t, err)
+		_, err = CombineTally(testSuite, &bad, tally, []*TallyShare{ts}, table)
+		require.Error(t, err)
+	}
+	_, err = NewBallot(testSuite, nil, "voter", vote)
+	require.Error(t, err)
+	_, err = CombineTally(testSuite, nil, tally, []*TallyShare{ts}, table)
+	require.Error(t, err)
+}
+
+func BenchmarkNewBallot(b *testing.B) {
+	e, _ := newElection(b, 3, 2, 10, 1)
+	vote := make([]bool, e.Choices)
+	vote[3] = true
+	for b.Loop() {
+		_, err := NewBallot(testSuite, e, "voter", vote)
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkVerifyBallot(b *testing.B) {
+	e, _ := newElection(b, 3, 2, 10, 1)
+	vote := make([]bool, e.Choices)
+	vote[3] = true
+	ballot, err := NewBallot(testSuite, e, "voter", vote)
+	require.NoError(b, err)
+	for b.Loop() {
+		require.NoError(b, VerifyBallot(testSuite, e, ballot))
+	}
+}